@@ -1,9 +1,13 @@
 package main
 
 import (
+	"flag"
+	"queue/core/domain/enum"
 	"queue/core/infra/servers"
 )
 
 func main() {
-	servers.Run()
+	mode := flag.String("mode", "", "publisher, subscriber ou all (padrão: RUN_MODE ou all)")
+	flag.Parse()
+	servers.RunWithMode(enum.RunModeEnum(*mode))
 }
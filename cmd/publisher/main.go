@@ -0,0 +1,11 @@
+package main
+
+import (
+	"queue/core/domain/enum"
+	"queue/core/infra/servers"
+)
+
+// Sobe apenas a API de publicação, o agendador e a API de dead-letter.
+func main() {
+	servers.RunWithMode(enum.PublisherMode)
+}
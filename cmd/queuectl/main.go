@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"queue/core/application/publish/service"
+	"queue/core/domain/enum"
+	"queue/core/infra/config"
+	"queue/core/infra/logging"
+	"queue/core/infra/queuectl"
+	"queue/core/infra/servers"
+	"syscall"
+)
+
+// Ferramenta de linha de comando para publicar, acompanhar e reenviar mensagens usando o broker
+// configurado no .env. Os resultados saem em JSON na saída padrão e os logs em Stderr.
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		return exitCode(queuectl.NewCLI(nil, nil).Run(ctx, args))
+	}
+
+	servers.LoadEnv()
+	cfg := config.LoadConfig()
+	logging.Setup(cfg.Log)
+	if cfg.Broker.Type == string(enum.MemoryBroker) {
+		fmt.Fprintln(os.Stderr, "o queuectl precisa de um broker compartilhado; BROKER=memory não é suportado")
+		return 1
+	}
+	if servers.PubSubProjectID(cfg) == "" {
+		fmt.Fprintln(os.Stderr, "PROJECT_ID é obrigatório para o broker pubsub")
+		return 1
+	}
+
+	client := servers.NewBrokerClient(cfg)
+	defer client.Close()
+	publisher, _ := publishservice.NewPublishService(client)
+	publisher.WithTopicRegistry(servers.NewTopicRegistry(cfg))
+
+	return exitCode(queuectl.NewCLI(client, publisher).Run(ctx, args))
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, queuectl.ErrFailures):
+		return 1
+	default:
+		fmt.Fprintln(os.Stderr, "erro:", err)
+		return 1
+	}
+}
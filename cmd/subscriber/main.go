@@ -0,0 +1,11 @@
+package main
+
+import (
+	"queue/core/domain/enum"
+	"queue/core/infra/servers"
+)
+
+// Sobe apenas os receptores das assinaturas, com o health check por HTTP.
+func main() {
+	servers.RunWithMode(enum.SubscriberMode)
+}
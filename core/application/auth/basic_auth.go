@@ -9,20 +9,32 @@ import (
 	"strings"
 )
 
-func BasicAuthMiddleware(username, password string) gin.HandlerFunc {
+// BasicAuthMiddleware exige as credenciais em todas as rotas, exceto "/" e as que começam com um dos
+// prefixos em skip, que são públicas ou protegidas por credenciais próprias. Falhas de autenticação respondem 401.
+func BasicAuthMiddleware(username, password string, skip ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.URL.Path == "/" {
+		if c.Request.URL.Path == "/" || skipped(c.Request.URL.Path, skip) {
 			c.Next()
 			return
 		}
 		if err := authenticate(c, username, password); err != nil {
-			response.Error(c, http.StatusBadRequest, err.Error(), nil)
+			c.Header("WWW-Authenticate", `Basic realm="queue"`)
+			response.Error(c, http.StatusUnauthorized, err.Error(), nil)
 			return
 		}
 		c.Next()
 	}
 }
 
+func skipped(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 func authenticate(c *gin.Context, validUsername, validPassword string) error {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
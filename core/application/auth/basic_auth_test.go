@@ -45,7 +45,7 @@ func TestBasicAuthMiddleware(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		var responseBody map[string]interface{}
 		_ = json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.Equal(t, "Autenticação de headers requerida", responseBody["message"])
@@ -58,7 +58,7 @@ func TestBasicAuthMiddleware(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		var responseBody map[string]interface{}
 		_ = json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.Equal(t, "Autenticação de headers inválida", responseBody["message"])
@@ -71,7 +71,7 @@ func TestBasicAuthMiddleware(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		var responseBody map[string]interface{}
 		_ = json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.Equal(t, "Falha ao decodificar o header", responseBody["message"])
@@ -85,9 +85,31 @@ func TestBasicAuthMiddleware(t *testing.T) {
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, `Basic realm="queue"`, w.Header().Get("WWW-Authenticate"))
 		var responseBody map[string]interface{}
 		_ = json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.Equal(t, "Credenciais inválidas", responseBody["message"])
 	})
 }
+
+func TestBasicAuthMiddleware_IgnoraPrefixosComCredenciaisProprias(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(auth.BasicAuthMiddleware("user", "pass", "/admin/dead-letter"))
+	for _, path := range []string{"/admin/dead-letter", "/admin/dead-letter/1", "/admin/dead-letters", "/publish"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+
+	expected := map[string]int{
+		"/admin/dead-letter":   http.StatusOK,
+		"/admin/dead-letter/1": http.StatusOK,
+		"/admin/dead-letters":  http.StatusUnauthorized,
+		"/publish":             http.StatusUnauthorized,
+	}
+	for path, code := range expected {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+}
@@ -0,0 +1,104 @@
+package deadlettercontroller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"queue/core/application/dead_letter/dto"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/response"
+	"strconv"
+)
+
+type DeadLetterController struct {
+	service *deadletterservice.DeadLetterService
+}
+
+func NewDeadLetterController(service *deadletterservice.DeadLetterService) *DeadLetterController {
+	return &DeadLetterController{
+		service: service,
+	}
+}
+
+// List lê as mensagens do dead-letter sem removê-las. Aceita os filtros subscription, topic, error e limit na query.
+func (ctrl *DeadLetterController) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil || limit < 0 || limit > deadletterservice.MaxLimit {
+		response.Error(c, http.StatusBadRequest, "O limite deve estar entre 1 e 1000", nil)
+		return
+	}
+	filter := deadletterservice.Filter{
+		Subscription: c.Query("subscription"),
+		Topic:        c.Query("topic"),
+		Error:        c.Query("error"),
+	}
+	messages, err := ctrl.service.List(c.Request.Context(), filter, limit)
+	if err != nil {
+		ctrl.handleError(c, "Erro ao listar mensagens do dead-letter", err)
+		return
+	}
+	list := make([]deadletterdto.DeadLetterMessageDto, len(messages))
+	for i, msg := range messages {
+		list[i] = deadletterdto.FromDeadLetterMessage(msg)
+	}
+	response.Success(c, response.IList[deadletterdto.DeadLetterMessageDto]{
+		List:      list,
+		TotalRows: int64(len(list)),
+	}, 200, "Mensagens do dead-letter")
+}
+
+func (ctrl *DeadLetterController) Get(c *gin.Context) {
+	msg, err := ctrl.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		ctrl.handleError(c, "Erro ao buscar mensagem do dead-letter", err)
+		return
+	}
+	if msg == nil {
+		response.Error(c, http.StatusNotFound, "Mensagem não encontrada no dead-letter", nil)
+		return
+	}
+	response.Success(c, deadletterdto.FromDeadLetterMessage(*msg), 200, "Mensagem do dead-letter")
+}
+
+func (ctrl *DeadLetterController) Replay(c *gin.Context) {
+	dto, ok := ctrl.selection(c)
+	if !ok {
+		return
+	}
+	result, err := ctrl.service.Replay(c.Request.Context(), dto.Selection(), dto.Limit)
+	if err != nil {
+		ctrl.handleError(c, "Erro ao republicar mensagens do dead-letter", err)
+		return
+	}
+	response.Success(c, deadletterdto.FromReplayResult(result), 200, "Mensagens republicadas")
+}
+
+func (ctrl *DeadLetterController) Purge(c *gin.Context) {
+	dto, ok := ctrl.selection(c)
+	if !ok {
+		return
+	}
+	purged, err := ctrl.service.Purge(c.Request.Context(), dto.Selection(), dto.Limit)
+	if err != nil {
+		ctrl.handleError(c, "Erro ao remover mensagens do dead-letter", err)
+		return
+	}
+	response.Success(c, deadletterdto.PurgeOutputDto{Purged: purged}, 200, "Mensagens removidas")
+}
+
+func (ctrl *DeadLetterController) selection(c *gin.Context) (*deadletterdto.SelectionDto, bool) {
+	dto := c.MustGet("dto").(*deadletterdto.SelectionDto)
+	if dto.IsEmpty() {
+		response.Error(c, http.StatusBadRequest, "Informe ids, filter ou all", nil)
+		return nil, false
+	}
+	return dto, true
+}
+
+func (ctrl *DeadLetterController) handleError(c *gin.Context, message string, err error) {
+	if errors.Is(err, deadletterservice.ErrSubscriptionNotFound) {
+		response.Error(c, http.StatusServiceUnavailable, err.Error(), nil)
+		return
+	}
+	response.Error(c, http.StatusInternalServerError, message, err.Error())
+}
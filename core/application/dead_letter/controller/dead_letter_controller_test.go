@@ -0,0 +1,109 @@
+package deadlettercontroller_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/application/dead_letter/controller"
+	"queue/core/application/dead_letter/dto"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/response"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"queue/core/infra/memory_broker"
+	"queue/core/infra/middleware"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	cfg := &types.Config{
+		Broker: types.BrokerConfig{Subscriptions: []types.SubscriptionConfig{
+			{ID: "dlq-sub", Topic: "dlq"},
+			{ID: "notifications-sub", Topic: "notifications"},
+		}},
+		DeadLetter: types.DeadLetterConfig{Topic: "dlq", Subscription: "dlq-sub"},
+	}
+	broker, err := memorybroker.NewMemoryBrokerFromConfig(cfg.Broker)
+	assert.NoError(t, err)
+	_, err = deadletter.NewDeadLetterQueue(broker, cfg.DeadLetter).Failed(context.Background(), types.DeliveryFailure{
+		SubscriptionID: "notifications-sub",
+		Topic:          "notifications",
+		Message:        &types.Message{ID: "42", Data: []byte(`{"userId":1}`)},
+		Err:            errors.New("status 400: inválido"),
+		Permanent:      true,
+	})
+	assert.NoError(t, err)
+
+	svc := deadletterservice.NewDeadLetterService(broker, cfg)
+	svc.Wait = 50 * time.Millisecond
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := deadlettercontroller.NewDeadLetterController(svc)
+	r.GET("/admin/dead-letter", ctrl.List)
+	r.GET("/admin/dead-letter/:id", ctrl.Get)
+	r.POST("/admin/dead-letter/replay", classtransformer.UseClassTransformerMiddleware(&deadletterdto.SelectionDto{}), ctrl.Replay)
+	r.POST("/admin/dead-letter/purge", classtransformer.UseClassTransformerMiddleware(&deadletterdto.SelectionDto{}), ctrl.Purge)
+	return r
+}
+
+func TestDeadLetterController_ListEGet(t *testing.T) {
+	router := setupRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letter?subscription=notifications-sub", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var list response.HttpResponse[response.IList[deadletterdto.DeadLetterMessageDto]]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(1), list.Data.TotalRows)
+	msg := list.Data.List[0]
+	assert.Equal(t, "42", msg.OriginalMessageID)
+	assert.Equal(t, "status 400: inválido", msg.Error)
+	assert.JSONEq(t, `{"userId":1}`, string(msg.Data))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letter/"+msg.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"topic":"notifications"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letter/nao-existe", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letter?limit=5000", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeadLetterController_ReplayEPurge(t *testing.T) {
+	router := setupRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/dead-letter/replay", bytes.NewBufferString(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Informe ids, filter ou all")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/dead-letter/replay", bytes.NewBufferString(`{"all":true,"limit":2000}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "O limite deve ser de no máximo 1000 mensagens.")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/dead-letter/replay", bytes.NewBufferString(`{"filter":{"error":"400"}}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var replay response.HttpResponse[deadletterdto.ReplayOutputDto]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &replay))
+	assert.Equal(t, 1, replay.Data.Replayed)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/dead-letter/purge", bytes.NewBufferString(`{"all":true}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"purged":0`)
+}
@@ -0,0 +1,41 @@
+package deadlettermodule
+
+import (
+	"github.com/gin-gonic/gin"
+	"queue/core/application/auth"
+	"queue/core/application/dead_letter/controller"
+	"queue/core/application/dead_letter/dto"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/middleware"
+)
+
+// RoutePrefix agrupa as rotas de administração do dead-letter, protegidas por DeadLetterConfig.AdminAuth
+// em vez das credenciais de publicação.
+const RoutePrefix = "/admin/dead-letter"
+
+type DeadLetterModule struct {
+	Service    *deadletterservice.DeadLetterService
+	Controller *deadlettercontroller.DeadLetterController
+	adminAuth  types.BasicAuthConfig
+}
+
+func NewDeadLetterModule(client interfaces.IPubSubClient, cfg *types.Config) *DeadLetterModule {
+	service := deadletterservice.NewDeadLetterService(client, cfg)
+	return &DeadLetterModule{
+		Service:    service,
+		Controller: deadlettercontroller.NewDeadLetterController(service),
+		adminAuth:  cfg.DeadLetter.AdminAuth,
+	}
+}
+
+func (m *DeadLetterModule) RegisterRoutes(router *gin.Engine) {
+	deadLetterGroup := router.Group(RoutePrefix, auth.BasicAuthMiddleware(m.adminAuth.Username, m.adminAuth.Password))
+	{
+		deadLetterGroup.GET("", m.Controller.List)
+		deadLetterGroup.GET("/:id", m.Controller.Get)
+		deadLetterGroup.POST("/replay", classtransformer.UseClassTransformerMiddleware(&deadletterdto.SelectionDto{}), m.Controller.Replay)
+		deadLetterGroup.POST("/purge", classtransformer.UseClassTransformerMiddleware(&deadletterdto.SelectionDto{}), m.Controller.Purge)
+	}
+}
@@ -0,0 +1,38 @@
+package deadlettermodule_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"queue/core/application/dead_letter"
+	"queue/core/domain/types"
+	"queue/core/infra/mock"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterModule_RegisterRoutes(t *testing.T) {
+	cfg := &types.Config{DeadLetter: types.DeadLetterConfig{
+		Topic:        "dlq",
+		Subscription: "dlq-sub",
+		AdminAuth:    types.BasicAuthConfig{Username: "ops", Password: "456"},
+	}}
+	module := deadlettermodule.NewDeadLetterModule(mock.NewMockPubSubClientAdapter("outra-sub"), cfg)
+	assert.NotNil(t, module.Service)
+	assert.NotNil(t, module.Controller)
+
+	router := gin.Default()
+	module.RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/dead-letter", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dead-letter", nil)
+	req.SetBasicAuth("ops", "456")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "assinatura do dead-letter não encontrada")
+}
@@ -0,0 +1,102 @@
+package deadletterdto
+
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"queue/core/infra/validation"
+	"time"
+)
+
+var selectionErrors = map[string]string{
+	"messageIDs.max": "São permitidos no máximo 1000 IDs.",
+	"limit.min":      "O limite deve ser maior que zero.",
+	"limit.max":      "O limite deve ser de no máximo 1000 mensagens.",
+}
+
+type DeadLetterMessageDto struct {
+	ID                string            `json:"id"`
+	OriginalMessageID string            `json:"originalMessageId"`
+	Subscription      string            `json:"subscription"`
+	Topic             string            `json:"topic"`
+	Error             string            `json:"error"`
+	Attempts          int               `json:"attempts"`
+	FailedAt          time.Time         `json:"failedAt"`
+	PublishTime       time.Time         `json:"publishTime"`
+	Attributes        map[string]string `json:"attributes,omitempty"`
+	Data              json.RawMessage   `json:"data"`
+}
+
+func FromDeadLetterMessage(msg types.DeadLetterMessage) DeadLetterMessageDto {
+	data := json.RawMessage(msg.Message.Data)
+	if !json.Valid(msg.Message.Data) {
+		data, _ = json.Marshal(string(msg.Message.Data))
+	}
+	return DeadLetterMessageDto{
+		ID:                msg.Message.ID,
+		OriginalMessageID: msg.OriginalID,
+		Subscription:      msg.SubscriptionID,
+		Topic:             msg.Topic,
+		Error:             msg.Error,
+		Attempts:          msg.Attempts,
+		FailedAt:          msg.FailedAt,
+		PublishTime:       msg.Message.PublishTime,
+		Attributes:        deadletter.OriginalAttributes(msg.Message.Attributes),
+		Data:              data,
+	}
+}
+
+type FilterDto struct {
+	Subscription string `json:"subscription,omitempty"`
+	Topic        string `json:"topic,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+func (f FilterDto) Filter() deadletterservice.Filter {
+	return deadletterservice.Filter{Subscription: f.Subscription, Topic: f.Topic, Error: f.Error}
+}
+
+// SelectionDto escolhe as mensagens de replay e purge. É preciso informar ids, filter ou all.
+type SelectionDto struct {
+	MessageIDs []string  `json:"ids,omitempty" validate:"omitempty,max=1000"`
+	Filter     FilterDto `json:"filter"`
+	All        bool      `json:"all,omitempty"`
+	Limit      int       `json:"limit,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+func (dto *SelectionDto) Selection() deadletterservice.Selection {
+	return deadletterservice.Selection{IDs: dto.MessageIDs, Filter: dto.Filter.Filter(), All: dto.All}
+}
+
+// IsEmpty indica que nenhuma mensagem foi escolhida, evitando um replay ou purge de tudo por engano.
+func (dto *SelectionDto) IsEmpty() bool {
+	return len(dto.MessageIDs) == 0 && dto.Filter.Filter().IsEmpty() && !dto.All
+}
+
+func (dto *SelectionDto) ValidationMessages(ve validator.ValidationErrors) map[string]string {
+	return validation.ValidationMessages(ve, selectionErrors)
+}
+
+type ReplayFailureDto struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+type ReplayOutputDto struct {
+	Replayed int                `json:"replayed"`
+	Failed   []ReplayFailureDto `json:"failed"`
+}
+
+func FromReplayResult(result deadletterservice.ReplayResult) ReplayOutputDto {
+	failed := make([]ReplayFailureDto, len(result.Failed))
+	for i, f := range result.Failed {
+		failed[i] = ReplayFailureDto{ID: f.ID, Reason: f.Reason}
+	}
+	return ReplayOutputDto{Replayed: result.Replayed, Failed: failed}
+}
+
+type PurgeOutputDto struct {
+	Purged int `json:"purged"`
+}
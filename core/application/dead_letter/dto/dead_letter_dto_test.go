@@ -0,0 +1,42 @@
+package deadletterdto_test
+
+import (
+	"queue/core/application/dead_letter/dto"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromDeadLetterMessage(t *testing.T) {
+	failedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	dto := deadletterdto.FromDeadLetterMessage(types.DeadLetterMessage{
+		Message: &types.Message{ID: "dlq-1", Data: []byte("texto"), Attributes: map[string]string{
+			"origem":                  "crm",
+			deadletter.ErrorAttribute: "falha",
+		}},
+		SubscriptionID: "notifications-sub",
+		Error:          "falha",
+		Attempts:       3,
+		FailedAt:       failedAt,
+		OriginalID:     "42",
+	})
+	assert.Equal(t, "dlq-1", dto.ID)
+	assert.Equal(t, "42", dto.OriginalMessageID)
+	assert.Equal(t, 3, dto.Attempts)
+	assert.Equal(t, map[string]string{"origem": "crm"}, dto.Attributes)
+	assert.Equal(t, `"texto"`, string(dto.Data))
+}
+
+func TestSelectionDto(t *testing.T) {
+	assert.True(t, (&deadletterdto.SelectionDto{}).IsEmpty())
+	assert.False(t, (&deadletterdto.SelectionDto{All: true}).IsEmpty())
+	assert.False(t, (&deadletterdto.SelectionDto{MessageIDs: []string{"1"}}).IsEmpty())
+
+	dto := &deadletterdto.SelectionDto{Filter: deadletterdto.FilterDto{Topic: "notifications"}}
+	assert.False(t, dto.IsEmpty())
+	assert.Equal(t, deadletterservice.Selection{Filter: deadletterservice.Filter{Topic: "notifications"}}, dto.Selection())
+}
@@ -0,0 +1,307 @@
+package deadletterservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/api/iterator"
+	"log/slog"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+	// defaultWait é quanto tempo sem novas mensagens encerra a leitura do dead-letter.
+	defaultWait = 2 * time.Second
+)
+
+var ErrSubscriptionNotFound = errors.New("assinatura do dead-letter não encontrada")
+
+// Filter seleciona mensagens do dead-letter; campos vazios não restringem e Error busca por trecho.
+type Filter struct {
+	Subscription string
+	Topic        string
+	Error        string
+}
+
+func (f Filter) IsEmpty() bool {
+	return f.Subscription == "" && f.Topic == "" && f.Error == ""
+}
+
+func (f Filter) Matches(msg types.DeadLetterMessage) bool {
+	return (f.Subscription == "" || msg.SubscriptionID == f.Subscription) &&
+		(f.Topic == "" || msg.Topic == f.Topic) &&
+		(f.Error == "" || strings.Contains(msg.Error, f.Error))
+}
+
+// Selection indica as mensagens afetadas por replay ou purge: IDs explícitos, filtro ou todas.
+type Selection struct {
+	IDs    []string
+	Filter Filter
+	All    bool
+}
+
+func (s Selection) matches(msg types.DeadLetterMessage) bool {
+	if len(s.IDs) > 0 {
+		for _, id := range s.IDs {
+			if msg.Message.ID == id {
+				return s.Filter.Matches(msg)
+			}
+		}
+		return false
+	}
+	return s.All || (!s.Filter.IsEmpty() && s.Filter.Matches(msg))
+}
+
+type ReplayFailure struct {
+	ID     string
+	Reason string
+}
+
+type ReplayResult struct {
+	Replayed int
+	Failed   []ReplayFailure
+}
+
+// DeadLetterService lê a assinatura do dead-letter sem consumir as mensagens, republicando-as
+// no tópico de origem ou removendo-as sob demanda.
+type DeadLetterService struct {
+	Client       interfaces.IPubSubClient
+	Subscription string
+	// Topics resolve o tópico de origem das mensagens que não puderam ser decodificadas, a partir da assinatura.
+	Topics map[string]string
+	Wait   time.Duration
+	// mu serializa as operações, já que leituras simultâneas dividiriam as mensagens entre si.
+	mu sync.Mutex
+}
+
+func NewDeadLetterService(client interfaces.IPubSubClient, cfg *types.Config) *DeadLetterService {
+	topics := map[string]string{}
+	for _, sub := range cfg.Broker.Subscriptions {
+		topics[sub.ID] = sub.Topic
+	}
+	return &DeadLetterService{
+		Client:       client,
+		Subscription: cfg.DeadLetter.Subscription,
+		Topics:       topics,
+		Wait:         defaultWait,
+	}
+}
+
+// List retorna até limit mensagens que atendem ao filtro, devolvendo todas à assinatura.
+func (s *DeadLetterService) List(ctx context.Context, filter Filter, limit int) ([]types.DeadLetterMessage, error) {
+	var found []types.DeadLetterMessage
+	err := s.scan(ctx, limit, func(messages []types.DeadLetterMessage) map[string]bool {
+		for _, msg := range messages {
+			if filter.Matches(msg) {
+				found = append(found, msg)
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// Get procura a mensagem pelo ID dentro das primeiras MaxLimit mensagens do dead-letter.
+func (s *DeadLetterService) Get(ctx context.Context, id string) (*types.DeadLetterMessage, error) {
+	var found *types.DeadLetterMessage
+	err := s.scan(ctx, MaxLimit, func(messages []types.DeadLetterMessage) map[string]bool {
+		for i := range messages {
+			if messages[i].Message.ID == id {
+				found = &messages[i]
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// Replay republica as mensagens selecionadas no tópico de origem, sem os atributos do dead-letter,
+// e só as confirma no dead-letter depois que a publicação for aceita.
+func (s *DeadLetterService) Replay(ctx context.Context, selection Selection, limit int) (ReplayResult, error) {
+	var result ReplayResult
+	err := s.scan(ctx, limit, func(messages []types.DeadLetterMessage) map[string]bool {
+		ack := map[string]bool{}
+		for _, msg := range messages {
+			if !selection.matches(msg) {
+				continue
+			}
+			if err := s.republish(ctx, msg); err != nil {
+				result.Failed = append(result.Failed, ReplayFailure{ID: msg.Message.ID, Reason: err.Error()})
+				continue
+			}
+			ack[msg.Message.ID] = true
+			result.Replayed++
+		}
+		return ack
+	})
+	if result.Replayed > 0 {
+		slog.InfoContext(ctx, "Mensagens do dead-letter republicadas", "count", result.Replayed)
+	}
+	return result, err
+}
+
+// Purge confirma as mensagens selecionadas, removendo-as do dead-letter em definitivo.
+func (s *DeadLetterService) Purge(ctx context.Context, selection Selection, limit int) (int, error) {
+	purged := 0
+	err := s.scan(ctx, limit, func(messages []types.DeadLetterMessage) map[string]bool {
+		ack := map[string]bool{}
+		for _, msg := range messages {
+			if selection.matches(msg) {
+				ack[msg.Message.ID] = true
+				purged++
+			}
+		}
+		return ack
+	})
+	if purged > 0 {
+		slog.InfoContext(ctx, "Mensagens removidas do dead-letter", "count", purged)
+	}
+	return purged, err
+}
+
+func (s *DeadLetterService) republish(ctx context.Context, msg types.DeadLetterMessage) error {
+	topicID := msg.Topic
+	if topicID == "" {
+		topicID = s.Topics[msg.SubscriptionID]
+	}
+	if topicID == "" {
+		return errors.New("tópico de origem desconhecido")
+	}
+	topic := s.Client.Topic(topicID)
+	defer topic.Stop()
+	_, err := topic.Publish(ctx, &types.Message{
+		Data:       msg.Message.Data,
+		Attributes: deadletter.OriginalAttributes(msg.Message.Attributes),
+	}).Get(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao publicar no tópico %s: %w", topicID, err)
+	}
+	return nil
+}
+
+// scan recebe até limit mensagens, mantendo cada uma pendente até que todas tenham sido lidas,
+// para que uma mensagem devolvida não seja entregue de novo na mesma leitura. A leitura termina
+// ao atingir o limite ou após Wait sem novas mensagens. decide retorna os IDs a confirmar;
+// as demais são devolvidas com Nack.
+func (s *DeadLetterService) scan(ctx context.Context, limit int, decide func([]types.DeadLetterMessage) map[string]bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit = normalizeLimit(limit)
+	sub, err := s.subscription(ctx)
+	if err != nil {
+		return err
+	}
+	if configurable, ok := sub.(interfaces.IReceiveSettingsAware); ok {
+		configurable.SetReceiveSettings(types.ReceiveSettings{MaxOutstandingMessages: limit})
+	}
+
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		messages []types.DeadLetterMessage
+		seen     = map[string]bool{}
+		closed   bool
+		ack      map[string]bool
+	)
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 1)
+	received := make(chan error, 1)
+	go func() {
+		received <- sub.Receive(receiveCtx, func(_ context.Context, msg *types.Message) {
+			mu.Lock()
+			if closed || seen[msg.ID] || len(messages) >= limit {
+				mu.Unlock()
+				msg.Nack()
+				return
+			}
+			seen[msg.ID] = true
+			messages = append(messages, deadletter.Parse(msg))
+			mu.Unlock()
+			select {
+			case arrived <- struct{}{}:
+			default:
+			}
+			<-release
+			if ack[msg.ID] {
+				msg.Ack()
+				return
+			}
+			msg.Nack()
+		})
+	}()
+
+	var receiveErr error
+	finished := false
+	idle := time.NewTimer(s.wait())
+	defer idle.Stop()
+collect:
+	for {
+		select {
+		case <-arrived:
+			mu.Lock()
+			full := len(messages) >= limit
+			mu.Unlock()
+			if full {
+				break collect
+			}
+			idle.Reset(s.wait())
+		case <-idle.C:
+			break collect
+		case <-ctx.Done():
+			break collect
+		case receiveErr = <-received:
+			finished = true
+			break collect
+		}
+	}
+
+	mu.Lock()
+	closed = true
+	collected := messages
+	mu.Unlock()
+	cancel()
+	ack = decide(collected)
+	close(release)
+	if !finished {
+		receiveErr = <-received
+	}
+	return receiveErr
+}
+
+func (s *DeadLetterService) subscription(ctx context.Context) (interfaces.ISubscription, error) {
+	it := s.Client.Subscriptions(ctx)
+	for {
+		sub, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil, ErrSubscriptionNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sub.ID() == s.Subscription {
+			return sub, nil
+		}
+	}
+}
+
+func (s *DeadLetterService) wait() time.Duration {
+	if s.Wait <= 0 {
+		return defaultWait
+	}
+	return s.Wait
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return min(limit, MaxLimit)
+}
@@ -0,0 +1,161 @@
+package deadletterservice_test
+
+import (
+	"context"
+	"errors"
+	"queue/core/application/dead_letter/service"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"queue/core/infra/memory_broker"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/iterator"
+)
+
+// setup cria o broker com o dead-letter populado com uma mensagem por assinatura de origem.
+func setup(t *testing.T) (*memorybroker.MemoryBroker, *deadletterservice.DeadLetterService) {
+	t.Helper()
+	cfg := &types.Config{
+		Broker: types.BrokerConfig{Subscriptions: []types.SubscriptionConfig{
+			{ID: "dlq-sub", Topic: "dlq"},
+			{ID: "notifications-sub", Topic: "notifications"},
+			{ID: "audit-sub", Topic: "audit"},
+		}},
+		DeadLetter: types.DeadLetterConfig{Topic: "dlq", Subscription: "dlq-sub", MaxDeliveryAttempts: 1},
+	}
+	broker, err := memorybroker.NewMemoryBrokerFromConfig(cfg.Broker)
+	assert.NoError(t, err)
+	queue := deadletter.NewDeadLetterQueue(broker, cfg.DeadLetter)
+	failures := []types.DeliveryFailure{
+		{SubscriptionID: "notifications-sub", Topic: "notifications", Err: errors.New("status 400: inválido")},
+		{SubscriptionID: "audit-sub", Err: errors.New("json inválido")},
+	}
+	for i, f := range failures {
+		f.Message = &types.Message{ID: string(rune('a' + i)), Data: []byte(`{"n":1}`), Attributes: map[string]string{"origem": "crm"}}
+		_, err := queue.Failed(context.Background(), f)
+		assert.NoError(t, err)
+	}
+	service := deadletterservice.NewDeadLetterService(broker, cfg)
+	service.Wait = 50 * time.Millisecond
+	return broker, service
+}
+
+// drain lê todas as mensagens disponíveis na assinatura informada, confirmando-as.
+func drain(t *testing.T, broker *memorybroker.MemoryBroker, id string) []*types.Message {
+	t.Helper()
+	it := broker.Subscriptions(context.Background())
+	for {
+		sub, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			t.Fatalf("assinatura %s não encontrada", id)
+		}
+		if sub.ID() != id {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		var received []*types.Message
+		_ = sub.Receive(ctx, func(_ context.Context, msg *types.Message) {
+			received = append(received, msg)
+			msg.Ack()
+		})
+		return received
+	}
+}
+
+func TestDeadLetterService_ListNaoConsome(t *testing.T) {
+	_, service := setup(t)
+	for i := 0; i < 2; i++ {
+		messages, err := service.List(context.Background(), deadletterservice.Filter{}, 0)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+	}
+
+	messages, err := service.List(context.Background(), deadletterservice.Filter{Error: "400"}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "notifications-sub", messages[0].SubscriptionID)
+	assert.Equal(t, "a", messages[0].OriginalID)
+
+	messages, err = service.List(context.Background(), deadletterservice.Filter{}, 1)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestDeadLetterService_Get(t *testing.T) {
+	_, service := setup(t)
+	messages, _ := service.List(context.Background(), deadletterservice.Filter{Subscription: "audit-sub"}, 0)
+
+	found, err := service.Get(context.Background(), messages[0].Message.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "json inválido", found.Error)
+
+	found, err = service.Get(context.Background(), "nao-existe")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestDeadLetterService_ReplayPorFiltro(t *testing.T) {
+	broker, service := setup(t)
+	assert.NoError(t, broker.CreateSubscription("notifications-replay", "notifications"))
+
+	result, err := service.Replay(context.Background(), deadletterservice.Selection{
+		Filter: deadletterservice.Filter{Topic: "notifications"},
+	}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Replayed)
+	assert.Empty(t, result.Failed)
+
+	replayed := drain(t, broker, "notifications-replay")
+	assert.Len(t, replayed, 1)
+	assert.Equal(t, map[string]string{"origem": "crm"}, replayed[0].Attributes)
+
+	remaining, _ := service.List(context.Background(), deadletterservice.Filter{}, 0)
+	assert.Len(t, remaining, 1)
+	assert.Equal(t, "audit-sub", remaining[0].SubscriptionID)
+}
+
+func TestDeadLetterService_ReplayUsaTopicoDaAssinatura(t *testing.T) {
+	broker, service := setup(t)
+	messages, _ := service.List(context.Background(), deadletterservice.Filter{Subscription: "audit-sub"}, 0)
+
+	result, err := service.Replay(context.Background(), deadletterservice.Selection{IDs: []string{messages[0].Message.ID}}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Replayed)
+	assert.Len(t, drain(t, broker, "audit-sub"), 1)
+}
+
+func TestDeadLetterService_ReplayComFalhaMantemMensagem(t *testing.T) {
+	_, service := setup(t)
+	service.Topics = map[string]string{}
+
+	result, err := service.Replay(context.Background(), deadletterservice.Selection{
+		Filter: deadletterservice.Filter{Subscription: "audit-sub"},
+	}, 0)
+	assert.NoError(t, err)
+	assert.Zero(t, result.Replayed)
+	assert.Len(t, result.Failed, 1)
+	assert.Equal(t, "tópico de origem desconhecido", result.Failed[0].Reason)
+
+	remaining, _ := service.List(context.Background(), deadletterservice.Filter{}, 0)
+	assert.Len(t, remaining, 2)
+}
+
+func TestDeadLetterService_Purge(t *testing.T) {
+	_, service := setup(t)
+	purged, err := service.Purge(context.Background(), deadletterservice.Selection{All: true}, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+
+	remaining, _ := service.List(context.Background(), deadletterservice.Filter{}, 0)
+	assert.Empty(t, remaining)
+}
+
+func TestDeadLetterService_AssinaturaInexistente(t *testing.T) {
+	_, service := setup(t)
+	service.Subscription = "nao-existe"
+	_, err := service.List(context.Background(), deadletterservice.Filter{}, 0)
+	assert.ErrorIs(t, err, deadletterservice.ErrSubscriptionNotFound)
+}
@@ -3,14 +3,30 @@ package health_check_controller
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/monitor"
+	"queue/core/domain/response"
 )
 
-type HealthCheckController struct{}
+type HealthCheckController struct {
+	Monitor interfaces.ISubscriptionMonitor
+}
 
-func NewHealthCheckController() *HealthCheckController {
-	return &HealthCheckController{}
+func NewHealthCheckController(monitor interfaces.ISubscriptionMonitor) *HealthCheckController {
+	return &HealthCheckController{Monitor: monitor}
 }
 
 func (h *HealthCheckController) HealthCheck(c *gin.Context) {
 	c.String(http.StatusOK, "Queue on Air!")
 }
+
+// Health responde sempre 200, já que a publicação continua disponível com assinaturas em falha;
+// o campo status indica se o consumo está degradado.
+func (h *HealthCheckController) Health(c *gin.Context) {
+	health := h.Monitor.Health()
+	message := "Assinaturas operando normalmente"
+	if health.Status != monitor.HealthOK {
+		message = "Há assinaturas com falha"
+	}
+	response.Success(c, health, http.StatusOK, message)
+}
@@ -1,21 +1,47 @@
 package health_check_controller_test
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
 	"queue/core/application/health_check/controller"
+	"queue/core/domain/monitor"
 	"testing"
 )
 
 func TestHealthCheck(t *testing.T) {
 	r := gin.Default()
-	controller := health_check_controller.NewHealthCheckController()
+	controller := health_check_controller.NewHealthCheckController(monitor.NewMonitor())
 	r.GET("/", controller.HealthCheck)
+
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
+
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Contains(t, w.Body.String(), `Queue on Air!`)
 }
+
+func TestHealth(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.ReceiverStarted("sub-a")
+	r := gin.Default()
+	controller := health_check_controller.NewHealthCheckController(m)
+	r.GET("/health", controller.Health)
+
+	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+	assert.Contains(t, w.Body.String(), `"id":"sub-a","state":"running"`)
+
+	m.ReceiverFailed("sub-a", errors.New("conexão perdida"))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"degraded"`)
+	assert.Contains(t, w.Body.String(), `"lastReceiverError":"conexão perdida"`)
+}
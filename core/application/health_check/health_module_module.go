@@ -3,14 +3,16 @@ package healthcheckmodule
 import (
 	"github.com/gin-gonic/gin"
 	"queue/core/application/health_check/controller"
+	"queue/core/domain/interfaces"
 )
 
 type HealthCheckModule struct {
 	Controller *health_check_controller.HealthCheckController
 }
 
-func NewHealthCheckModule() *HealthCheckModule {
-	ctrl := health_check_controller.NewHealthCheckController()
+// NewHealthCheckModule expõe o estado das assinaturas em /health a partir do monitor informado.
+func NewHealthCheckModule(monitor interfaces.ISubscriptionMonitor) *HealthCheckModule {
+	ctrl := health_check_controller.NewHealthCheckController(monitor)
 	return &HealthCheckModule{
 		Controller: ctrl,
 	}
@@ -18,4 +20,5 @@ func NewHealthCheckModule() *HealthCheckModule {
 
 func (m *HealthCheckModule) RegisterRoutes(router *gin.Engine) {
 	router.GET("/", m.Controller.HealthCheck)
+	router.GET("/health", m.Controller.Health)
 }
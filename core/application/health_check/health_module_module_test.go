@@ -6,12 +6,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"queue/core/application/health_check"
+	"queue/core/domain/monitor"
 	"testing"
 )
 
 func TestHealthCheckModule_RegisterRoutes(t *testing.T) {
 	r := gin.Default()
-	module := healthcheckmodule.NewHealthCheckModule()
+	module := healthcheckmodule.NewHealthCheckModule(monitor.NewMonitor())
 	module.RegisterRoutes(r)
 	req, _ := http.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
@@ -20,3 +21,14 @@ func TestHealthCheckModule_RegisterRoutes(t *testing.T) {
 	expectedBody := `Queue on Air!`
 	assert.Equal(t, expectedBody, w.Body.String(), "A resposta não corresponde à esperada")
 }
+
+func TestHealthCheckModule_RegisterRoutes_Health(t *testing.T) {
+	r := gin.Default()
+	healthcheckmodule.NewHealthCheckModule(monitor.NewMonitor()).RegisterRoutes(r)
+
+	req, _ := http.NewRequest(http.MethodGet, "/health", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"ok"`)
+}
@@ -0,0 +1,17 @@
+package metricsmodule
+
+import (
+	"github.com/gin-gonic/gin"
+	"queue/core/infra/metrics"
+)
+
+type MetricsModule struct{}
+
+// NewMetricsModule expõe em /metrics as métricas do publisher e do dispatcher no formato do Prometheus.
+func NewMetricsModule() *MetricsModule {
+	return &MetricsModule{}
+}
+
+func (m *MetricsModule) RegisterRoutes(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
@@ -0,0 +1,21 @@
+package metricsmodule_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/application/metrics"
+	"testing"
+)
+
+func TestMetricsModule_RegisterRoutes(t *testing.T) {
+	r := gin.Default()
+	metricsmodule.NewMetricsModule().RegisterRoutes(r)
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
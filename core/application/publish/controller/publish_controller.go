@@ -1,11 +1,21 @@
 package publishcontroller
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"queue/core/application/publish/dto"
 	"queue/core/application/publish/service"
 	"queue/core/domain/response"
+	"queue/core/domain/structs"
+	"queue/core/infra/middleware"
+)
+
+// IdempotencyKeyHeader permite informar a chave de idempotência sem alterar o envelope.
+const (
+	IdempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
 )
 
 type PublishController struct {
@@ -29,10 +39,86 @@ func (ctrl *PublishController) Publish(c *gin.Context) {
 		response.Error(c, http.StatusBadRequest, "Formato de mensagem inválido", nil)
 		return
 	}
-	_, err := ctrl.service.Publish(*messageDto)
+	if messageDto.Meta.IdempotencyKey == "" {
+		messageDto.Meta.IdempotencyKey = c.GetHeader(IdempotencyKeyHeader)
+	}
+	if len(messageDto.Meta.IdempotencyKey) > maxIdempotencyKeyLength {
+		response.Error(c, http.StatusBadRequest, "A chave de idempotência deve ter no máximo 255 caracteres.", nil)
+		return
+	}
+	output, err := ctrl.service.Publish(c.Request.Context(), *messageDto)
 	if err != nil {
+		var ve *structs.ValidationMessagesError
+		if errors.As(err, &ve) {
+			response.Error(c, http.StatusBadRequest, err.Error(), ve.Messages)
+			return
+		}
 		response.Error(c, http.StatusBadRequest, err.Error(), err)
 		return
 	}
-	response.Success(c, true, 200, "Mensagem publicada com sucesso")
+	if output.Replayed {
+		response.Success(c, output, 200, "Mensagem já publicada anteriormente")
+		return
+	}
+	response.Success(c, output, 200, "Mensagem publicada com sucesso")
+}
+
+func (ctrl *PublishController) PublishBatch(c *gin.Context) {
+	raw, exists := c.Get("dtos")
+	if !exists {
+		response.Error(c, http.StatusBadRequest, "Formato de lote inválido", nil)
+		return
+	}
+	items, ok := raw.([]classtransformer.BatchItem)
+	if !ok {
+		response.Error(c, http.StatusBadRequest, "Formato de lote inválido", nil)
+		return
+	}
+	if len(items) == 0 {
+		response.Error(c, http.StatusBadRequest, "O lote não pode ser vazio", nil)
+		return
+	}
+	if len(items) > publishdto.MaxBatchSize {
+		response.Error(c, http.StatusBadRequest, fmt.Sprintf("O lote deve ter no máximo %d mensagens", publishdto.MaxBatchSize), nil)
+		return
+	}
+
+	output := publishdto.BatchOutputDto{Results: make([]publishdto.BatchItemResultDto, len(items))}
+	valid := make([]publishdto.InputDto, 0, len(items))
+	validIndexes := make([]int, 0, len(items))
+	for _, item := range items {
+		messageDto, ok := item.Dto.(*publishdto.InputDto)
+		if item.Err != nil || !ok {
+			output.Results[item.Index] = invalidBatchItem(item)
+			continue
+		}
+		valid = append(valid, *messageDto)
+		validIndexes = append(validIndexes, item.Index)
+	}
+
+	for i, result := range ctrl.service.PublishBatch(c.Request.Context(), valid) {
+		result.Index = validIndexes[i]
+		output.Results[result.Index] = result
+	}
+	for _, result := range output.Results {
+		if result.Error != "" {
+			output.Failed++
+		} else {
+			output.Published++
+		}
+	}
+	response.Success(c, output, 200, "Lote processado")
+}
+
+func invalidBatchItem(item classtransformer.BatchItem) publishdto.BatchItemResultDto {
+	result := publishdto.BatchItemResultDto{Index: item.Index, Error: "Formato de mensagem inválido"}
+	if item.Err == nil {
+		return result
+	}
+	result.Error = item.Err.Error()
+	var ve *structs.ValidationMessagesError
+	if errors.As(item.Err, &ve) {
+		result.Details = ve.Messages
+	}
+	return result
 }
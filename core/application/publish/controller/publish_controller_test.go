@@ -11,8 +11,13 @@ import (
 	"queue/core/application/publish/controller"
 	"queue/core/application/publish/dto"
 	"queue/core/application/publish/service"
+	"queue/core/infra/idempotency_store"
+	"queue/core/infra/middleware"
 	"queue/core/infra/mock"
+	"queue/core/infra/topic_registry"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Configuração simplificada do router, "injeção" manual no contexto da request do "dto".
@@ -48,7 +53,7 @@ func TestPublishController(t *testing.T) {
 				Data: map[string]string{"foo": "bar"},
 			},
 			wantStatus:  http.StatusOK,
-			wantContent: "Mensagem publicada com sucesso",
+			wantContent: `"messageId":"mocked-message-id"`,
 		},
 		{
 			name:      "erro no publish",
@@ -95,3 +100,113 @@ func TestPublishController(t *testing.T) {
 		assert.Contains(t, w.Body.String(), "Formato de mensagem inválido")
 	})
 }
+
+func setupBatchRouter(srv publishservice.PublishService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.Default()
+	ctrl := publishcontroller.NewPublishController(srv)
+	r.POST("/publish/batch", classtransformer.UseClassTransformerBatchMiddleware(&publishdto.InputDto{}), ctrl.PublishBatch)
+	return r
+}
+
+func TestPublishController_PublishBatch(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(mock.NewMockPubSubClientAdapter())
+	router := setupBatchRouter(*svc)
+
+	body := `[
+		{"meta":{"topic":"ok"},"data":{"foo":"bar"}},
+		{"meta":{"topic":""},"data":{"foo":"bar"}},
+		{"meta":{"topic":"fail"},"data":{"foo":"bar"}}
+	]`
+	req, _ := http.NewRequest(http.MethodPost, "/publish/batch", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data publishdto.BatchOutputDto `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Published)
+	assert.Equal(t, 2, resp.Data.Failed)
+	assert.Equal(t, "mocked-message-id", resp.Data.Results[0].MessageID)
+	assert.Equal(t, 1, resp.Data.Results[1].Index)
+	assert.Equal(t, "O tópico é obrigatório.", resp.Data.Results[1].Error)
+	assert.Equal(t, []string{"O tópico é obrigatório."}, resp.Data.Results[1].Details)
+	assert.Empty(t, resp.Data.Results[1].MessageID)
+	assert.Equal(t, 2, resp.Data.Results[2].Index)
+	assert.Equal(t, "erro simulado", resp.Data.Results[2].Error)
+}
+
+func TestPublishController_PublishBatch_LoteInvalido(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(mock.NewMockPubSubClientAdapter())
+	router := setupBatchRouter(*svc)
+
+	tooMany := make([]publishdto.InputDto, publishdto.MaxBatchSize+1)
+	tooManyBody, _ := json.Marshal(tooMany)
+	cases := map[string]struct {
+		body        string
+		wantContent string
+	}{
+		"lote vazio":        {body: `[]`, wantContent: "O lote não pode ser vazio"},
+		"lote muito grande": {body: string(tooManyBody), wantContent: "no máximo"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/publish/batch", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tc.wantContent)
+		})
+	}
+}
+
+func TestPublishController_IdempotencyKeyHeader(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(mock.NewMockPubSubClientAdapter())
+	svc.WithIdempotency(idempotencystore.NewMemoryStore(), time.Hour)
+	router := setupRouter(*svc)
+
+	publish := func(key string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(publishdto.InputDto{
+			Meta: publishdto.MetaDto{Topic: "ok"},
+			Data: map[string]string{"foo": "bar"},
+		})
+		req, _ := http.NewRequest(http.MethodPost, "/publish", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(publishcontroller.IdempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := publish("pedido-1")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotContains(t, first.Body.String(), `"replayed"`)
+
+	second := publish("pedido-1")
+	assert.Equal(t, http.StatusOK, second.Code)
+	assert.Contains(t, second.Body.String(), `"replayed":true`)
+	assert.Contains(t, second.Body.String(), `"messageId":"mocked-message-id"`)
+
+	tooLong := publish(strings.Repeat("x", 256))
+	assert.Equal(t, http.StatusBadRequest, tooLong.Code)
+}
+
+func TestPublishController_SchemaInvalido(t *testing.T) {
+	registry, err := topicregistry.NewTopicRegistry([]topicregistry.TopicDefinition{
+		{Name: "ok", Schema: json.RawMessage(`{"type": "object", "required": ["email"]}`)},
+	})
+	assert.NoError(t, err)
+	svc, _ := publishservice.NewPublishService(mock.NewMockPubSubClientAdapter())
+	svc.WithTopicRegistry(registry)
+	router := setupRouter(*svc)
+
+	body, _ := json.Marshal(publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "ok"}, Data: map[string]string{}})
+	req, _ := http.NewRequest(http.MethodPost, "/publish", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"errors":["O campo data.email é obrigatório."]`)
+}
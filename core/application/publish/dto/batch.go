@@ -0,0 +1,20 @@
+package publishdto
+
+// MaxBatchSize limita a quantidade de envelopes aceitos em uma única chamada de POST /publish/batch.
+const MaxBatchSize = 1000
+
+type BatchItemResultDto struct {
+	Index      int      `json:"index"`
+	Topic      string   `json:"topic,omitempty"`
+	MessageID  string   `json:"messageId,omitempty"`
+	ScheduleID string   `json:"scheduleId,omitempty"`
+	Replayed   bool     `json:"replayed,omitempty"`
+	Error      string   `json:"error,omitempty"`
+	Details    []string `json:"details,omitempty"`
+}
+
+type BatchOutputDto struct {
+	Published int                  `json:"published"`
+	Failed    int                  `json:"failed"`
+	Results   []BatchItemResultDto `json:"results"`
+}
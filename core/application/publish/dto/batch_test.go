@@ -0,0 +1,31 @@
+package publishdto_test
+
+import (
+	"encoding/json"
+	"queue/core/application/publish/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchOutputDto_OmiteCamposVazios(t *testing.T) {
+	output := publishdto.BatchOutputDto{
+		Published: 1,
+		Failed:    1,
+		Results: []publishdto.BatchItemResultDto{
+			{Index: 0, Topic: "meu-topico", MessageID: "abc"},
+			{Index: 1, Error: "O tópico é obrigatório."},
+		},
+	}
+
+	raw, err := json.Marshal(output)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"published": 1,
+		"failed": 1,
+		"results": [
+			{"index": 0, "topic": "meu-topico", "messageId": "abc"},
+			{"index": 1, "error": "O tópico é obrigatório."}
+		]
+	}`, string(raw))
+}
@@ -0,0 +1,39 @@
+package publishdto
+
+import (
+	"encoding/json"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"queue/core/infra/cloudevents"
+)
+
+// partitionKeyExtension segue a extensão de particionamento do CloudEvents e vira a chave de ordenação.
+const partitionKeyExtension = "partitionkey"
+
+// FromCloudEvent monta o envelope de publicação a partir de um CloudEvent. A mensagem é publicada
+// no modo binário: o data do evento vira o corpo e os atributos do evento viram atributos ce-*.
+func FromCloudEvent(event *types.Event) (*InputDto, error) {
+	var data interface{}
+	if event.HasJSONData() {
+		if len(event.Data) > 0 {
+			if err := json.Unmarshal(event.Data, &data); err != nil {
+				return nil, &structs.ValidationMessagesError{Messages: []string{"O data do CloudEvent não é um JSON válido."}}
+			}
+		}
+	} else if len(event.Data) > 0 {
+		data = string(event.Data)
+	}
+	rawData := event.Data
+	if rawData == nil {
+		rawData = []byte{}
+	}
+	return &InputDto{
+		Meta: MetaDto{
+			Topic:       event.Topic,
+			Attributes:  cloudevents.Attributes(event),
+			OrderingKey: event.Extensions[partitionKeyExtension],
+		},
+		Data:    data,
+		RawData: rawData,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package publishdto_test
+
+import (
+	"queue/core/application/publish/dto"
+	"queue/core/domain/types"
+	"queue/core/infra/middleware"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCloudEvent(t *testing.T) {
+	dto, err := publishdto.FromCloudEvent(&types.Event{
+		ID:         "1",
+		Source:     "/crm",
+		Topic:      "notifications",
+		Type:       "notifications",
+		Data:       []byte(`{"name":"Ada"}`),
+		Extensions: map[string]string{"partitionkey": "user-1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications", dto.Meta.Topic)
+	assert.Equal(t, "user-1", dto.Meta.OrderingKey)
+	assert.Equal(t, "1", dto.Meta.Attributes["ce-id"])
+	assert.Equal(t, map[string]interface{}{"name": "Ada"}, dto.Data)
+
+	payload, err := dto.Payload()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"Ada"}`, string(payload))
+}
+
+func TestFromCloudEvent_TopicoPorExtensao(t *testing.T) {
+	dto, err := publishdto.FromCloudEvent(&types.Event{
+		Topic:           "notifications",
+		Type:            "com.acme.user.created",
+		DataContentType: "text/plain",
+		Data:            []byte("olá"),
+		Extensions:      map[string]string{"topic": "notifications"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications", dto.Meta.Topic)
+	assert.Equal(t, "olá", dto.Data)
+}
+
+func TestFromCloudEvent_SemData(t *testing.T) {
+	dto, err := publishdto.FromCloudEvent(&types.Event{ID: "1", Source: "/crm", Topic: "user.deleted", Type: "user.deleted"})
+	assert.NoError(t, err)
+	assert.NoError(t, classtransformer.Validate(dto))
+	assert.Nil(t, dto.Data)
+
+	payload, err := dto.Payload()
+	assert.NoError(t, err)
+	assert.Empty(t, payload)
+}
+
+func TestFromCloudEvent_DataJSONInvalido(t *testing.T) {
+	_, err := publishdto.FromCloudEvent(&types.Event{Type: "t", Data: []byte("{")})
+	assert.EqualError(t, err, "O data do CloudEvent não é um JSON válido.")
+}
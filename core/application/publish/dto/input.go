@@ -1,23 +1,62 @@
 package publishdto
 
 import (
+	"encoding/json"
 	"github.com/go-playground/validator/v10"
 	"queue/core/infra/validation"
+	"time"
 )
 
 var createInputErrors = map[string]string{
-	"Meta.Topic.required": "O tópico é obrigatório.",
-	"Data.required":       "Os dados são obrigatórios.",
+	"meta.topic.required":             "O tópico é obrigatório.",
+	"meta.attributes.max":             "São permitidos no máximo 100 atributos.",
+	"meta.orderingKey.max":            "A chave de ordenação deve ter no máximo 1024 caracteres.",
+	"meta.delaySeconds.min":           "O atraso deve ser maior que zero.",
+	"meta.delaySeconds.max":           "O atraso deve ser de no máximo 30 dias.",
+	"meta.delaySeconds.excluded_with": "Informe apenas deliverAt ou delaySeconds.",
+	"meta.idempotencyKey.max":         "A chave de idempotência deve ter no máximo 255 caracteres.",
+	"data.required_without":           "Os dados são obrigatórios.",
 }
 
 type MetaDto struct {
-	AccessToken *string `json:"access_token,omitempty" validate:"omitempty"`
-	Topic       string  `json:"topic" validate:"required"`
+	AccessToken    *string           `json:"access_token,omitempty" validate:"omitempty"`
+	Topic          string            `json:"topic" validate:"required"`
+	Attributes     map[string]string `json:"attributes,omitempty" validate:"omitempty,max=100,dive,keys,required,max=256,endkeys,max=1024"`
+	OrderingKey    string            `json:"orderingKey,omitempty" validate:"omitempty,max=1024"`
+	DeliverAt      *time.Time        `json:"deliverAt,omitempty" validate:"omitempty"`
+	DelaySeconds   int               `json:"delaySeconds,omitempty" validate:"omitempty,min=1,max=2592000,excluded_with=DeliverAt"`
+	IdempotencyKey string            `json:"idempotencyKey,omitempty" validate:"omitempty,max=255"`
 }
 
+// ScheduledFor retorna o horário de entrega quando a mensagem deve ser publicada no futuro.
+func (m MetaDto) ScheduledFor(now time.Time) (time.Time, bool) {
+	var deliverAt time.Time
+	switch {
+	case m.DeliverAt != nil:
+		deliverAt = *m.DeliverAt
+	case m.DelaySeconds > 0:
+		deliverAt = now.Add(time.Duration(m.DelaySeconds) * time.Second)
+	default:
+		return time.Time{}, false
+	}
+	return deliverAt, deliverAt.After(now)
+}
+
+// InputDto exige data apenas no envelope {meta, data}. Quando RawData vem preenchido, como nos
+// CloudEvents, o corpo já está definido e um evento sem data é válido.
 type InputDto struct {
 	Meta MetaDto     `json:"meta" validate:"required"`
-	Data interface{} `json:"data" validate:"required"`
+	Data interface{} `json:"data" validate:"required_without=RawData"`
+	// RawData, quando preenchido, é publicado como corpo da mensagem no lugar do envelope {meta, data}.
+	RawData []byte `json:"-"`
+}
+
+// Payload retorna o corpo da mensagem a ser publicada.
+func (dto InputDto) Payload() ([]byte, error) {
+	if dto.RawData != nil {
+		return dto.RawData, nil
+	}
+	return json.Marshal(dto)
 }
 
 func (dto *InputDto) ValidationMessages(ve validator.ValidationErrors) map[string]string {
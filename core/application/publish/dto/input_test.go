@@ -2,7 +2,9 @@ package publishdto_test
 
 import (
 	"queue/core/application/publish/dto"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
@@ -63,3 +65,82 @@ func TestInputDto_DeveRetornarErroQuandoDataForNil(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Data")
 }
+
+func TestInputDto_DeveAceitarAtributosEChaveDeOrdenacao(t *testing.T) {
+	input := publishdto.InputDto{
+		Meta: publishdto.MetaDto{
+			Topic:       "meu-topico",
+			Attributes:  map[string]string{"tenant": "acme"},
+			OrderingKey: "user-1",
+		},
+		Data: map[string]interface{}{"mensagem": "teste"},
+	}
+
+	err := validate.Struct(input)
+	assert.NoError(t, err)
+}
+
+func TestInputDto_DeveRetornarErroQuandoAtributoTemChaveVazia(t *testing.T) {
+	input := publishdto.InputDto{
+		Meta: publishdto.MetaDto{
+			Topic:      "meu-topico",
+			Attributes: map[string]string{"": "acme"},
+		},
+		Data: map[string]interface{}{"mensagem": "teste"},
+	}
+
+	err := validate.Struct(input)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Attributes")
+}
+
+func TestInputDto_ValidationMessages(t *testing.T) {
+	input := &publishdto.InputDto{
+		Meta: publishdto.MetaDto{OrderingKey: strings.Repeat("x", 1025)},
+	}
+
+	err := validate.Struct(input)
+	var ve validator.ValidationErrors
+	assert.ErrorAs(t, err, &ve)
+	msgs := input.ValidationMessages(ve)
+	assert.Equal(t, "O tópico é obrigatório.", msgs["meta.topic"])
+	assert.Equal(t, "A chave de ordenação deve ter no máximo 1024 caracteres.", msgs["meta.orderingKey"])
+	assert.Equal(t, "Os dados são obrigatórios.", msgs["data"])
+}
+
+func TestMetaDto_ScheduledFor(t *testing.T) {
+	now := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := map[string]struct {
+		meta      publishdto.MetaDto
+		want      time.Time
+		scheduled bool
+	}{
+		"sem agendamento":      {meta: publishdto.MetaDto{}, scheduled: false},
+		"deliverAt no futuro":  {meta: publishdto.MetaDto{DeliverAt: &future}, want: future, scheduled: true},
+		"deliverAt no passado": {meta: publishdto.MetaDto{DeliverAt: &past}, want: past, scheduled: false},
+		"delaySeconds":         {meta: publishdto.MetaDto{DelaySeconds: 1800}, want: now.Add(30 * time.Minute), scheduled: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, scheduled := tt.meta.ScheduledFor(now)
+			assert.Equal(t, tt.scheduled, scheduled)
+			assert.True(t, tt.want.Equal(got))
+		})
+	}
+}
+
+func TestInputDto_DeveRetornarErroQuandoDeliverAtEDelaySecondsInformados(t *testing.T) {
+	deliverAt := time.Now().Add(time.Hour)
+	input := &publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "meu-topico", DeliverAt: &deliverAt, DelaySeconds: 60},
+		Data: map[string]interface{}{"mensagem": "teste"},
+	}
+
+	err := validate.Struct(input)
+	var ve validator.ValidationErrors
+	assert.ErrorAs(t, err, &ve)
+	assert.Equal(t, "Informe apenas deliverAt ou delaySeconds.", input.ValidationMessages(ve)["meta.delaySeconds"])
+}
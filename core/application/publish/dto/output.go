@@ -0,0 +1,12 @@
+package publishdto
+
+import "time"
+
+type OutputDto struct {
+	Topic       string     `json:"topic"`
+	MessageID   string     `json:"messageId,omitempty"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	ScheduleID  string     `json:"scheduleId,omitempty"`
+	DeliverAt   *time.Time `json:"deliverAt,omitempty"`
+	Replayed    bool       `json:"replayed,omitempty"`
+}
@@ -0,0 +1,36 @@
+package publishdto_test
+
+import (
+	"encoding/json"
+	"queue/core/application/publish/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputDto_SerializaCamposEsperados(t *testing.T) {
+	publishedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	output := publishdto.OutputDto{
+		Topic:       "meu-topico",
+		MessageID:   "abc123",
+		PublishedAt: &publishedAt,
+	}
+
+	raw, err := json.Marshal(output)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"topic":"meu-topico","messageId":"abc123","publishedAt":"2025-01-02T03:04:05Z"}`, string(raw))
+}
+
+func TestOutputDto_Agendado(t *testing.T) {
+	deliverAt := time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC)
+	output := publishdto.OutputDto{
+		Topic:      "meu-topico",
+		ScheduleID: "sched-1",
+		DeliverAt:  &deliverAt,
+	}
+
+	raw, err := json.Marshal(output)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"topic":"meu-topico","scheduleId":"sched-1","deliverAt":"2025-01-02T08:00:00Z"}`, string(raw))
+}
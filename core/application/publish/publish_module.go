@@ -7,18 +7,37 @@ import (
 	"queue/core/application/publish/dto"
 	"queue/core/application/publish/service"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
 	"queue/core/infra/middleware"
+	"time"
 )
 
 type PublishModule struct {
 	Controller *publishcontroller.PublishController
 }
 
-func NewPublishModule(client interfaces.IPubSubClient) (*PublishModule, error) {
+// Options reúne as dependências opcionais do serviço de publicação; campos nulos desativam o recurso.
+type Options struct {
+	Scheduler      interfaces.IScheduler
+	Idempotency    interfaces.IIdempotencyStore
+	IdempotencyTTL time.Duration
+	TopicRegistry  interfaces.ITopicRegistry
+}
+
+func NewPublishModule(client interfaces.IPubSubClient, opts Options) (*PublishModule, error) {
 	publishService, err := publishservice.NewPublishService(client)
 	if err != nil {
 		return nil, errors.New("Erro ao criar o serviço de publicação")
 	}
+	if opts.Scheduler != nil {
+		publishService.WithScheduler(opts.Scheduler)
+	}
+	if opts.Idempotency != nil {
+		publishService.WithIdempotency(opts.Idempotency, opts.IdempotencyTTL)
+	}
+	if opts.TopicRegistry != nil {
+		publishService.WithTopicRegistry(opts.TopicRegistry)
+	}
 	controller := publishcontroller.NewPublishController(*publishService)
 	return &PublishModule{
 		Controller: controller,
@@ -28,6 +47,15 @@ func NewPublishModule(client interfaces.IPubSubClient) (*PublishModule, error) {
 func (m *PublishModule) RegisterRoutes(router *gin.Engine) {
 	publishGroup := router.Group("/publish")
 	{
-		publishGroup.POST("", classtransformer.UseClassTransformerMiddleware(&publishdto.InputDto{}), m.Controller.Publish)
+		publishGroup.POST("",
+			classtransformer.UseCloudEventsMiddleware(fromCloudEvent),
+			classtransformer.UseClassTransformerMiddleware(&publishdto.InputDto{}),
+			m.Controller.Publish,
+		)
+		publishGroup.POST("/batch", classtransformer.UseClassTransformerBatchMiddleware(&publishdto.InputDto{}), m.Controller.PublishBatch)
 	}
 }
+
+func fromCloudEvent(event *types.Event) (interface{}, error) {
+	return publishdto.FromCloudEvent(event)
+}
@@ -22,7 +22,7 @@ func (m *MockPubSubClient) Close() error                      { return nil }
 
 func TestNewPublishModule_Success(t *testing.T) {
 	client := &MockPubSubClient{}
-	module, err := publishmodule.NewPublishModule(client)
+	module, err := publishmodule.NewPublishModule(client, publishmodule.Options{})
 	assert.NoError(t, err)
 	assert.NotNil(t, module)
 	assert.NotNil(t, module.Controller)
@@ -30,7 +30,7 @@ func TestNewPublishModule_Success(t *testing.T) {
 
 func TestPublishModule_RegisterRoutes(t *testing.T) {
 	client := &MockPubSubClient{}
-	module, err := publishmodule.NewPublishModule(client)
+	module, err := publishmodule.NewPublishModule(client, publishmodule.Options{})
 	assert.NoError(t, err)
 	router := gin.Default()
 	module.RegisterRoutes(router)
@@ -39,4 +39,9 @@ func TestPublishModule_RegisterRoutes(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.NotEqual(t, http.StatusNotFound, w.Code)
 	assert.Contains(t, []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusOK}, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/publish/batch", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
@@ -1,34 +1,352 @@
 package publishservice
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
-	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"queue/core/application/publish/dto"
+	"queue/core/domain/enum"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"queue/core/infra/logging"
+	"queue/core/infra/metrics"
+	"queue/core/infra/tracing"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// batchConcurrency limita quantas publicações de um lote aguardam confirmação do broker ao mesmo tempo.
+const batchConcurrency = 32
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+var ErrSchedulerNotConfigured = errors.New("Agendamento de mensagens não está configurado")
+
 type PublishService struct {
-	Client interfaces.IPubSubClient
+	Client         interfaces.IPubSubClient
+	Scheduler      interfaces.IScheduler
+	Idempotency    interfaces.IIdempotencyStore
+	TopicRegistry  interfaces.ITopicRegistry
+	IdempotencyTTL time.Duration
+	keyLocks       *keyedMutex
 }
 
 func NewPublishService(client interfaces.IPubSubClient) (*PublishService, error) {
 	return &PublishService{Client: client}, nil
 }
 
-func (ps *PublishService) Publish(dto publishdto.InputDto) (bool, error) {
-	data, err := json.Marshal(dto)
+// Opcional – habilita o envio agendado via meta.deliverAt / meta.delaySeconds
+func (ps *PublishService) WithScheduler(scheduler interfaces.IScheduler) *PublishService {
+	ps.Scheduler = scheduler
+	return ps
+}
+
+// Opcional – habilita a deduplicação por Idempotency-Key / meta.idempotencyKey
+func (ps *PublishService) WithIdempotency(store interfaces.IIdempotencyStore, ttl time.Duration) *PublishService {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	ps.Idempotency = store
+	ps.IdempotencyTTL = ttl
+	ps.keyLocks = newKeyedMutex()
+	return ps
+}
+
+// Opcional – restringe os tópicos publicáveis e valida o data pelo JSON Schema de cada tópico
+func (ps *PublishService) WithTopicRegistry(registry interfaces.ITopicRegistry) *PublishService {
+	ps.TopicRegistry = registry
+	return ps
+}
+
+// Publish publica ou agenda o envelope, gravando o contexto de trace de ctx nos atributos da mensagem.
+// O cancelamento de ctx não interrompe uma publicação já enviada ao broker.
+func (ps *PublishService) Publish(ctx context.Context, dto publishdto.InputDto) (*publishdto.OutputDto, error) {
+	ctx, span := startPublishSpan(context.WithoutCancel(ctx), dto.Meta.Topic)
+	defer span.End()
+	start := time.Now()
+	output, err := ps.publish(ctx, dto)
+	ps.observe(dto.Meta.Topic, start, output, err)
+	endPublishSpan(span, output, err)
+	return output, err
+}
+
+func (ps *PublishService) publish(ctx context.Context, dto publishdto.InputDto) (*publishdto.OutputDto, error) {
+	if err := ps.validateTopic(dto); err != nil {
+		return nil, err
+	}
+	data, err := dto.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return ps.idempotent(ctx, dto, func() (*publishdto.OutputDto, error) {
+		return ps.publishTo(ctx, dto, data, func() interfaces.ITopic {
+			return ps.Client.Topic(dto.Meta.Topic)
+		})
+	})
+}
+
+// PublishBatch publica os envelopes concorrentemente e devolve um resultado por item, na mesma ordem da entrada.
+func (ps *PublishService) PublishBatch(ctx context.Context, dtos []publishdto.InputDto) []publishdto.BatchItemResultDto {
+	ctx, span := tracing.Tracer().Start(context.WithoutCancel(ctx), "publish batch",
+		trace.WithAttributes(attribute.Int("queue.batch.size", len(dtos))))
+	defer span.End()
+	results := make([]publishdto.BatchItemResultDto, len(dtos))
+	topics := map[string]interfaces.ITopic{}
+	rejected := make([]bool, len(dtos))
+	for i, dto := range dtos {
+		results[i] = publishdto.BatchItemResultDto{Index: i, Topic: dto.Meta.Topic}
+		if err := ps.validateTopic(dto); err != nil {
+			ps.observe(dto.Meta.Topic, time.Now(), nil, err)
+			results[i].Error = err.Error()
+			var ve *structs.ValidationMessagesError
+			if errors.As(err, &ve) {
+				results[i].Details = ve.Messages
+			}
+			rejected[i] = true
+			continue
+		}
+		if _, ok := topics[dto.Meta.Topic]; !ok {
+			topics[dto.Meta.Topic] = ps.Client.Topic(dto.Meta.Topic)
+		}
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, batchConcurrency)
+	for i, dto := range dtos {
+		if rejected[i] {
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, dto publishdto.InputDto, topic interfaces.ITopic) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			ctx, span := startPublishSpan(ctx, dto.Meta.Topic)
+			defer span.End()
+			start := time.Now()
+			data, err := dto.Payload()
+			if err != nil {
+				ps.observe(dto.Meta.Topic, start, nil, err)
+				tracing.RecordError(span, err)
+				results[i].Error = err.Error()
+				return
+			}
+			output, err := ps.idempotent(ctx, dto, func() (*publishdto.OutputDto, error) {
+				return ps.publishTo(ctx, dto, data, func() interfaces.ITopic { return topic })
+			})
+			ps.observe(dto.Meta.Topic, start, output, err)
+			endPublishSpan(span, output, err)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].MessageID = output.MessageID
+			results[i].ScheduleID = output.ScheduleID
+			results[i].Replayed = output.Replayed
+		}(i, dto, topics[dto.Meta.Topic])
+	}
+	wg.Wait()
+
+	for _, topic := range topics {
+		topic.Stop()
+	}
+	return results
+}
+
+func (ps *PublishService) validateTopic(dto publishdto.InputDto) error {
+	if ps.TopicRegistry == nil {
+		return nil
+	}
+	return ps.TopicRegistry.Validate(dto.Meta.Topic, dto.Data)
+}
+
+// publishTo agenda a mensagem quando ela é futura; caso contrário publica no tópico resolvido sob demanda.
+func (ps *PublishService) publishTo(ctx context.Context, dto publishdto.InputDto, data []byte, topic func() interfaces.ITopic) (*publishdto.OutputDto, error) {
+	if deliverAt, ok := dto.Meta.ScheduledFor(time.Now()); ok {
+		return ps.schedule(ctx, dto, data, deliverAt)
+	}
+	id, err := topic().Publish(ctx, newMessage(ctx, dto, data)).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Mensagem publicada com sucesso", "topic", dto.Meta.Topic, "message_id", id)
+	publishedAt := time.Now().UTC()
+	return &publishdto.OutputDto{
+		Topic:       dto.Meta.Topic,
+		MessageID:   id,
+		PublishedAt: &publishedAt,
+	}, nil
+}
+
+// schedule grava o contexto de trace na mensagem já no agendamento, para que a entrega futura
+// continue o mesmo trace da requisição original.
+func (ps *PublishService) schedule(ctx context.Context, dto publishdto.InputDto, data []byte, deliverAt time.Time) (*publishdto.OutputDto, error) {
+	if ps.Scheduler == nil {
+		return nil, ErrSchedulerNotConfigured
+	}
+	scheduled, err := ps.Scheduler.Schedule(dto.Meta.Topic, newMessage(ctx, dto, data), deliverAt)
+	if err != nil {
+		return nil, err
+	}
+	return &publishdto.OutputDto{
+		Topic:      dto.Meta.Topic,
+		ScheduleID: scheduled.ID,
+		DeliverAt:  &scheduled.DeliverAt,
+	}, nil
+}
+
+// idempotent devolve o resultado original quando a chave já foi usada dentro da janela configurada.
+// Requisições simultâneas com a mesma chave são serializadas para que apenas uma publique.
+func (ps *PublishService) idempotent(ctx context.Context, dto publishdto.InputDto, publish func() (*publishdto.OutputDto, error)) (*publishdto.OutputDto, error) {
+	key := dto.Meta.IdempotencyKey
+	if key == "" || ps.Idempotency == nil {
+		return publish()
+	}
+	unlock := ps.keyLocks.lock(key)
+	defer unlock()
+
+	record, err := ps.Idempotency.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		slog.InfoContext(ctx, "Chave de idempotência reutilizada", "idempotency_key", key, "message_id", record.MessageID)
+		return &publishdto.OutputDto{
+			Topic:      record.Topic,
+			MessageID:  record.MessageID,
+			ScheduleID: record.ScheduleID,
+			Replayed:   true,
+		}, nil
+	}
+
+	output, err := publish()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	err = ps.Idempotency.Save(types.IdempotencyRecord{
+		Key:        key,
+		Topic:      output.Topic,
+		MessageID:  output.MessageID,
+		ScheduleID: output.ScheduleID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ps.IdempotencyTTL),
+	})
 	if err != nil {
-		return false, err
+		slog.ErrorContext(ctx, "Erro ao salvar chave de idempotência", "idempotency_key", key, "error", err)
 	}
-	result := ps.Client.
-		Topic(dto.Meta.Topic).
-		Publish(context.Background(), &pubsub.Message{Data: data})
-	id, err := result.Get(context.Background())
+	return output, nil
+}
+
+// observe registra o resultado da publicação nas métricas. Erros de validação contam como rejeitados.
+func (ps *PublishService) observe(topic string, start time.Time, output *publishdto.OutputDto, err error) {
+	outcome := enum.PublishPublished
+	var ve *structs.ValidationMessagesError
+	switch {
+	case errors.As(err, &ve):
+		outcome = enum.PublishRejected
+	case err != nil:
+		outcome = enum.PublishFailed
+	case output.Replayed:
+		outcome = enum.PublishReplayed
+	case output.ScheduleID != "":
+		outcome = enum.PublishScheduled
+	}
+	metrics.PublishObserved(ps.metricTopic(topic, outcome), outcome, time.Since(start))
+}
+
+// metricTopic só usa como rótulo tópicos reais: os do registro ou, sem registro, aqueles em que o broker
+// confirmou a publicação. Os demais vêm do cliente sem confirmação e contam como metrics.UnknownTopic.
+func (ps *PublishService) metricTopic(topic string, outcome enum.PublishOutcomeEnum) string {
+	if ps.TopicRegistry != nil {
+		if ps.TopicRegistry.Has(topic) {
+			return topic
+		}
+		return metrics.UnknownTopic
+	}
+	if outcome == enum.PublishPublished {
+		return topic
+	}
+	return metrics.UnknownTopic
+}
+
+func startPublishSpan(ctx context.Context, topic string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingDestinationName(topic), semconv.MessagingOperationTypeSend))
+}
+
+func endPublishSpan(span trace.Span, output *publishdto.OutputDto, err error) {
 	if err != nil {
-		return false, err
+		tracing.RecordError(span, err)
+		return
+	}
+	if output.MessageID != "" {
+		span.SetAttributes(semconv.MessagingMessageID(output.MessageID))
+	}
+	if output.ScheduleID != "" {
+		span.SetAttributes(attribute.String("queue.schedule.id", output.ScheduleID))
+	}
+	span.SetAttributes(attribute.Bool("queue.idempotency.replayed", output.Replayed))
+}
+
+// newMessage copia os atributos do envelope antes de incluir o traceparent, sem alterar o DTO recebido.
+func newMessage(ctx context.Context, dto publishdto.InputDto, data []byte) *types.Message {
+	attributes := make(map[string]string, len(dto.Meta.Attributes)+2)
+	for k, v := range dto.Meta.Attributes {
+		attributes[k] = v
+	}
+	tracing.Inject(ctx, attributes)
+	if id := logging.RequestID(ctx); id != "" {
+		attributes[logging.RequestIDAttribute] = id
+	}
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+	return &types.Message{
+		Data:        data,
+		Attributes:  attributes,
+		OrderingKey: dto.Meta.OrderingKey,
+	}
+}
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: map[string]*keyLock{}}
+}
+
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		k.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
 	}
-	log.Printf("Mensagem publicada com sucesso. ID: %s", id)
-	return true, nil
 }
@@ -4,14 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"queue/core/application/publish/dto"
 	"queue/core/application/publish/service"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/structs"
+	"queue/core/infra/idempotency_store"
+	"queue/core/infra/logging"
+	"queue/core/infra/metrics"
+	queuemock "queue/core/infra/mock"
+	"sync"
 	"testing"
+	"time"
 
-	"cloud.google.com/go/pubsub"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/trace"
+	"queue/core/domain/types"
 )
 
 // Mock para IPubSubClient
@@ -41,7 +51,7 @@ type MockPubSubTopic struct {
 
 func (m *MockPubSubTopic) ID() string { return "" }
 func (m *MockPubSubTopic) Stop()      {}
-func (m *MockPubSubTopic) Publish(ctx context.Context, msg *pubsub.Message) interfaces.IPublishResult {
+func (m *MockPubSubTopic) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
 	args := m.Called(ctx, msg)
 	return args.Get(0).(interfaces.IPublishResult)
 }
@@ -67,16 +77,18 @@ func TestPublish_Success(t *testing.T) {
 	data, _ := json.Marshal(dtoInput)
 
 	// Configura mocks
-	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *pubsub.Message) bool {
+	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *types.Message) bool {
 		return string(msg.Data) == string(data)
 	})).Return(publishResult)
 	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
 	clientMock.On("Topic", "my-topic").Return(topicMock)
 
 	svc := &publishservice.PublishService{Client: clientMock}
-	ok, err := svc.Publish(dtoInput)
-	assert.True(t, ok)
+	output, err := svc.Publish(context.Background(), dtoInput)
 	assert.NoError(t, err)
+	assert.Equal(t, "my-topic", output.Topic)
+	assert.Equal(t, "msg-id", output.MessageID)
+	assert.False(t, output.PublishedAt.IsZero())
 }
 
 func TestPublish_JSONMarshalError(t *testing.T) {
@@ -86,8 +98,8 @@ func TestPublish_JSONMarshalError(t *testing.T) {
 		Data: func() {},
 	}
 	svc := &publishservice.PublishService{Client: clientMock}
-	ok, err := svc.Publish(dtoInput)
-	assert.False(t, ok)
+	output, err := svc.Publish(context.Background(), dtoInput)
+	assert.Nil(t, output)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "json:")
 }
@@ -104,14 +116,309 @@ func TestPublish_PubSubError(t *testing.T) {
 	data, _ := json.Marshal(dtoInput)
 
 	// Configura mocks
-	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *pubsub.Message) bool {
+	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *types.Message) bool {
 		return string(msg.Data) == string(data)
 	})).Return(publishResult)
 	publishResult.On("Get", mock.Anything).Return("", errors.New("erro pub sub"))
 	clientMock.On("Topic", "erro-topic").Return(topicMock)
 
 	svc := &publishservice.PublishService{Client: clientMock}
-	ok, err := svc.Publish(dtoInput)
-	assert.False(t, ok)
+	output, err := svc.Publish(context.Background(), dtoInput)
+	assert.Nil(t, output)
 	assert.EqualError(t, err, "erro pub sub")
 }
+
+func TestPublishBatch_ResultadoPorItem(t *testing.T) {
+	client := queuemock.NewMockPubSubClientAdapter()
+	svc, _ := publishservice.NewPublishService(client)
+
+	results := svc.PublishBatch(context.Background(), []publishdto.InputDto{
+		{Meta: publishdto.MetaDto{Topic: "ok"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "fail"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "ok"}, Data: func() {}},
+	})
+
+	assert.Len(t, results, 3)
+	assert.Equal(t, publishdto.BatchItemResultDto{Index: 0, Topic: "ok", MessageID: "mocked-message-id"}, results[0])
+	assert.Equal(t, 1, results[1].Index)
+	assert.Equal(t, "erro simulado", results[1].Error)
+	assert.Empty(t, results[1].MessageID)
+	assert.Contains(t, results[2].Error, "json:")
+}
+
+func TestPublish_EncaminhaAtributosEChaveDeOrdenacao(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	topicMock := new(MockPubSubTopic)
+	publishResult := new(MockPublishResult)
+
+	dtoInput := publishdto.InputDto{
+		Meta: publishdto.MetaDto{
+			Topic:       "my-topic",
+			Attributes:  map[string]string{"tenant": "acme"},
+			OrderingKey: "user-1",
+		},
+		Data: map[string]string{"key": "value"},
+	}
+
+	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *types.Message) bool {
+		return msg.Attributes["tenant"] == "acme" && msg.OrderingKey == "user-1"
+	})).Return(publishResult)
+	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
+	clientMock.On("Topic", "my-topic").Return(topicMock)
+
+	svc := &publishservice.PublishService{Client: clientMock}
+	_, err := svc.Publish(context.Background(), dtoInput)
+	assert.NoError(t, err)
+	topicMock.AssertExpectations(t)
+}
+
+func TestPublish_PropagaContextoDeTrace(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	topicMock := new(MockPubSubTopic)
+	publishResult := new(MockPublishResult)
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	var published *types.Message
+	topicMock.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		published = args.Get(1).(*types.Message)
+	}).Return(publishResult)
+	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
+	clientMock.On("Topic", "my-topic").Return(topicMock)
+
+	svc := &publishservice.PublishService{Client: clientMock}
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	_, err := svc.Publish(ctx, publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "my-topic"}, Data: 1})
+	assert.NoError(t, err)
+	assert.Contains(t, published.Attributes["traceparent"], parent.TraceID().String())
+}
+
+func TestPublish_PropagaRequestID(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	topicMock := new(MockPubSubTopic)
+	publishResult := new(MockPublishResult)
+
+	topicMock.On("Publish", mock.Anything, mock.MatchedBy(func(msg *types.Message) bool {
+		return msg.Attributes[logging.RequestIDAttribute] == "req-1" && msg.Attributes["tenant"] == "acme"
+	})).Return(publishResult)
+	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
+	clientMock.On("Topic", "my-topic").Return(topicMock)
+
+	svc := &publishservice.PublishService{Client: clientMock}
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	dtoInput := publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "my-topic", Attributes: map[string]string{"tenant": "acme"}}, Data: 1}
+	_, err := svc.Publish(ctx, dtoInput)
+	assert.NoError(t, err)
+	topicMock.AssertExpectations(t)
+}
+
+type fakeScheduler struct {
+	topic     string
+	msg       *types.Message
+	deliverAt time.Time
+}
+
+func (f *fakeScheduler) Schedule(topic string, msg *types.Message, deliverAt time.Time) (*types.ScheduledMessage, error) {
+	f.topic, f.msg, f.deliverAt = topic, msg, deliverAt
+	return &types.ScheduledMessage{ID: "sched-1", Topic: topic, DeliverAt: deliverAt}, nil
+}
+
+func TestPublish_Agendado(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	scheduler := &fakeScheduler{}
+	svc := (&publishservice.PublishService{Client: clientMock}).WithScheduler(scheduler)
+
+	output, err := svc.Publish(context.Background(), publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "my-topic", DelaySeconds: 1800, OrderingKey: "user-1"},
+		Data: map[string]string{"key": "value"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sched-1", output.ScheduleID)
+	assert.Empty(t, output.MessageID)
+	assert.Equal(t, "my-topic", scheduler.topic)
+	assert.Equal(t, "user-1", scheduler.msg.OrderingKey)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), scheduler.deliverAt, 5*time.Second)
+	clientMock.AssertNotCalled(t, "Topic", mock.Anything)
+}
+
+func TestPublish_AgendadoSemScheduler(t *testing.T) {
+	svc := &publishservice.PublishService{Client: new(MockPubSubClient)}
+	_, err := svc.Publish(context.Background(), publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "my-topic", DelaySeconds: 60},
+		Data: map[string]string{"key": "value"},
+	})
+	assert.ErrorIs(t, err, publishservice.ErrSchedulerNotConfigured)
+}
+
+func TestPublishBatch_ItemAgendado(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithScheduler(&fakeScheduler{})
+
+	results := svc.PublishBatch(context.Background(), []publishdto.InputDto{
+		{Meta: publishdto.MetaDto{Topic: "ok"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "ok", DelaySeconds: 60}, Data: map[string]string{"k": "v"}},
+	})
+	assert.Equal(t, "mocked-message-id", results[0].MessageID)
+	assert.Equal(t, "sched-1", results[1].ScheduleID)
+	assert.Empty(t, results[1].MessageID)
+}
+
+func TestPublish_IdempotencyKeyRepeteResultado(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	topicMock := new(MockPubSubTopic)
+	publishResult := new(MockPublishResult)
+	topicMock.On("Publish", mock.Anything, mock.Anything).Return(publishResult).Once()
+	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
+	clientMock.On("Topic", "my-topic").Return(topicMock)
+
+	svc := (&publishservice.PublishService{Client: clientMock}).WithIdempotency(idempotencystore.NewMemoryStore(), time.Hour)
+	dtoInput := publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "my-topic", IdempotencyKey: "pedido-1"},
+		Data: map[string]string{"key": "value"},
+	}
+
+	first, err := svc.Publish(context.Background(), dtoInput)
+	assert.NoError(t, err)
+	assert.False(t, first.Replayed)
+
+	second, err := svc.Publish(context.Background(), dtoInput)
+	assert.NoError(t, err)
+	assert.True(t, second.Replayed)
+	assert.Equal(t, "msg-id", second.MessageID)
+	topicMock.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestPublish_IdempotencyKeyConcorrente(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	topicMock := new(MockPubSubTopic)
+	publishResult := new(MockPublishResult)
+	topicMock.On("Publish", mock.Anything, mock.Anything).Return(publishResult)
+	publishResult.On("Get", mock.Anything).Return("msg-id", nil)
+	clientMock.On("Topic", "my-topic").Return(topicMock)
+
+	svc := (&publishservice.PublishService{Client: clientMock}).WithIdempotency(idempotencystore.NewMemoryStore(), time.Hour)
+	dtoInput := publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "my-topic", IdempotencyKey: "pedido-1"},
+		Data: map[string]string{"key": "value"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Publish(context.Background(), dtoInput)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	topicMock.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestPublish_IdempotencyKeyNaoSalvaFalhas(t *testing.T) {
+	store := idempotencystore.NewMemoryStore()
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithIdempotency(store, time.Hour)
+
+	_, err := svc.Publish(context.Background(), publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "fail", IdempotencyKey: "pedido-1"},
+		Data: map[string]string{"key": "value"},
+	})
+	assert.Error(t, err)
+	record, _ := store.Get("pedido-1")
+	assert.Nil(t, record)
+}
+
+func TestPublishBatch_IdempotencyKey(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithIdempotency(idempotencystore.NewMemoryStore(), time.Hour)
+
+	results := svc.PublishBatch(context.Background(), []publishdto.InputDto{
+		{Meta: publishdto.MetaDto{Topic: "ok", IdempotencyKey: "a"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "ok", IdempotencyKey: "a"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "ok"}, Data: map[string]string{"k": "v"}},
+	})
+	assert.Equal(t, "mocked-message-id", results[0].MessageID)
+	assert.Equal(t, "mocked-message-id", results[1].MessageID)
+	assert.True(t, results[0].Replayed != results[1].Replayed)
+	assert.False(t, results[2].Replayed)
+}
+
+type fakeTopicRegistry struct{}
+
+func (fakeTopicRegistry) Has(topic string) bool { return topic == "ok" }
+
+func (fakeTopicRegistry) Validate(topic string, data interface{}) error {
+	if topic != "ok" {
+		return &structs.ValidationMessagesError{Messages: []string{"O tópico " + topic + " não está habilitado para publicação."}}
+	}
+	return nil
+}
+
+func TestPublish_TopicoNaoPermitido(t *testing.T) {
+	clientMock := new(MockPubSubClient)
+	svc := (&publishservice.PublishService{Client: clientMock}).WithTopicRegistry(fakeTopicRegistry{})
+
+	_, err := svc.Publish(context.Background(), publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "outro"},
+		Data: map[string]string{"key": "value"},
+	})
+	var ve *structs.ValidationMessagesError
+	assert.ErrorAs(t, err, &ve)
+	clientMock.AssertNotCalled(t, "Topic", mock.Anything)
+}
+
+func TestPublishBatch_TopicoNaoPermitido(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithTopicRegistry(fakeTopicRegistry{})
+
+	results := svc.PublishBatch(context.Background(), []publishdto.InputDto{
+		{Meta: publishdto.MetaDto{Topic: "outro"}, Data: map[string]string{"k": "v"}},
+		{Meta: publishdto.MetaDto{Topic: "ok"}, Data: map[string]string{"k": "v"}},
+	})
+	assert.Equal(t, "O tópico outro não está habilitado para publicação.", results[0].Error)
+	assert.Equal(t, []string{"O tópico outro não está habilitado para publicação."}, results[0].Details)
+	assert.Empty(t, results[0].MessageID)
+	assert.Equal(t, "mocked-message-id", results[1].MessageID)
+}
+
+func TestPublish_Metricas(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithScheduler(&fakeScheduler{}).WithIdempotency(idempotencystore.NewMemoryStore(), time.Hour)
+	idempotent := publishdto.InputDto{
+		Meta: publishdto.MetaDto{Topic: "metricas-idempotente", IdempotencyKey: "metricas-1"},
+		Data: map[string]string{"k": "v"},
+	}
+	_, _ = svc.Publish(context.Background(), idempotent)
+	_, _ = svc.Publish(context.Background(), idempotent)
+	_, _ = svc.Publish(context.Background(), publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "metricas-agendado", DelaySeconds: 60}, Data: 1})
+	_, _ = svc.Publish(context.Background(), publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "fail"}, Data: 1})
+	svc.WithTopicRegistry(fakeTopicRegistry{})
+	_, _ = svc.Publish(context.Background(), publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "metricas-rejeitado"}, Data: 1})
+	svc.PublishBatch(context.Background(), []publishdto.InputDto{{Meta: publishdto.MetaDto{Topic: "metricas-lote"}, Data: 1}})
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	assert.Contains(t, body, `queue_publish_requests_total{outcome="published",topic="metricas-idempotente"} 1`)
+	// Sem confirmação do broker ou do registro, o tópico informado pelo cliente não vira rótulo
+	for _, outcome := range []string{"replayed", "scheduled", "failed", "rejected"} {
+		assert.Contains(t, body, `queue_publish_requests_total{outcome="`+outcome+`",topic="unknown"}`)
+	}
+	for _, topic := range []string{"metricas-agendado", "fail", "metricas-rejeitado", "metricas-lote"} {
+		assert.NotContains(t, body, `topic="`+topic+`"`)
+	}
+}
+
+func TestPublish_MetricasComRegistroUsamTopicosRegistrados(t *testing.T) {
+	svc, _ := publishservice.NewPublishService(queuemock.NewMockPubSubClientAdapter())
+	svc.WithTopicRegistry(fakeTopicRegistry{})
+	_, _ = svc.Publish(context.Background(), publishdto.InputDto{Meta: publishdto.MetaDto{Topic: "ok"}, Data: 1})
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `queue_publish_requests_total{outcome="published",topic="ok"}`)
+}
@@ -0,0 +1,55 @@
+package schedulecontroller
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"queue/core/application/schedule/dto"
+	"queue/core/application/schedule/service"
+	"queue/core/domain/response"
+	"queue/core/domain/types"
+)
+
+type ScheduleController struct {
+	service *scheduleservice.ScheduleService
+}
+
+func NewScheduleController(service *scheduleservice.ScheduleService) *ScheduleController {
+	return &ScheduleController{
+		service: service,
+	}
+}
+
+func (ctrl *ScheduleController) List(c *gin.Context) {
+	messages, err := ctrl.service.List()
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Erro ao listar mensagens agendadas", err.Error())
+		return
+	}
+	list := make([]scheduledto.ScheduledMessageDto, len(messages))
+	for i, msg := range messages {
+		list[i] = scheduledto.FromScheduledMessage(msg)
+	}
+	response.Success(c, response.IList[scheduledto.ScheduledMessageDto]{
+		List:      list,
+		TotalRows: int64(len(list)),
+	}, 200, "Mensagens agendadas")
+}
+
+func (ctrl *ScheduleController) Cancel(c *gin.Context) {
+	id := c.Param("id")
+	deleted, err := ctrl.service.Cancel(id)
+	if errors.Is(err, types.ErrScheduleInFlight) {
+		response.Error(c, http.StatusConflict, "A mensagem agendada já está sendo publicada", nil)
+		return
+	}
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Erro ao cancelar mensagem agendada", err.Error())
+		return
+	}
+	if !deleted {
+		response.Error(c, http.StatusNotFound, "Mensagem agendada não encontrada", nil)
+		return
+	}
+	response.Success(c, true, 200, "Agendamento cancelado com sucesso")
+}
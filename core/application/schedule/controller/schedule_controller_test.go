@@ -0,0 +1,63 @@
+package schedulecontroller_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/application/schedule/controller"
+	"queue/core/application/schedule/dto"
+	"queue/core/application/schedule/service"
+	"queue/core/domain/response"
+	"queue/core/domain/types"
+	"queue/core/infra/mock"
+	"queue/core/infra/schedule_store"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(svc *scheduleservice.ScheduleService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ctrl := schedulecontroller.NewScheduleController(svc)
+	r.GET("/publish/scheduled", ctrl.List)
+	r.DELETE("/publish/scheduled/:id", ctrl.Cancel)
+	return r
+}
+
+func TestScheduleController_ListECancel(t *testing.T) {
+	svc := scheduleservice.NewScheduleService(mock.NewMockPubSubClientAdapter(), schedulestore.NewMemoryStore(), 0)
+	scheduled, _ := svc.Schedule("topic", &types.Message{Data: []byte(`{"data":1}`)}, time.Now().Add(time.Hour))
+	router := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/publish/scheduled", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp response.HttpResponse[response.IList[scheduledto.ScheduledMessageDto]]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1), resp.Data.TotalRows)
+	assert.Equal(t, scheduled.ID, resp.Data.List[0].ID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/publish/scheduled/"+scheduled.ID, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/publish/scheduled/"+scheduled.ID, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Mensagem agendada não encontrada")
+}
+
+func TestScheduleController_CancelDuranteAPublicacao(t *testing.T) {
+	store := schedulestore.NewMemoryStore()
+	svc := scheduleservice.NewScheduleService(mock.NewMockPubSubClientAdapter(), store, 0)
+	scheduled, _ := svc.Schedule("topic", &types.Message{}, time.Now())
+	_, _ = store.Claim(time.Now())
+	router := setupRouter(svc)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/publish/scheduled/"+scheduled.ID, nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,33 @@
+package scheduledto
+
+import (
+	"encoding/json"
+	"queue/core/domain/types"
+	"time"
+)
+
+type ScheduledMessageDto struct {
+	ID          string            `json:"id"`
+	Topic       string            `json:"topic"`
+	DeliverAt   time.Time         `json:"deliverAt"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	Envelope    json.RawMessage   `json:"envelope"`
+}
+
+func FromScheduledMessage(msg types.ScheduledMessage) ScheduledMessageDto {
+	envelope := json.RawMessage(msg.Data)
+	if !json.Valid(msg.Data) {
+		envelope, _ = json.Marshal(string(msg.Data))
+	}
+	return ScheduledMessageDto{
+		ID:          msg.ID,
+		Topic:       msg.Topic,
+		DeliverAt:   msg.DeliverAt,
+		CreatedAt:   msg.CreatedAt,
+		Attributes:  msg.Attributes,
+		OrderingKey: msg.OrderingKey,
+		Envelope:    envelope,
+	}
+}
@@ -0,0 +1,27 @@
+package scheduledto_test
+
+import (
+	"encoding/json"
+	"queue/core/application/schedule/dto"
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromScheduledMessage(t *testing.T) {
+	dto := scheduledto.FromScheduledMessage(types.ScheduledMessage{
+		ID:    "sched-1",
+		Topic: "topic",
+		Data:  []byte(`{"meta":{"topic":"topic"},"data":{"k":"v"}}`),
+	})
+
+	raw, err := json.Marshal(dto)
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), `"envelope":{"meta":{"topic":"topic"},"data":{"k":"v"}}`)
+}
+
+func TestFromScheduledMessage_DadosNaoJSON(t *testing.T) {
+	dto := scheduledto.FromScheduledMessage(types.ScheduledMessage{ID: "sched-1", Data: []byte("texto")})
+	assert.Equal(t, `"texto"`, string(dto.Envelope))
+}
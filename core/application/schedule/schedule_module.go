@@ -0,0 +1,30 @@
+package schedulemodule
+
+import (
+	"github.com/gin-gonic/gin"
+	"queue/core/application/schedule/controller"
+	"queue/core/application/schedule/service"
+	"queue/core/domain/interfaces"
+	"time"
+)
+
+type ScheduleModule struct {
+	Service    *scheduleservice.ScheduleService
+	Controller *schedulecontroller.ScheduleController
+}
+
+func NewScheduleModule(client interfaces.IPubSubClient, store interfaces.IScheduleStore, pollInterval time.Duration) *ScheduleModule {
+	service := scheduleservice.NewScheduleService(client, store, pollInterval)
+	return &ScheduleModule{
+		Service:    service,
+		Controller: schedulecontroller.NewScheduleController(service),
+	}
+}
+
+func (m *ScheduleModule) RegisterRoutes(router *gin.Engine) {
+	scheduleGroup := router.Group("/publish/scheduled")
+	{
+		scheduleGroup.GET("", m.Controller.List)
+		scheduleGroup.DELETE("/:id", m.Controller.Cancel)
+	}
+}
@@ -0,0 +1,30 @@
+package schedulemodule_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"queue/core/application/schedule"
+	"queue/core/infra/mock"
+	"queue/core/infra/schedule_store"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleModule_RegisterRoutes(t *testing.T) {
+	module := schedulemodule.NewScheduleModule(mock.NewMockPubSubClientAdapter(), schedulestore.NewMemoryStore(), 0)
+	assert.NotNil(t, module.Service)
+	assert.NotNil(t, module.Controller)
+
+	router := gin.Default()
+	module.RegisterRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/publish/scheduled", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/publish/scheduled/nao-existe", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
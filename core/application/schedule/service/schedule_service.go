@@ -0,0 +1,101 @@
+package scheduleservice
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/logging"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+type ScheduleService struct {
+	Client       interfaces.IPubSubClient
+	Store        interfaces.IScheduleStore
+	PollInterval time.Duration
+}
+
+func NewScheduleService(client interfaces.IPubSubClient, store interfaces.IScheduleStore, pollInterval time.Duration) *ScheduleService {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &ScheduleService{
+		Client:       client,
+		Store:        store,
+		PollInterval: pollInterval,
+	}
+}
+
+func (s *ScheduleService) Schedule(topic string, msg *types.Message, deliverAt time.Time) (*types.ScheduledMessage, error) {
+	scheduled := types.ScheduledMessage{
+		ID:          uuid.NewString(),
+		Topic:       topic,
+		Data:        msg.Data,
+		Attributes:  msg.Attributes,
+		OrderingKey: msg.OrderingKey,
+		DeliverAt:   deliverAt.UTC(),
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.Store.Save(scheduled); err != nil {
+		return nil, err
+	}
+	ctx := logging.WithAttributesRequestID(context.Background(), msg.Attributes)
+	slog.InfoContext(ctx, "Mensagem agendada", "schedule_id", scheduled.ID, "topic", topic, "deliver_at", scheduled.DeliverAt)
+	return &scheduled, nil
+}
+
+func (s *ScheduleService) List() ([]types.ScheduledMessage, error) {
+	return s.Store.List()
+}
+
+// Cancel retorna types.ErrScheduleInFlight quando a mensagem já está sendo publicada.
+func (s *ScheduleService) Cancel(id string) (bool, error) {
+	return s.Store.Delete(id)
+}
+
+// PublishDue reserva no store as mensagens cujo horário já chegou, para que não possam mais ser
+// canceladas, e as publica. As publicadas são removidas; as que falharem voltam a ficar pendentes
+// e são tentadas novamente no próximo ciclo.
+func (s *ScheduleService) PublishDue(ctx context.Context, now time.Time) int {
+	due, err := s.Store.Claim(now)
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao buscar mensagens agendadas", "error", err)
+		return 0
+	}
+	published := 0
+	for _, scheduled := range due {
+		ctx := logging.WithAttributesRequestID(logging.With(ctx, "schedule_id", scheduled.ID, "topic", scheduled.Topic), scheduled.Attributes)
+		topic := s.Client.Topic(scheduled.Topic)
+		id, err := topic.Publish(ctx, scheduled.Message()).Get(ctx)
+		topic.Stop()
+		if err != nil {
+			slog.ErrorContext(ctx, "Erro ao publicar mensagem agendada", "error", err)
+			if err := s.Store.Release(scheduled.ID); err != nil {
+				slog.ErrorContext(ctx, "Erro ao devolver mensagem agendada", "error", err)
+			}
+			continue
+		}
+		if err := s.Store.Complete(scheduled.ID); err != nil {
+			slog.ErrorContext(ctx, "Erro ao remover mensagem agendada", "error", err)
+		}
+		slog.InfoContext(ctx, "Mensagem agendada publicada", "message_id", id)
+		published++
+	}
+	return published
+}
+
+func (s *ScheduleService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.PublishDue(ctx, now)
+		}
+	}
+}
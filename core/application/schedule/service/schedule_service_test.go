@@ -0,0 +1,115 @@
+package scheduleservice_test
+
+import (
+	"context"
+	"queue/core/application/schedule/service"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/memory_broker"
+	"queue/core/infra/mock"
+	"queue/core/infra/schedule_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleService_ScheduleEPublishDue(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	store := schedulestore.NewMemoryStore()
+	svc := scheduleservice.NewScheduleService(broker, store, 0)
+
+	deliverAt := time.Now().Add(time.Hour)
+	scheduled, err := svc.Schedule("topic", &types.Message{Data: []byte("x"), OrderingKey: "user-1"}, deliverAt)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, scheduled.ID)
+
+	list, err := svc.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	assert.Equal(t, 0, svc.PublishDue(context.Background(), time.Now()))
+	assert.Equal(t, 1, svc.PublishDue(context.Background(), deliverAt))
+
+	list, _ = svc.List()
+	assert.Empty(t, list)
+}
+
+func TestScheduleService_FalhaMantemAgendamento(t *testing.T) {
+	store := schedulestore.NewMemoryStore()
+	svc := scheduleservice.NewScheduleService(mock.NewMockPubSubClientAdapter(), store, 0)
+
+	_, err := svc.Schedule("fail", &types.Message{Data: []byte("x")}, time.Now().Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, svc.PublishDue(context.Background(), time.Now()))
+
+	list, _ := svc.List()
+	assert.Len(t, list, 1)
+}
+
+func TestScheduleService_Cancel(t *testing.T) {
+	svc := scheduleservice.NewScheduleService(mock.NewMockPubSubClientAdapter(), schedulestore.NewMemoryStore(), 0)
+	scheduled, _ := svc.Schedule("topic", &types.Message{}, time.Now().Add(time.Hour))
+
+	deleted, err := svc.Cancel(scheduled.ID)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, _ = svc.Cancel(scheduled.ID)
+	assert.False(t, deleted)
+}
+
+// publishHookClient executa onPublish durante cada publicação, antes de ela terminar.
+type publishHookClient struct {
+	interfaces.IPubSubClient
+	onPublish func()
+}
+
+func (c *publishHookClient) Topic(id string) interfaces.ITopic {
+	return &publishHookTopic{ITopic: c.IPubSubClient.Topic(id), onPublish: c.onPublish}
+}
+
+type publishHookTopic struct {
+	interfaces.ITopic
+	onPublish func()
+}
+
+func (t *publishHookTopic) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
+	t.onPublish()
+	return t.ITopic.Publish(ctx, msg)
+}
+
+func TestScheduleService_CancelDuranteAPublicacao(t *testing.T) {
+	client := &publishHookClient{IPubSubClient: mock.NewMockPubSubClientAdapter()}
+	svc := scheduleservice.NewScheduleService(client, schedulestore.NewMemoryStore(), 0)
+	scheduled, _ := svc.Schedule("topic", &types.Message{Data: []byte("x")}, time.Now())
+
+	var cancelErr error
+	client.onPublish = func() { _, cancelErr = svc.Cancel(scheduled.ID) }
+	assert.Equal(t, 1, svc.PublishDue(context.Background(), time.Now()))
+	assert.ErrorIs(t, cancelErr, types.ErrScheduleInFlight)
+
+	// Depois de publicada, a mensagem não existe mais
+	deleted, err := svc.Cancel(scheduled.ID)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestScheduleService_Run(t *testing.T) {
+	store := schedulestore.NewMemoryStore()
+	svc := scheduleservice.NewScheduleService(mock.NewMockPubSubClientAdapter(), store, 10*time.Millisecond)
+	_, _ = svc.Schedule("topic", &types.Message{}, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool {
+		list, _ := store.List()
+		return len(list) == 0
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+}
@@ -4,51 +4,186 @@ import (
 	"context"
 	"errors"
 	"google.golang.org/api/iterator"
-	"log"
+	"log/slog"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/monitor"
 	"queue/core/domain/strategy"
 	"queue/core/domain/types"
 	"queue/core/infra/adapter"
+	"queue/core/infra/dead_letter"
+	"queue/core/infra/logging"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRestartMinBackoff = time.Second
+	defaultRestartMaxBackoff = time.Minute
 )
 
 type SubscriptionService struct {
 	Client          interfaces.IPubSubClient
 	Cfg             *types.Config
 	HandlerStrategy interfaces.HandlerStrategyInterface
+	// DeadLetter é repassado aos handlers que o aceitam; nil mantém as falhas apenas com Nack.
+	DeadLetter interfaces.IDeadLetterQueue
+	// Monitor recebe o estado dos receptores e o destino das mensagens, exposto no health check.
+	Monitor interfaces.ISubscriptionMonitor
 }
 
-func NewSubscriptionService(client interfaces.IPubSubClient, cfg *types.Config) *SubscriptionService {
-	handlerStrategy := strategy.NewSubscriptionHandlerStrategy(cfg)
-	return &SubscriptionService{
+// NewSubscriptionService aplica os middlewares informados a todos os handlers das assinaturas.
+func NewSubscriptionService(client interfaces.IPubSubClient, cfg *types.Config, middlewares ...interfaces.EventHandlerMiddleware) *SubscriptionService {
+	handlerStrategy := strategy.NewSubscriptionHandlerStrategy(cfg, middlewares...)
+	service := &SubscriptionService{
 		Client:          client,
 		Cfg:             cfg,
 		HandlerStrategy: handlerStrategy,
+		Monitor:         monitor.NewMonitor(),
+	}
+	if cfg != nil && cfg.DeadLetter.Topic != "" {
+		service.DeadLetter = deadletter.NewDeadLetterQueue(client, cfg.DeadLetter)
 	}
+	return service
 }
 
-func (l *SubscriptionService) Listen(ctx context.Context) error {
-	subs := l.Client.Subscriptions(ctx)
+type listener struct {
+	sub     interfaces.ISubscription
+	handler interfaces.ISubscribeHandler
+}
+
+// Listen consome todas as assinaturas com handler registrado, cada uma na sua goroutine,
+// e só retorna quando o contexto é cancelado. Falhas na listagem das assinaturas e receptores
+// que encerram são repetidos com backoff, sem afetar as demais assinaturas nem a publicação.
+func (l *SubscriptionService) Listen(ctx context.Context) {
+	listeners, ok := l.listAll(ctx)
+	if !ok {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range listeners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.supervise(ctx, item.sub, item.handler)
+		}()
+	}
+	wg.Wait()
+}
 
+// listAll repete a listagem das assinaturas até conseguir ou o contexto ser cancelado.
+func (l *SubscriptionService) listAll(ctx context.Context) ([]listener, bool) {
+	minBackoff, maxBackoff := l.restartBackoff()
+	backoff := minBackoff
+	for {
+		listeners, err := l.listeners(ctx)
+		l.Monitor.SubscriptionsListed(err)
+		if err == nil {
+			return listeners, true
+		}
+		slog.ErrorContext(ctx, "Erro ao listar as assinaturas", "error", err, "retry_in", backoff)
+		if !sleep(ctx, backoff) {
+			return nil, false
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// listeners percorre todas as assinaturas antes de iniciar o consumo, para que uma falha
+// no iterator não deixe apenas parte delas em execução.
+func (l *SubscriptionService) listeners(ctx context.Context) ([]listener, error) {
+	subs := l.Client.Subscriptions(ctx)
+	var listeners []listener
 	for {
 		sub, err := subs.Next()
 		if err != nil {
 			if errors.Is(err, iterator.Done) {
-				break
+				return listeners, nil
 			}
-			log.Printf("Erro ao iterar sobre as subscriptions: %v", err)
-			return err
+			return nil, err
 		}
 		handler := l.HandlerStrategy.GetHandler(sub.ID())
 		if handler == nil {
-			log.Printf("Nenhum handler registrado para o tópico %s", sub.ID())
+			slog.WarnContext(ctx, "Nenhum handler registrado para a assinatura", "subscription", sub.ID())
 			continue
 		}
-		log.Printf("Handler encontrado para o tópico %s", sub.ID())
-		subAdapter := pubsubadapter.NewSubscriptionAdapter(sub)
-		err = handler.Handle(subAdapter)
+		slog.InfoContext(ctx, "Handler encontrado para a assinatura", "subscription", sub.ID())
+		if aware, ok := handler.(interfaces.IDeadLetterAware); ok && l.DeadLetter != nil {
+			aware.SetDeadLetterQueue(l.DeadLetter)
+		}
+		if aware, ok := handler.(interfaces.ISubscriptionMonitorAware); ok {
+			aware.SetMonitor(l.Monitor)
+		}
+		if configurable, ok := sub.(interfaces.IReceiveSettingsAware); ok {
+			configurable.SetReceiveSettings(l.receiveSettings(sub.ID()))
+		}
+		listeners = append(listeners, listener{sub: pubsubadapter.NewSubscriptionAdapter(sub), handler: handler})
+	}
+}
+
+// supervise mantém o receptor da assinatura ativo, reiniciando-o sempre que encerra antes do contexto.
+// O backoff dobra a cada reinício e volta ao mínimo quando o receptor fica estável pelo intervalo máximo.
+func (l *SubscriptionService) supervise(ctx context.Context, sub interfaces.ISubscription, handler interfaces.ISubscribeHandler) {
+	minBackoff, maxBackoff := l.restartBackoff()
+	backoff := minBackoff
+	defer l.Monitor.ReceiverStopped(sub.ID())
+	logCtx := logging.With(ctx, "subscription", sub.ID())
+	for {
+		started := time.Now()
+		l.Monitor.ReceiverStarted(sub.ID())
+		err := handler.Handle(ctx, sub)
+		if ctx.Err() != nil {
+			slog.InfoContext(logCtx, "Receptor da assinatura encerrado")
+			return
+		}
+		if time.Since(started) >= maxBackoff {
+			backoff = minBackoff
+		}
+		l.Monitor.ReceiverFailed(sub.ID(), err)
 		if err != nil {
-			return errors.New("Erro ao processar assinatura")
+			slog.ErrorContext(logCtx, "Receptor da assinatura encerrou com erro", "error", err, "restart_in", backoff)
+		} else {
+			slog.WarnContext(logCtx, "Receptor da assinatura encerrou", "restart_in", backoff)
+		}
+
+		if !sleep(ctx, backoff) {
+			return
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// sleep aguarda o intervalo, retornando false se o contexto for cancelado antes.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (l *SubscriptionService) restartBackoff() (time.Duration, time.Duration) {
+	minBackoff, maxBackoff := defaultRestartMinBackoff, defaultRestartMaxBackoff
+	if l.Cfg != nil {
+		if l.Cfg.Subscriber.RestartMinBackoff > 0 {
+			minBackoff = l.Cfg.Subscriber.RestartMinBackoff
 		}
+		if l.Cfg.Subscriber.RestartMaxBackoff > 0 {
+			maxBackoff = l.Cfg.Subscriber.RestartMaxBackoff
+		}
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+	return minBackoff, maxBackoff
+}
+
+func (l *SubscriptionService) receiveSettings(subscriptionID string) types.ReceiveSettings {
+	if l.Cfg == nil {
+		return types.ReceiveSettings{}
 	}
-	return nil
+	return l.Cfg.Broker.ReceiveSettingsFor(subscriptionID)
 }
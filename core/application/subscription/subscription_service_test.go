@@ -4,27 +4,61 @@ import (
 	"context"
 	"errors"
 	"google.golang.org/api/iterator"
+	"queue/core/domain/enum"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/monitor"
 	"queue/core/domain/types"
 	"queue/core/infra/mock"
+	"sync"
 	"testing"
+	"time"
 
 	subscriptionservice "queue/core/application/subscription"
 )
 
-// Handler falso para simular comportamentos nos testes
+// Handler falso que bloqueia como um receptor real até o contexto ser cancelado
 type fakeHandler struct {
-	Called   bool
+	mu       sync.Mutex
+	calls    int
 	CallWith interfaces.ISubscription
 	Err      error
+	// Block mantém o handler ativo até o contexto ser cancelado
+	Block   bool
+	started chan struct{}
 }
 
-func (f *fakeHandler) Handle(sub interfaces.ISubscription) error {
-	f.Called = true
+func newFakeHandler(block bool) *fakeHandler {
+	return &fakeHandler{Block: block, started: make(chan struct{}, 100)}
+}
+
+func (f *fakeHandler) Handle(ctx context.Context, sub interfaces.ISubscription) error {
+	f.mu.Lock()
+	f.calls++
 	f.CallWith = sub
+	f.mu.Unlock()
+	f.started <- struct{}{}
+	if f.Block {
+		<-ctx.Done()
+		return nil
+	}
 	return f.Err
 }
 
+func (f *fakeHandler) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (f *fakeHandler) waitStart(t *testing.T) {
+	t.Helper()
+	select {
+	case <-f.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler não foi iniciado")
+	}
+}
+
 // Mock robusto de HandlerStrategyInterface
 type fakeHandlerStrategy struct {
 	handlers map[string]interfaces.ISubscribeHandler
@@ -34,9 +68,28 @@ func (f *fakeHandlerStrategy) GetHandler(topic string) interfaces.ISubscribeHand
 	return f.handlers[topic]
 }
 
+// listen executa Listen em segundo plano e devolve o canal fechado quando ele retorna
+func listen(ctx context.Context, service *subscriptionservice.SubscriptionService) chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Listen(ctx)
+	}()
+	return done
+}
+
+func waitListen(t *testing.T, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Listen deveria retornar após o cancelamento do contexto")
+	}
+}
+
 func TestSubscriptionService_Listen_WithHandler(t *testing.T) {
 	cfg := &types.Config{}
-	handler := &fakeHandler{}
+	handler := newFakeHandler(true)
 	handlerStrategy := &fakeHandlerStrategy{
 		handlers: map[string]interfaces.ISubscribeHandler{
 			"sub-ok": handler,
@@ -46,15 +99,34 @@ func TestSubscriptionService_Listen_WithHandler(t *testing.T) {
 	service := subscriptionservice.NewSubscriptionService(client, cfg)
 	service.HandlerStrategy = handlerStrategy // sobrescrevendo após construção
 
-	err := service.Listen(context.Background())
-	if err != nil {
-		t.Fatalf("Listen retornou erro inesperado: %v", err)
-	}
-	if !handler.Called {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	handler.waitStart(t)
+	cancel()
+	waitListen(t, done)
+	if handler.CallWith.ID() != "sub-ok" {
 		t.Errorf("Esperava que handler fosse chamado para subscription existente.")
 	}
 }
 
+func TestSubscriptionService_Listen_TodasAsAssinaturasEmParalelo(t *testing.T) {
+	cfg := &types.Config{}
+	first, second := newFakeHandler(true), newFakeHandler(true)
+	client := mock.NewMockPubSubClientAdapter("sub-a", "sub-b")
+	service := subscriptionservice.NewSubscriptionService(client, cfg)
+	service.HandlerStrategy = &fakeHandlerStrategy{
+		handlers: map[string]interfaces.ISubscribeHandler{"sub-a": first, "sub-b": second},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	// O primeiro handler bloqueia, então o segundo só inicia se o consumo for concorrente
+	first.waitStart(t)
+	second.waitStart(t)
+	cancel()
+	waitListen(t, done)
+}
+
 func TestSubscriptionService_Listen_WithoutHandler(t *testing.T) {
 	cfg := &types.Config{}
 	handlerStrategy := &fakeHandlerStrategy{
@@ -64,15 +136,17 @@ func TestSubscriptionService_Listen_WithoutHandler(t *testing.T) {
 	service := subscriptionservice.NewSubscriptionService(client, cfg)
 	service.HandlerStrategy = handlerStrategy
 
-	err := service.Listen(context.Background())
-	if err != nil {
-		t.Fatalf("Listen sem handler deveria terminar sem erro, retornou: %v", err)
-	}
+	// Sem handlers não há receptores, então Listen retorna logo
+	service.Listen(context.Background())
 }
 
-func TestSubscriptionService_Listen_HandlerError(t *testing.T) {
-	cfg := &types.Config{}
-	handler := &fakeHandler{Err: errors.New("erro simulado")}
+func TestSubscriptionService_Listen_ReiniciaReceptorComBackoff(t *testing.T) {
+	cfg := &types.Config{Subscriber: types.SubscriberConfig{
+		RestartMinBackoff: 10 * time.Millisecond,
+		RestartMaxBackoff: 20 * time.Millisecond,
+	}}
+	handler := newFakeHandler(false)
+	handler.Err = errors.New("erro simulado")
 	handlerStrategy := &fakeHandlerStrategy{
 		handlers: map[string]interfaces.ISubscribeHandler{
 			"sub-err": handler,
@@ -82,9 +156,76 @@ func TestSubscriptionService_Listen_HandlerError(t *testing.T) {
 	service := subscriptionservice.NewSubscriptionService(client, cfg)
 	service.HandlerStrategy = handlerStrategy
 
-	err := service.Listen(context.Background())
-	if err == nil {
-		t.Fatal("Listen deveria retornar erro quando handler falha.")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	for i := 0; i < 3; i++ {
+		handler.waitStart(t)
+	}
+	status := service.Monitor.Health().Subscriptions[0]
+	cancel()
+	waitListen(t, done)
+	if handler.Calls() < 3 {
+		t.Fatalf("Esperava ao menos 3 execuções do receptor, obteve %d", handler.Calls())
+	}
+	if status.Restarts < 2 || status.LastReceiverError != "erro simulado" {
+		t.Fatalf("Monitor deveria registrar os reinícios do receptor: %+v", status)
+	}
+	if state := service.Monitor.Health().Subscriptions[0].State; state != enum.SubscriberStopped {
+		t.Fatalf("Receptor deveria estar encerrado após o cancelamento, obteve %s", state)
+	}
+}
+
+type settingsSubscription struct {
+	mock.MockSubscription
+	settings types.ReceiveSettings
+}
+
+func (s *settingsSubscription) SetReceiveSettings(settings types.ReceiveSettings) {
+	s.settings = settings
+}
+
+type singleIter struct {
+	sub  interfaces.ISubscription
+	done bool
+}
+
+func (it *singleIter) Next() (interfaces.ISubscription, error) {
+	if it.done {
+		return nil, iterator.Done
+	}
+	it.done = true
+	return it.sub, nil
+}
+
+type singleClient struct {
+	sub interfaces.ISubscription
+}
+
+func (c *singleClient) Subscriptions(_ context.Context) interfaces.ISubscriptionIterator {
+	return &singleIter{sub: c.sub}
+}
+func (c *singleClient) Topic(id string) interfaces.ITopic { return nil }
+func (c *singleClient) Close() error                      { return nil }
+
+func TestSubscriptionService_Listen_AplicaReceiveSettings(t *testing.T) {
+	cfg := &types.Config{Broker: types.BrokerConfig{
+		Receive: types.ReceiveSettings{NumGoroutines: 2},
+		Subscriptions: []types.SubscriptionConfig{
+			{ID: "sub-a", Topic: "t", Receive: types.ReceiveSettings{MaxOutstandingMessages: 25}},
+		},
+	}}
+	sub := &settingsSubscription{MockSubscription: mock.MockSubscription{IDValue: "sub-a"}}
+	handler := newFakeHandler(true)
+	service := subscriptionservice.NewSubscriptionService(&singleClient{sub: sub}, cfg)
+	service.HandlerStrategy = &fakeHandlerStrategy{handlers: map[string]interfaces.ISubscribeHandler{"sub-a": handler}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	handler.waitStart(t)
+	cancel()
+	waitListen(t, done)
+	if sub.settings != (types.ReceiveSettings{MaxOutstandingMessages: 25, NumGoroutines: 2}) {
+		t.Fatalf("ReceiveSettings inesperado: %+v", sub.settings)
 	}
 }
 
@@ -108,9 +249,15 @@ func TestSubscriptionService_Listen_IteratorError(t *testing.T) {
 	service := subscriptionservice.NewSubscriptionService(&clientErr{}, cfg)
 	service.HandlerStrategy = handlerStrategy
 
-	err := service.Listen(context.Background())
-	if err == nil || err.Error() != "falha iterator" {
-		t.Fatalf("Listen deveria repassar erro inesperado do iterator")
+	service.Cfg.Subscriber = types.SubscriberConfig{RestartMinBackoff: 10 * time.Millisecond, RestartMaxBackoff: 10 * time.Millisecond}
+
+	// A falha na listagem é repetida até o cancelamento, sem derrubar o serviço
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	service.Listen(ctx)
+	health := service.Monitor.Health()
+	if health.Status != monitor.HealthDegraded || health.ListError != "falha iterator" {
+		t.Fatalf("Health deveria reportar a falha na listagem: %+v", health)
 	}
 }
 
@@ -134,8 +281,82 @@ func TestSubscriptionService_Listen_Empty(t *testing.T) {
 	service := subscriptionservice.NewSubscriptionService(&doneClient{}, cfg)
 	service.HandlerStrategy = handlerStrategy
 
-	err := service.Listen(context.Background())
-	if err != nil {
-		t.Fatalf("Listen deveria terminar sem erro quando não há subscriptions: %v", err)
+	service.Listen(context.Background())
+	if status := service.Monitor.Health().Status; status != monitor.HealthOK {
+		t.Fatalf("Health deveria estar ok sem assinaturas, obteve %s", status)
+	}
+}
+
+type deadLetterAwareHandler struct {
+	*fakeHandler
+	queue interfaces.IDeadLetterQueue
+}
+
+func (h *deadLetterAwareHandler) SetDeadLetterQueue(queue interfaces.IDeadLetterQueue) {
+	h.queue = queue
+}
+
+func TestSubscriptionService_Listen_RepassaDeadLetter(t *testing.T) {
+	cfg := &types.Config{DeadLetter: types.DeadLetterConfig{Topic: "dlq"}}
+	handler := &deadLetterAwareHandler{fakeHandler: newFakeHandler(true)}
+	service := subscriptionservice.NewSubscriptionService(mock.NewMockPubSubClientAdapter("sub-a"), cfg)
+	service.HandlerStrategy = &fakeHandlerStrategy{handlers: map[string]interfaces.ISubscribeHandler{"sub-a": handler}}
+	if service.DeadLetter == nil {
+		t.Fatal("DeadLetter deveria ser criado quando o tópico está configurado")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	handler.waitStart(t)
+	cancel()
+	waitListen(t, done)
+	if handler.queue != service.DeadLetter {
+		t.Fatal("o dead-letter deveria ser repassado ao handler")
+	}
+}
+
+type monitorAwareHandler struct {
+	*fakeHandler
+	monitor interfaces.ISubscriptionMonitor
+}
+
+func (h *monitorAwareHandler) SetMonitor(monitor interfaces.ISubscriptionMonitor) {
+	h.monitor = monitor
+}
+
+func TestSubscriptionService_Listen_RepassaMonitorEIsolaFalhas(t *testing.T) {
+	cfg := &types.Config{Subscriber: types.SubscriberConfig{
+		RestartMinBackoff: 10 * time.Millisecond,
+		RestartMaxBackoff: 20 * time.Millisecond,
+	}}
+	healthy := &monitorAwareHandler{fakeHandler: newFakeHandler(true)}
+	failing := newFakeHandler(false)
+	failing.Err = errors.New("assinatura removida")
+	service := subscriptionservice.NewSubscriptionService(mock.NewMockPubSubClientAdapter("sub-a", "sub-b"), cfg)
+	service.HandlerStrategy = &fakeHandlerStrategy{handlers: map[string]interfaces.ISubscribeHandler{
+		"sub-a": healthy,
+		"sub-b": failing,
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := listen(ctx, service)
+	healthy.waitStart(t)
+	failing.waitStart(t)
+	failing.waitStart(t)
+	health := service.Monitor.Health()
+	cancel()
+	waitListen(t, done)
+
+	if healthy.monitor != service.Monitor {
+		t.Fatal("Monitor deveria ser repassado ao handler")
+	}
+	if failed := health.Subscriptions[1]; failed.Restarts < 1 || failed.LastReceiverError != "assinatura removida" {
+		t.Fatalf("Monitor deveria registrar a falha da assinatura: %+v", failed)
+	}
+	if health.Subscriptions[0].State != enum.SubscriberRunning {
+		t.Fatalf("A assinatura saudável deveria continuar ativa: %+v", health.Subscriptions[0])
+	}
+	if healthy.Calls() != 1 {
+		t.Fatalf("A falha de outra assinatura não deveria reiniciar o receptor saudável")
 	}
 }
@@ -0,0 +1,166 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInProgress indica que outra entrega do mesmo evento está sendo processada pelo handler.
+var ErrInProgress = errors.New("evento já está em processamento")
+
+// KeyFunc extrai do evento a chave que identifica entregas repetidas.
+type KeyFunc func(event *types.Event) (string, error)
+
+// MessageIDKey usa o id do evento: o id da mensagem no envelope legado ou o id do CloudEvent.
+func MessageIDKey(event *types.Event) (string, error) {
+	if event.ID == "" {
+		return "", errors.New("evento sem id")
+	}
+	return event.ID, nil
+}
+
+// NewKeyFunc monta a chave a partir dos campos informados. Os campos "id", "topic" e "data"
+// referem-se ao evento (data é o payload inteiro); "data.<caminho>" lê um campo do payload JSON.
+// Sem campos, ou apenas com "id", a chave é o id do evento; nos demais casos é o hash SHA-256 dos valores.
+func NewKeyFunc(fields []string) KeyFunc {
+	if len(fields) == 0 || (len(fields) == 1 && fields[0] == "id") {
+		return MessageIDKey
+	}
+	return func(event *types.Event) (string, error) {
+		var payload any
+		hash := sha256.New()
+		for _, field := range fields {
+			value, err := fieldValue(event, field, &payload)
+			if err != nil {
+				return "", err
+			}
+			hash.Write(value)
+			hash.Write([]byte{0})
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+}
+
+// fieldValue decodifica o payload apenas na primeira leitura de um campo dele.
+func fieldValue(event *types.Event, field string, payload *any) ([]byte, error) {
+	switch field {
+	case "id":
+		return []byte(event.ID), nil
+	case "topic":
+		return []byte(event.Topic), nil
+	case "data":
+		return event.Data, nil
+	}
+	path, ok := strings.CutPrefix(field, "data.")
+	if !ok {
+		return nil, fmt.Errorf("campo de deduplicação inválido: %s", field)
+	}
+	if *payload == nil {
+		if !event.HasJSONData() {
+			return nil, fmt.Errorf("o campo %s exige payload JSON", field)
+		}
+		if err := json.Unmarshal(event.Data, payload); err != nil {
+			return nil, fmt.Errorf("payload inválido: %w", err)
+		}
+	}
+	value := *payload
+	for _, part := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("campo %s não encontrado no payload", field)
+		}
+		if value, ok = object[part]; !ok {
+			return nil, fmt.Errorf("campo %s não encontrado no payload", field)
+		}
+	}
+	return json.Marshal(value)
+}
+
+// Handler envolve um handler de eventos e confirma sem efeitos colaterais as entregas de eventos
+// que ele já processou. A chave só é gravada após o sucesso do handler, então falhas continuam
+// sendo repetidas. Uma entrega recebida enquanto o mesmo evento ainda está em processamento falha
+// com ErrInProgress, para ser repetida pelo broker e confirmada depois que a primeira terminar.
+type Handler struct {
+	name  string
+	inner interfaces.IEventHandler
+	store interfaces.IDedupStore
+	key   KeyFunc
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	inFlight map[string]struct{}
+}
+
+func NewHandler(name string, inner interfaces.IEventHandler, store interfaces.IDedupStore, key KeyFunc, ttl time.Duration) *Handler {
+	if key == nil {
+		key = MessageIDKey
+	}
+	return &Handler{name: name, inner: inner, store: store, key: key, ttl: ttl, inFlight: map[string]struct{}{}}
+}
+
+// Middleware aplica a deduplicação a cada handler, com as chaves separadas por assinatura e handler
+// para que o mesmo evento recebido em outra assinatura, ou por outro handler, seja processado normalmente.
+func Middleware(store interfaces.IDedupStore, key KeyFunc, ttl time.Duration) interfaces.EventHandlerMiddleware {
+	return func(subscriptionID string, name string, handler interfaces.IEventHandler) interfaces.IEventHandler {
+		return NewHandler(subscriptionID+"/"+name, handler, store, key, ttl)
+	}
+}
+
+func (h *Handler) Supports(topic string) bool {
+	return h.inner.Supports(topic)
+}
+
+// Handle processa o evento sem deduplicação quando a chave não pode ser obtida ou o store falha,
+// preferindo uma entrega repetida a uma mensagem perdida.
+func (h *Handler) Handle(ctx context.Context, event *types.Event) error {
+	key, err := h.key(event)
+	if err != nil {
+		slog.WarnContext(ctx, "Deduplicação ignorada", "event_id", event.ID, "error", err)
+		return h.inner.Handle(ctx, event)
+	}
+	key = h.name + ":" + key
+	if !h.acquire(key) {
+		return fmt.Errorf("evento %s no handler %s: %w", event.ID, h.name, ErrInProgress)
+	}
+	defer h.release(key)
+	seen, err := h.store.Seen(key)
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao consultar a chave de deduplicação", "dedup_key", key, "error", err)
+	} else if seen {
+		slog.InfoContext(ctx, "Evento já processado pelo handler, entrega duplicada ignorada", "event_id", event.ID)
+		return nil
+	}
+	if err := h.inner.Handle(ctx, event); err != nil {
+		return err
+	}
+	if err := h.store.Mark(key, h.ttl); err != nil {
+		slog.ErrorContext(ctx, "Erro ao gravar a chave de deduplicação", "dedup_key", key, "error", err)
+	}
+	return nil
+}
+
+func (h *Handler) acquire(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.inFlight[key]; ok {
+		return false
+	}
+	h.inFlight[key] = struct{}{}
+	return true
+}
+
+func (h *Handler) release(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.inFlight, key)
+}
@@ -0,0 +1,157 @@
+package dedup_test
+
+import (
+	"context"
+	"errors"
+	"queue/core/domain/dedup"
+	"queue/core/domain/types"
+	"queue/core/infra/dedup_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) Supports(topic string) bool { return topic == "user.created" }
+
+func (h *countingHandler) Handle(ctx context.Context, event *types.Event) error {
+	h.calls++
+	return h.err
+}
+
+type failingStore struct{}
+
+func (failingStore) Seen(key string) (bool, error) { return false, errors.New("store indisponível") }
+func (failingStore) Mark(key string, ttl time.Duration) error {
+	return errors.New("store indisponível")
+}
+
+func event(id string, data string) *types.Event {
+	return &types.Event{ID: id, Topic: "user.created", Data: []byte(data)}
+}
+
+func TestHandler_IgnoraEntregaDuplicada(t *testing.T) {
+	inner := &countingHandler{}
+	handler := dedup.NewHandler("sub/h", inner, dedupstore.NewMemoryStore(0), nil, time.Hour)
+
+	assert.True(t, handler.Supports("user.created"))
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+	assert.NoError(t, handler.Handle(context.Background(), event("2", "{}")))
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestHandler_FalhaNaoMarcaEvento(t *testing.T) {
+	inner := &countingHandler{err: errors.New("falhou")}
+	handler := dedup.NewHandler("sub/h", inner, dedupstore.NewMemoryStore(0), nil, time.Hour)
+
+	assert.Error(t, handler.Handle(context.Background(), event("1", "{}")))
+	inner.err = nil
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestHandler_ProcessaQuandoStoreFalha(t *testing.T) {
+	inner := &countingHandler{}
+	handler := dedup.NewHandler("sub/h", inner, failingStore{}, nil, time.Hour)
+
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestHandler_ProcessaQuandoChaveIndisponivel(t *testing.T) {
+	inner := &countingHandler{}
+	handler := dedup.NewHandler("sub/h", inner, dedupstore.NewMemoryStore(0), nil, time.Hour)
+
+	assert.NoError(t, handler.Handle(context.Background(), event("", "{}")))
+	assert.NoError(t, handler.Handle(context.Background(), event("", "{}")))
+	assert.Equal(t, 2, inner.calls)
+}
+
+func TestMiddleware_SeparaAssinaturasEHandlers(t *testing.T) {
+	store := dedupstore.NewMemoryStore(0)
+	middleware := dedup.Middleware(store, nil, time.Hour)
+	first, second, other := &countingHandler{}, &countingHandler{}, &countingHandler{}
+	handlers := []struct {
+		sub, name string
+		inner     *countingHandler
+	}{
+		{"sub-a", "notification", first},
+		{"sub-b", "notification", second},
+		{"sub-a", "audit", other},
+	}
+	for _, h := range handlers {
+		wrapped := middleware(h.sub, h.name, h.inner)
+		assert.NoError(t, wrapped.Handle(context.Background(), event("1", "{}")))
+		assert.NoError(t, wrapped.Handle(context.Background(), event("1", "{}")))
+		assert.Equal(t, 1, h.inner.calls, h.sub+"/"+h.name)
+	}
+}
+
+func TestNewKeyFunc_MessageID(t *testing.T) {
+	for _, fields := range [][]string{nil, {"id"}} {
+		key, err := dedup.NewKeyFunc(fields)(event("1", "{}"))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", key)
+	}
+}
+
+func TestNewKeyFunc_ChaveDeNegocio(t *testing.T) {
+	keyFunc := dedup.NewKeyFunc([]string{"data.userId", "data.channel.name", "data"})
+	payload := `{"userId":10,"channel":{"name":"email"}}`
+
+	first, err := keyFunc(event("1", payload))
+	assert.NoError(t, err)
+	second, err := keyFunc(event("2", payload))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Len(t, first, 64)
+
+	other, err := keyFunc(event("1", `{"userId":11,"channel":{"name":"email"}}`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func TestNewKeyFunc_Erros(t *testing.T) {
+	_, err := dedup.NewKeyFunc([]string{"data.userId"})(event("1", `{"outro":1}`))
+	assert.Error(t, err)
+	_, err = dedup.NewKeyFunc([]string{"data.userId"})(event("1", `{`))
+	assert.Error(t, err)
+	_, err = dedup.NewKeyFunc([]string{"data.userId"})(&types.Event{ID: "1", DataContentType: "text/plain", Data: []byte("x")})
+	assert.Error(t, err)
+	_, err = dedup.NewKeyFunc([]string{"userId"})(event("1", `{"userId":1}`))
+	assert.Error(t, err)
+}
+
+type blockingHandler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) Supports(topic string) bool { return true }
+
+func (h *blockingHandler) Handle(ctx context.Context, event *types.Event) error {
+	close(h.started)
+	<-h.release
+	return nil
+}
+
+func TestHandler_EntregaSimultaneaFalhaAteOPrimeiroTerminar(t *testing.T) {
+	inner := &blockingHandler{started: make(chan struct{}), release: make(chan struct{})}
+	handler := dedup.NewHandler("sub/h", inner, dedupstore.NewMemoryStore(0), nil, time.Hour)
+
+	done := make(chan error)
+	go func() { done <- handler.Handle(context.Background(), event("1", "{}")) }()
+	<-inner.started
+	assert.ErrorIs(t, handler.Handle(context.Background(), event("1", "{}")), dedup.ErrInProgress)
+
+	close(inner.release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, handler.Handle(context.Background(), event("1", "{}")))
+}
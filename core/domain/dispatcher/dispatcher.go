@@ -0,0 +1,262 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"queue/core/domain/enum"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"queue/core/infra/cloudevents"
+	"queue/core/infra/logging"
+	"queue/core/infra/metrics"
+	"queue/core/infra/tracing"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrHandlerTimeout indica que o handler não terminou dentro do tempo limite; a mensagem é devolvida ao broker.
+var ErrHandlerTimeout = errors.New("tempo limite do handler excedido")
+
+// ErrNoHandler indica que nenhum handler registrado suporta o tópico do evento. A mensagem não é confirmada,
+// para que volte a ser entregue ou siga para o dead-letter em vez de ser descartada sem aviso.
+var ErrNoHandler = errors.New("nenhum handler registrado para o tópico")
+
+// HandlerOptions define como o Dispatcher executa um handler. Timeout zero não limita a execução.
+// Name identifica o handler nos logs; vazio usa o tipo do handler.
+type HandlerOptions struct {
+	Name    string
+	Policy  enum.HandlerPolicyEnum
+	Timeout time.Duration
+}
+
+type registration struct {
+	name    string
+	handler interfaces.IEventHandler
+	policy  enum.HandlerPolicyEnum
+	timeout time.Duration
+}
+
+// Dispatcher implementa interfaces.IDispatcher e interfaces.ISubscribeHandler: cada mensagem recebida
+// é decodificada em um evento e entregue, em paralelo, a todos os handlers que suportam o seu tópico.
+type Dispatcher struct {
+	mu            sync.RWMutex
+	registrations []registration
+	deadLetter    interfaces.IDeadLetterQueue
+	monitor       interfaces.ISubscriptionMonitor
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// SetDeadLetterQueue faz as falhas passarem pelo dead-letter antes de decidir entre Ack e Nack.
+func (d *Dispatcher) SetDeadLetterQueue(queue interfaces.IDeadLetterQueue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.deadLetter = queue
+}
+
+// SetMonitor faz o Dispatcher reportar o destino de cada mensagem recebida.
+func (d *Dispatcher) SetMonitor(monitor interfaces.ISubscriptionMonitor) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.monitor = monitor
+}
+
+// Register adiciona um handler obrigatório: se ele falhar, a mensagem não é confirmada.
+func (d *Dispatcher) Register(handler interfaces.IEventHandler) {
+	d.RegisterWithPolicy(handler, enum.RequiredHandler)
+}
+
+func (d *Dispatcher) RegisterWithPolicy(handler interfaces.IEventHandler, policy enum.HandlerPolicyEnum) {
+	d.RegisterWithOptions(handler, HandlerOptions{Policy: policy})
+}
+
+// RegisterWithOptions adiciona um handler com política e tempo limite próprios; sem política ele é obrigatório.
+func (d *Dispatcher) RegisterWithOptions(handler interfaces.IEventHandler, options HandlerOptions) {
+	if options.Policy == "" {
+		options.Policy = enum.RequiredHandler
+	}
+	if options.Name == "" {
+		options.Name = fmt.Sprintf("%T", handler)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.registrations = append(d.registrations, registration{name: options.Name, handler: handler, policy: options.Policy, timeout: options.Timeout})
+}
+
+// Dispatch executa todos os handlers que suportam o tópico do evento e retorna os erros dos handlers
+// obrigatórios. Falhas de handlers best-effort são apenas registradas em log. Sem handler para o tópico,
+// retorna ErrNoHandler.
+func (d *Dispatcher) Dispatch(ctx context.Context, event *types.Event) error {
+	matches := d.matching(event.Topic)
+	if len(matches) == 0 {
+		return fmt.Errorf("%w %q", ErrNoHandler, event.Topic)
+	}
+
+	errs := make([]error, len(matches))
+	var wg sync.WaitGroup
+	for i, reg := range matches {
+		wg.Add(1)
+		go func(i int, reg registration) {
+			defer wg.Done()
+			ctx := logging.With(ctx, "handler", reg.name)
+			if err := reg.run(ctx, event); err != nil {
+				if reg.policy == enum.BestEffortHandler {
+					slog.WarnContext(ctx, "Handler best-effort falhou", "event_id", event.ID, "error", err)
+					return
+				}
+				errs[i] = fmt.Errorf("%s: %w", reg.name, err)
+			}
+		}(i, reg)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// run executa o handler com o tempo limite da registration, cancelando o contexto repassado a ele.
+// Um handler que ignora o cancelamento não segura a mensagem: ela é devolvida e ele termina em segundo plano.
+// O erro de tempo limite nunca é permanente, mesmo que o handler tenha retornado um, para que a mensagem seja reentregue.
+func (reg registration) run(ctx context.Context, event *types.Event) error {
+	if reg.timeout <= 0 {
+		return reg.handler.Handle(ctx, event)
+	}
+	ctx, cancel := context.WithTimeout(ctx, reg.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.handler.Handle(ctx, event)
+	}()
+	select {
+	case err := <-done:
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w (%s): %v", ErrHandlerTimeout, reg.timeout, err)
+		}
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w (%s)", ErrHandlerTimeout, reg.timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// Handle consome a assinatura, confirmando cada mensagem apenas quando todos os handlers obrigatórios tiverem sucesso.
+// As falhas passam pelo dead-letter, quando configurado, que pode retirar a mensagem da assinatura.
+func (d *Dispatcher) Handle(ctx context.Context, sub interfaces.ISubscription) error {
+	return sub.Receive(ctx, func(ctx context.Context, msg *types.Message) {
+		// O cancelamento do recebimento no desligamento não interrompe mensagens já em processamento.
+		ctx = context.WithoutCancel(ctx)
+		metrics.MessageReceived(sub.ID())
+		ctx = logging.WithMessage(ctx, sub.ID(), msg)
+		ctx, span := startProcessSpan(ctx, sub.ID(), msg)
+		defer span.End()
+		event, err := cloudevents.FromMessage(msg)
+		if err != nil {
+			slog.ErrorContext(ctx, "Mensagem inválida", "error", err)
+			d.fail(ctx, types.DeliveryFailure{SubscriptionID: sub.ID(), Message: msg, Err: err})
+			return
+		}
+		if err := d.Dispatch(ctx, event); err != nil {
+			slog.ErrorContext(ctx, "Erro ao processar a mensagem", "event_id", event.ID, "error", err)
+			d.fail(ctx, types.DeliveryFailure{
+				SubscriptionID: sub.ID(),
+				Topic:          event.Topic,
+				Message:        msg,
+				Err:            err,
+				Permanent:      structs.IsPermanent(err),
+			})
+			return
+		}
+		if queue := d.deadLetterQueue(); queue != nil {
+			queue.Succeeded(sub.ID(), msg)
+		}
+		msg.Ack()
+		d.report(ctx, sub.ID(), enum.MessageAcked, nil)
+	})
+}
+
+// startProcessSpan continua o trace gravado nos atributos pelo publicador, ligando a requisição
+// de publicação ao processamento e às chamadas feitas pelos handlers.
+func startProcessSpan(ctx context.Context, subscriptionID string, msg *types.Message) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{
+		semconv.MessagingDestinationSubscriptionName(subscriptionID),
+		semconv.MessagingMessageID(msg.ID),
+		semconv.MessagingOperationTypeProcess,
+	}
+	if msg.DeliveryAttempt != nil {
+		attributes = append(attributes, semconv.MessagingGCPPubSubMessageDeliveryAttempt(*msg.DeliveryAttempt))
+	}
+	if msg.OrderingKey != "" {
+		attributes = append(attributes, semconv.MessagingGCPPubSubMessageOrderingKey(msg.OrderingKey))
+	}
+	return tracing.Tracer().Start(tracing.Extract(ctx, msg.Attributes), "process "+subscriptionID,
+		trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attributes...))
+}
+
+// fail confirma a mensagem quando ela foi para o dead-letter e a devolve para nova entrega nos demais casos.
+// Sem dead-letter, falhas permanentes são confirmadas com o conteúdo em log, já que reentregar não mudaria o resultado.
+func (d *Dispatcher) fail(ctx context.Context, failure types.DeliveryFailure) {
+	msg := failure.Message
+	queue := d.deadLetterQueue()
+	if queue == nil {
+		if failure.Permanent {
+			slog.WarnContext(ctx, "Mensagem descartada por erro permanente", "data", string(msg.Data))
+			msg.Ack()
+			d.report(ctx, failure.SubscriptionID, enum.MessageDiscarded, failure.Err)
+			return
+		}
+		msg.Nack()
+		d.report(ctx, failure.SubscriptionID, enum.MessageNacked, failure.Err)
+		return
+	}
+	sent, err := queue.Failed(ctx, failure)
+	if err != nil {
+		slog.ErrorContext(ctx, "Erro ao enviar a mensagem ao dead-letter", "error", err)
+	}
+	if sent {
+		msg.Ack()
+		d.report(ctx, failure.SubscriptionID, enum.MessageDeadLettered, failure.Err)
+		return
+	}
+	msg.Nack()
+	d.report(ctx, failure.SubscriptionID, enum.MessageNacked, failure.Err)
+}
+
+func (d *Dispatcher) report(ctx context.Context, subscriptionID string, outcome enum.MessageOutcomeEnum, err error) {
+	metrics.MessageProcessed(subscriptionID, outcome)
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("queue.message.outcome", string(outcome)))
+	tracing.RecordError(span, err)
+	d.mu.RLock()
+	monitor := d.monitor
+	d.mu.RUnlock()
+	if monitor != nil {
+		monitor.MessageHandled(subscriptionID, outcome, err)
+	}
+}
+
+func (d *Dispatcher) deadLetterQueue() interfaces.IDeadLetterQueue {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.deadLetter
+}
+
+func (d *Dispatcher) matching(topic string) []registration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	var matches []registration
+	for _, reg := range d.registrations {
+		if reg.handler.Supports(topic) {
+			matches = append(matches, reg)
+		}
+	}
+	return matches
+}
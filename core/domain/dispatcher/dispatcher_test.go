@@ -0,0 +1,369 @@
+package dispatcher_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"queue/core/domain/dispatcher"
+	"queue/core/domain/enum"
+	"queue/core/domain/monitor"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"queue/core/infra/logging"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingHandler struct {
+	mu     sync.Mutex
+	topics []string
+	err    error
+	events []string
+}
+
+func (h *recordingHandler) Supports(topic string) bool {
+	for _, t := range h.topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, event *types.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event.ID)
+	return h.err
+}
+
+type fakeSubscription struct {
+	messages []*types.Message
+}
+
+func (f *fakeSubscription) ID() string { return "fake-sub" }
+
+func (f *fakeSubscription) Receive(ctx context.Context, fn func(context.Context, *types.Message)) error {
+	for _, msg := range f.messages {
+		fn(ctx, msg)
+	}
+	return nil
+}
+
+func newMessage(data string) (*types.Message, *string) {
+	result := ""
+	msg := types.NewReceivedMessage(types.Message{ID: "1", Data: []byte(data)},
+		func() { result = "ack" },
+		func() { result = "nack" },
+	)
+	return msg, &result
+}
+
+func TestDispatcher_FanOut(t *testing.T) {
+	audit := &recordingHandler{topics: []string{"user.created", "user.deleted"}}
+	notification := &recordingHandler{topics: []string{"user.created"}}
+	other := &recordingHandler{topics: []string{"order.created"}}
+	d := dispatcher.NewDispatcher()
+	d.Register(audit)
+	d.Register(notification)
+	d.Register(other)
+
+	err := d.Dispatch(context.Background(), &types.Event{ID: "1", Topic: "user.created"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, audit.events)
+	assert.Equal(t, []string{"1"}, notification.events)
+	assert.Empty(t, other.events)
+}
+
+func TestDispatcher_SemHandler(t *testing.T) {
+	d := dispatcher.NewDispatcher()
+	err := d.Dispatch(context.Background(), &types.Event{Topic: "user.created"})
+	assert.ErrorIs(t, err, dispatcher.ErrNoHandler)
+	assert.ErrorContains(t, err, `"user.created"`)
+}
+
+func TestDispatcher_Handle_SemHandlerDevolveMensagem(t *testing.T) {
+	m := monitor.NewMonitor()
+	d := dispatcher.NewDispatcher()
+	d.Register(&recordingHandler{topics: []string{"audit"}})
+	d.SetMonitor(m)
+
+	msg, result := newMessage(`{"meta":{"topic":"desconhecido"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{msg}}))
+	assert.Equal(t, "nack", *result)
+	assert.Equal(t, int64(1), m.Health().Subscriptions[0].Messages[enum.MessageNacked])
+
+	queue := &fakeDeadLetter{send: true}
+	d.SetDeadLetterQueue(queue)
+	deadLettered, deadResult := newMessage(`{"meta":{"topic":"desconhecido"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{deadLettered}}))
+	assert.Equal(t, "ack", *deadResult)
+	assert.ErrorIs(t, queue.failures[0].Err, dispatcher.ErrNoHandler)
+	assert.Equal(t, "desconhecido", queue.failures[0].Topic)
+}
+
+func TestDispatcher_ErroIdentificaHandlerPeloNome(t *testing.T) {
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(&recordingHandler{topics: []string{"t"}, err: errors.New("falhou")}, dispatcher.HandlerOptions{Name: "audit"})
+	err := d.Dispatch(context.Background(), &types.Event{Topic: "t"})
+	assert.EqualError(t, err, "audit: falhou")
+}
+
+func TestDispatcher_Politicas(t *testing.T) {
+	required := &recordingHandler{topics: []string{"t"}, err: errors.New("falha obrigatória")}
+	bestEffort := &recordingHandler{topics: []string{"t"}, err: errors.New("falha opcional")}
+
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithPolicy(bestEffort, enum.BestEffortHandler)
+	assert.NoError(t, d.Dispatch(context.Background(), &types.Event{Topic: "t"}))
+
+	d.Register(required)
+	err := d.Dispatch(context.Background(), &types.Event{Topic: "t"})
+	assert.ErrorContains(t, err, "falha obrigatória")
+	assert.NotContains(t, err.Error(), "falha opcional")
+}
+
+func TestDispatcher_Handle_AckENack(t *testing.T) {
+	ok := &recordingHandler{topics: []string{"notifications"}}
+	failing := &recordingHandler{topics: []string{"audit"}, err: errors.New("erro")}
+	d := dispatcher.NewDispatcher()
+	d.Register(ok)
+	d.Register(failing)
+
+	acked, ackResult := newMessage(`{"meta":{"topic":"notifications"},"data":{}}`)
+	nacked, nackResult := newMessage(`{"meta":{"topic":"audit"},"data":{}}`)
+	invalid, invalidResult := newMessage(`{not-json}`)
+	err := d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{acked, nacked, invalid}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ack", *ackResult)
+	assert.Equal(t, "nack", *nackResult)
+	assert.Equal(t, "nack", *invalidResult)
+}
+
+type contextHandler struct {
+	ctx context.Context
+}
+
+func (h *contextHandler) Supports(topic string) bool { return true }
+
+func (h *contextHandler) Handle(ctx context.Context, event *types.Event) error {
+	h.ctx = ctx
+	return nil
+}
+
+func TestDispatcher_Handle_ContinuaTraceDaMensagem(t *testing.T) {
+	handler := &contextHandler{}
+	d := dispatcher.NewDispatcher()
+	d.Register(handler)
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	msg, _ := newMessage(`{"meta":{"topic":"t"},"data":{}}`)
+	msg.Attributes = map[string]string{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"}
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{msg}}))
+	assert.Equal(t, traceID, trace.SpanContextFromContext(handler.ctx).TraceID().String())
+}
+
+type loggingHandler struct{}
+
+func (h *loggingHandler) Supports(topic string) bool { return true }
+
+func (h *loggingHandler) Handle(ctx context.Context, event *types.Event) error {
+	slog.InfoContext(ctx, "processando")
+	return nil
+}
+
+func TestDispatcher_Handle_IdentificaMensagemNosLogs(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, types.LogConfig{Format: "json"}))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(&loggingHandler{}, dispatcher.HandlerOptions{Name: "audit"})
+	msg, _ := newMessage(`{"meta":{"topic":"t"},"data":{}}`)
+	attempt := 2
+	msg.DeliveryAttempt = &attempt
+	msg.Attributes = map[string]string{logging.RequestIDAttribute: "req-1"}
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{msg}}))
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line), buf.String())
+	assert.Equal(t, "processando", line["msg"])
+	assert.Equal(t, "fake-sub", line["subscription"])
+	assert.Equal(t, "1", line["message_id"])
+	assert.Equal(t, 2.0, line["attempt"])
+	assert.Equal(t, "audit", line["handler"])
+	assert.Equal(t, "req-1", line["request_id"])
+}
+
+func TestDispatcher_Handle_ErroPermanenteConfirma(t *testing.T) {
+	permanent := &recordingHandler{topics: []string{"notifications"}, err: structs.NewPermanentError(errors.New("inválido"))}
+	temporary := &recordingHandler{topics: []string{"audit"}, err: errors.New("indisponível")}
+	d := dispatcher.NewDispatcher()
+	d.Register(permanent)
+	d.Register(temporary)
+
+	onlyPermanent, permanentResult := newMessage(`{"meta":{"topic":"notifications"},"data":{}}`)
+	err := d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{onlyPermanent}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ack", *permanentResult)
+
+	// Com uma falha temporária junto, a mensagem volta para nova entrega
+	temporary.topics = append(temporary.topics, "notifications")
+	mixed, mixedResult := newMessage(`{"meta":{"topic":"notifications"},"data":{}}`)
+	err = d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{mixed}})
+	assert.NoError(t, err)
+	assert.Equal(t, "nack", *mixedResult)
+}
+
+type fakeDeadLetter struct {
+	failures  []types.DeliveryFailure
+	send      bool
+	succeeded int
+}
+
+func (q *fakeDeadLetter) Failed(ctx context.Context, failure types.DeliveryFailure) (bool, error) {
+	q.failures = append(q.failures, failure)
+	return q.send, nil
+}
+
+func (q *fakeDeadLetter) Succeeded(subscriptionID string, msg *types.Message) {
+	q.succeeded++
+}
+
+func TestDispatcher_Handle_DeadLetter(t *testing.T) {
+	ok := &recordingHandler{topics: []string{"notifications"}}
+	failing := &recordingHandler{topics: []string{"audit"}, err: errors.New("erro")}
+	queue := &fakeDeadLetter{}
+	d := dispatcher.NewDispatcher()
+	d.Register(ok)
+	d.Register(failing)
+	d.SetDeadLetterQueue(queue)
+
+	acked, ackResult := newMessage(`{"meta":{"topic":"notifications"},"data":{}}`)
+	retried, retriedResult := newMessage(`{"meta":{"topic":"audit"},"data":{}}`)
+	err := d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{acked, retried}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ack", *ackResult)
+	assert.Equal(t, "nack", *retriedResult)
+	assert.Equal(t, 1, queue.succeeded)
+
+	queue.send = true
+	deadLettered, deadResult := newMessage(`{not-json}`)
+	err = d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{deadLettered}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ack", *deadResult)
+
+	assert.Len(t, queue.failures, 2)
+	assert.Equal(t, "audit", queue.failures[0].Topic)
+	assert.Equal(t, "fake-sub", queue.failures[0].SubscriptionID)
+	assert.Empty(t, queue.failures[1].Topic)
+	assert.False(t, queue.failures[1].Permanent)
+}
+
+type slowHandler struct {
+	delay       time.Duration
+	ignoreCtx   bool
+	err         error
+	ctxCanceled chan bool
+}
+
+func (h *slowHandler) Supports(topic string) bool { return true }
+
+func (h *slowHandler) Handle(ctx context.Context, event *types.Event) error {
+	if h.ignoreCtx {
+		time.Sleep(h.delay)
+		return nil
+	}
+	select {
+	case <-time.After(h.delay):
+		return nil
+	case <-ctx.Done():
+		h.ctxCanceled <- true
+		if h.err != nil {
+			return h.err
+		}
+		return ctx.Err()
+	}
+}
+
+func TestDispatcher_TimeoutCancelaContextoDoHandler(t *testing.T) {
+	handler := &slowHandler{delay: time.Second, ctxCanceled: make(chan bool, 1)}
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(handler, dispatcher.HandlerOptions{Timeout: 20 * time.Millisecond})
+
+	err := d.Dispatch(context.Background(), &types.Event{ID: "1", Topic: "t"})
+	assert.ErrorIs(t, err, dispatcher.ErrHandlerTimeout)
+	assert.True(t, <-handler.ctxCanceled)
+}
+
+func TestDispatcher_TimeoutNaoEsperaHandlerQueIgnoraContexto(t *testing.T) {
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(&slowHandler{delay: 500 * time.Millisecond, ignoreCtx: true}, dispatcher.HandlerOptions{Timeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	err := d.Dispatch(context.Background(), &types.Event{ID: "1", Topic: "t"})
+	assert.ErrorIs(t, err, dispatcher.ErrHandlerTimeout)
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestDispatcher_Handle_TimeoutDevolveMensagem(t *testing.T) {
+	// Mesmo que o handler transforme o cancelamento em erro permanente, o tempo limite leva ao Nack
+	handler := &slowHandler{delay: time.Second, err: structs.NewPermanentError(errors.New("cancelado")), ctxCanceled: make(chan bool, 1)}
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(handler, dispatcher.HandlerOptions{Timeout: 20 * time.Millisecond})
+
+	msg, result := newMessage(`{"meta":{"topic":"t"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{msg}}))
+	assert.Equal(t, "nack", *result)
+}
+
+func TestDispatcher_TimeoutBestEffortConfirma(t *testing.T) {
+	d := dispatcher.NewDispatcher()
+	d.RegisterWithOptions(&slowHandler{delay: time.Second, ctxCanceled: make(chan bool, 1)}, dispatcher.HandlerOptions{
+		Policy:  enum.BestEffortHandler,
+		Timeout: 20 * time.Millisecond,
+	})
+
+	msg, result := newMessage(`{"meta":{"topic":"t"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{msg}}))
+	assert.Equal(t, "ack", *result)
+}
+
+func TestDispatcher_Handle_ReportaDestinoAoMonitor(t *testing.T) {
+	ok := &recordingHandler{topics: []string{"notifications"}}
+	failing := &recordingHandler{topics: []string{"audit"}, err: errors.New("erro")}
+	permanent := &recordingHandler{topics: []string{"invalido"}, err: structs.NewPermanentError(errors.New("dados inválidos"))}
+	m := monitor.NewMonitor()
+	d := dispatcher.NewDispatcher()
+	d.Register(ok)
+	d.Register(failing)
+	d.Register(permanent)
+	d.SetMonitor(m)
+
+	acked, _ := newMessage(`{"meta":{"topic":"notifications"},"data":{}}`)
+	nacked, _ := newMessage(`{"meta":{"topic":"audit"},"data":{}}`)
+	discarded, _ := newMessage(`{"meta":{"topic":"invalido"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{acked, nacked, discarded}}))
+
+	queue := &fakeDeadLetter{send: true}
+	d.SetDeadLetterQueue(queue)
+	deadLettered, _ := newMessage(`{"meta":{"topic":"audit"},"data":{}}`)
+	assert.NoError(t, d.Handle(context.Background(), &fakeSubscription{messages: []*types.Message{deadLettered}}))
+
+	status := m.Health().Subscriptions[0]
+	assert.Equal(t, "fake-sub", status.ID)
+	assert.Equal(t, map[enum.MessageOutcomeEnum]int64{
+		enum.MessageAcked:        1,
+		enum.MessageNacked:       1,
+		enum.MessageDiscarded:    1,
+		enum.MessageDeadLettered: 1,
+	}, status.Messages)
+	assert.Contains(t, status.LastHandlerError, "erro")
+}
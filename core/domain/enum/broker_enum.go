@@ -0,0 +1,8 @@
+package enum
+
+type BrokerEnum string
+
+const (
+	PubSubBroker BrokerEnum = "pubsub"
+	MemoryBroker BrokerEnum = "memory"
+)
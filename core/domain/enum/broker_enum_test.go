@@ -0,0 +1,29 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestBrokerEnum(t *testing.T) {
+	tests := map[string]struct {
+		broker   enum.BrokerEnum
+		expected enum.BrokerEnum
+	}{
+		"Test PubSub Broker": {
+			broker:   enum.PubSubBroker,
+			expected: "pubsub",
+		},
+		"Test Memory Broker": {
+			broker:   enum.MemoryBroker,
+			expected: "memory",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.broker)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package enum
+
+// HandlerPolicyEnum define como a falha de um handler afeta o ack da mensagem no Dispatcher.
+type HandlerPolicyEnum string
+
+const (
+	// RequiredHandler falha a mensagem (Nack) quando o handler retorna erro.
+	RequiredHandler HandlerPolicyEnum = "required"
+	// BestEffortHandler apenas registra o erro; a mensagem é confirmada se os handlers obrigatórios tiverem sucesso.
+	BestEffortHandler HandlerPolicyEnum = "best-effort"
+)
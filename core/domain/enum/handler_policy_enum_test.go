@@ -0,0 +1,12 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestHandlerPolicyEnum(t *testing.T) {
+	assert.Equal(t, enum.HandlerPolicyEnum("required"), enum.RequiredHandler)
+	assert.Equal(t, enum.HandlerPolicyEnum("best-effort"), enum.BestEffortHandler)
+}
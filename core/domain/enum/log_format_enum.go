@@ -0,0 +1,11 @@
+package enum
+
+// LogFormatEnum define o formato das linhas de log.
+type LogFormatEnum string
+
+const (
+	// JSONLogFormat grava um objeto JSON por linha, para os coletores de log em produção.
+	JSONLogFormat LogFormatEnum = "json"
+	// TextLogFormat grava pares chave=valor, mais fáceis de ler no terminal.
+	TextLogFormat LogFormatEnum = "text"
+)
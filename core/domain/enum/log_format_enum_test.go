@@ -0,0 +1,12 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestLogFormatEnum(t *testing.T) {
+	assert.Equal(t, enum.LogFormatEnum("json"), enum.JSONLogFormat)
+	assert.Equal(t, enum.LogFormatEnum("text"), enum.TextLogFormat)
+}
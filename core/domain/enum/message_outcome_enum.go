@@ -0,0 +1,15 @@
+package enum
+
+// MessageOutcomeEnum é o destino dado pelo Dispatcher a uma mensagem recebida.
+type MessageOutcomeEnum string
+
+const (
+	// MessageAcked indica que todos os handlers obrigatórios tiveram sucesso.
+	MessageAcked MessageOutcomeEnum = "acked"
+	// MessageNacked indica que a mensagem falhou e foi devolvida para nova entrega.
+	MessageNacked MessageOutcomeEnum = "nacked"
+	// MessageDeadLettered indica que a mensagem falhou e foi enviada ao tópico de dead-letter.
+	MessageDeadLettered MessageOutcomeEnum = "dead-lettered"
+	// MessageDiscarded indica uma falha permanente confirmada sem dead-letter configurado.
+	MessageDiscarded MessageOutcomeEnum = "discarded"
+)
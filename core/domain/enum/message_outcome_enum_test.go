@@ -0,0 +1,14 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestMessageOutcomeEnum(t *testing.T) {
+	assert.Equal(t, enum.MessageOutcomeEnum("acked"), enum.MessageAcked)
+	assert.Equal(t, enum.MessageOutcomeEnum("nacked"), enum.MessageNacked)
+	assert.Equal(t, enum.MessageOutcomeEnum("dead-lettered"), enum.MessageDeadLettered)
+	assert.Equal(t, enum.MessageOutcomeEnum("discarded"), enum.MessageDiscarded)
+}
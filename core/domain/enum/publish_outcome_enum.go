@@ -0,0 +1,17 @@
+package enum
+
+// PublishOutcomeEnum é o resultado de uma mensagem recebida para publicação.
+type PublishOutcomeEnum string
+
+const (
+	// PublishPublished indica que o broker confirmou a publicação.
+	PublishPublished PublishOutcomeEnum = "published"
+	// PublishScheduled indica que a mensagem foi guardada para entrega futura.
+	PublishScheduled PublishOutcomeEnum = "scheduled"
+	// PublishReplayed indica uma chave de idempotência já usada; nada foi publicado de novo.
+	PublishReplayed PublishOutcomeEnum = "replayed"
+	// PublishRejected indica um tópico fora do registro ou dados que não seguem o schema.
+	PublishRejected PublishOutcomeEnum = "rejected"
+	// PublishFailed indica erro do broker ou do agendamento.
+	PublishFailed PublishOutcomeEnum = "failed"
+)
@@ -0,0 +1,15 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestPublishOutcomeEnum(t *testing.T) {
+	assert.Equal(t, enum.PublishOutcomeEnum("published"), enum.PublishPublished)
+	assert.Equal(t, enum.PublishOutcomeEnum("scheduled"), enum.PublishScheduled)
+	assert.Equal(t, enum.PublishOutcomeEnum("replayed"), enum.PublishReplayed)
+	assert.Equal(t, enum.PublishOutcomeEnum("rejected"), enum.PublishRejected)
+	assert.Equal(t, enum.PublishOutcomeEnum("failed"), enum.PublishFailed)
+}
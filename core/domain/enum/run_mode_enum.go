@@ -0,0 +1,13 @@
+package enum
+
+// RunModeEnum define quais partes do serviço são iniciadas no processo.
+type RunModeEnum string
+
+const (
+	// PublisherMode sobe a API de publicação, o agendador e a API de dead-letter.
+	PublisherMode RunModeEnum = "publisher"
+	// SubscriberMode consome as assinaturas, expondo apenas o health check por HTTP.
+	SubscriberMode RunModeEnum = "subscriber"
+	// AllMode executa publicação e consumo no mesmo processo.
+	AllMode RunModeEnum = "all"
+)
@@ -0,0 +1,13 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestRunModeEnum(t *testing.T) {
+	assert.Equal(t, enum.RunModeEnum("publisher"), enum.PublisherMode)
+	assert.Equal(t, enum.RunModeEnum("subscriber"), enum.SubscriberMode)
+	assert.Equal(t, enum.RunModeEnum("all"), enum.AllMode)
+}
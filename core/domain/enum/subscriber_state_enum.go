@@ -0,0 +1,13 @@
+package enum
+
+// SubscriberStateEnum é o estado do receptor de uma assinatura.
+type SubscriberStateEnum string
+
+const (
+	// SubscriberRunning indica que o receptor está consumindo a assinatura.
+	SubscriberRunning SubscriberStateEnum = "running"
+	// SubscriberRestarting indica que o receptor encerrou com falha e aguarda o backoff para ser reiniciado.
+	SubscriberRestarting SubscriberStateEnum = "restarting"
+	// SubscriberStopped indica que o receptor foi encerrado pelo desligamento do serviço.
+	SubscriberStopped SubscriberStateEnum = "stopped"
+)
@@ -0,0 +1,13 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestSubscriberStateEnum(t *testing.T) {
+	assert.Equal(t, enum.SubscriberStateEnum("running"), enum.SubscriberRunning)
+	assert.Equal(t, enum.SubscriberStateEnum("restarting"), enum.SubscriberRestarting)
+	assert.Equal(t, enum.SubscriberStateEnum("stopped"), enum.SubscriberStopped)
+}
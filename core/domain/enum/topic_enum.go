@@ -1,8 +0,0 @@
-package enum
-
-type TopicEnum string
-
-const (
-	Notification    TopicEnum = "notifications-sub"
-	HmlNotification TopicEnum = "hml-notifications-sub"
-)
@@ -0,0 +1,13 @@
+package enum
+
+// TracingExporterEnum indica para onde os spans são enviados.
+type TracingExporterEnum string
+
+const (
+	// NoTracingExporter desliga o tracing; o contexto recebido ainda é repassado adiante.
+	NoTracingExporter TracingExporterEnum = "none"
+	// StdoutTracingExporter imprime os spans na saída padrão, para uso local.
+	StdoutTracingExporter TracingExporterEnum = "stdout"
+	// OTLPTracingExporter envia os spans por OTLP/HTTP ao endpoint de OTEL_EXPORTER_OTLP_ENDPOINT.
+	OTLPTracingExporter TracingExporterEnum = "otlp"
+)
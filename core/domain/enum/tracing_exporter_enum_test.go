@@ -0,0 +1,13 @@
+package enum_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
+	"testing"
+)
+
+func TestTracingExporterEnum(t *testing.T) {
+	assert.Equal(t, enum.TracingExporterEnum("none"), enum.NoTracingExporter)
+	assert.Equal(t, enum.TracingExporterEnum("stdout"), enum.StdoutTracingExporter)
+	assert.Equal(t, enum.TracingExporterEnum("otlp"), enum.OTLPTracingExporter)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"queue/core/domain/types"
+)
+
+// IDeadLetterQueue decide quando uma mensagem com falha deve deixar a assinatura e a republica no tópico de dead-letter.
+type IDeadLetterQueue interface {
+	// Failed registra a falha e retorna true quando a mensagem foi enviada ao dead-letter e pode ser confirmada.
+	Failed(ctx context.Context, failure types.DeliveryFailure) (bool, error)
+	// Succeeded descarta a contagem local de tentativas da mensagem.
+	Succeeded(subscriptionID string, msg *types.Message)
+}
+
+// IDeadLetterAware é implementado pelos handlers de assinatura que encaminham falhas ao dead-letter.
+type IDeadLetterAware interface {
+	SetDeadLetterQueue(queue IDeadLetterQueue)
+}
@@ -0,0 +1,41 @@
+package interfaces_test
+
+import (
+	"context"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingDeadLetter struct {
+	failures int
+}
+
+func (q *countingDeadLetter) Failed(ctx context.Context, failure types.DeliveryFailure) (bool, error) {
+	q.failures++
+	return failure.Permanent, nil
+}
+
+func (q *countingDeadLetter) Succeeded(subscriptionID string, msg *types.Message) {}
+
+type deadLetterHandler struct {
+	queue interfaces.IDeadLetterQueue
+}
+
+func (h *deadLetterHandler) SetDeadLetterQueue(queue interfaces.IDeadLetterQueue) {
+	h.queue = queue
+}
+
+func TestIDeadLetterQueue(t *testing.T) {
+	queue := &countingDeadLetter{}
+	handler := &deadLetterHandler{}
+	var aware interfaces.IDeadLetterAware = handler
+	aware.SetDeadLetterQueue(queue)
+
+	sent, err := handler.queue.Failed(context.Background(), types.DeliveryFailure{Permanent: true})
+	assert.NoError(t, err)
+	assert.True(t, sent)
+	assert.Equal(t, 1, queue.failures)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "time"
+
+// IDedupStore guarda as chaves dos eventos já processados pelos handlers de assinatura.
+// Seen não deve considerar chaves expiradas.
+type IDedupStore interface {
+	Seen(key string) (bool, error)
+	Mark(key string, ttl time.Duration) error
+}
@@ -0,0 +1,38 @@
+package interfaces_test
+
+import (
+	"queue/core/domain/interfaces"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapDedupStore struct {
+	keys map[string]time.Time
+}
+
+func (s *mapDedupStore) Seen(key string) (bool, error) {
+	expiresAt, ok := s.keys[key]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (s *mapDedupStore) Mark(key string, ttl time.Duration) error {
+	s.keys[key] = time.Now().Add(ttl)
+	return nil
+}
+
+func TestIDedupStore(t *testing.T) {
+	var store interfaces.IDedupStore = &mapDedupStore{keys: map[string]time.Time{}}
+	seen, err := store.Seen("k")
+	assert.NoError(t, err)
+	assert.False(t, seen)
+
+	assert.NoError(t, store.Mark("k", time.Hour))
+	seen, _ = store.Seen("k")
+	assert.True(t, seen)
+
+	assert.NoError(t, store.Mark("expirada", -time.Second))
+	seen, _ = store.Seen("expirada")
+	assert.False(t, seen)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+)
+
+// IEventHandler processa eventos dos tópicos para os quais Supports retorna true. Handle deve ser idempotente.
+type IEventHandler interface {
+	Supports(topic string) bool
+	Handle(ctx context.Context, event *types.Event) error
+}
+
+// IDispatcher entrega cada evento a todos os handlers registrados que suportam o seu tópico.
+type IDispatcher interface {
+	Register(handler IEventHandler)
+	RegisterWithPolicy(handler IEventHandler, policy enum.HandlerPolicyEnum)
+	Dispatch(ctx context.Context, event *types.Event) error
+}
+
+// EventHandlerMiddleware envolve cada handler montado pela estratégia de assinaturas, recebendo
+// a assinatura e o nome com que o handler foi configurado.
+type EventHandlerMiddleware func(subscriptionID string, name string, handler IEventHandler) IEventHandler
@@ -0,0 +1,40 @@
+package interfaces_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+)
+
+type TopicHandler struct {
+	Topic   string
+	Handled []string
+}
+
+func (h *TopicHandler) Supports(topic string) bool { return topic == h.Topic }
+
+func (h *TopicHandler) Handle(ctx context.Context, event *types.Event) error {
+	h.Handled = append(h.Handled, event.ID)
+	return nil
+}
+
+func TestIEventHandler(t *testing.T) {
+	var handler interfaces.IEventHandler = &TopicHandler{Topic: "user.created"}
+	assert.True(t, handler.Supports("user.created"))
+	assert.False(t, handler.Supports("user.deleted"))
+	assert.NoError(t, handler.Handle(context.Background(), &types.Event{ID: "1"}))
+	assert.Equal(t, []string{"1"}, handler.(*TopicHandler).Handled)
+}
+
+func TestEventHandlerMiddleware(t *testing.T) {
+	var middleware interfaces.EventHandlerMiddleware = func(subscriptionID string, name string, handler interfaces.IEventHandler) interfaces.IEventHandler {
+		assert.Equal(t, "sub", subscriptionID)
+		assert.Equal(t, "handler", name)
+		return handler
+	}
+	inner := &TopicHandler{Topic: "user.created"}
+	assert.Same(t, inner, middleware("sub", "handler", inner))
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "queue/core/domain/types"
+
+// IIdempotencyStore não deve retornar registros expirados em Get.
+type IIdempotencyStore interface {
+	Get(key string) (*types.IdempotencyRecord, error)
+	Save(record types.IdempotencyRecord) error
+}
@@ -1,8 +1,8 @@
 package interfaces
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
+	"queue/core/domain/types"
 )
 
 type IPubSubClient interface {
@@ -18,12 +18,12 @@ type ISubscriptionIterator interface {
 type ITopic interface {
 	ID() string
 	Stop()
-	Publish(ctx context.Context, msg *pubsub.Message) IPublishResult
+	Publish(ctx context.Context, msg *types.Message) IPublishResult
 }
 
 type ISubscription interface {
 	ID() string
-	Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error
+	Receive(ctx context.Context, f func(context.Context, *types.Message)) error
 }
 
 type IPublishResult interface {
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"testing"
 
-	"cloud.google.com/go/pubsub"
 	"github.com/stretchr/testify/assert"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
 )
 
 type DemoSubscription struct {
@@ -17,7 +17,7 @@ type DemoSubscription struct {
 func (d *DemoSubscription) ID() string {
 	return d.tag
 }
-func (d *DemoSubscription) Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error {
+func (d *DemoSubscription) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
 	return nil
 }
 
@@ -37,7 +37,7 @@ type ChannelTopic struct {
 
 func (c *ChannelTopic) ID() string { return c.name }
 func (c *ChannelTopic) Stop()      {}
-func (c *ChannelTopic) Publish(ctx context.Context, msg *pubsub.Message) interfaces.IPublishResult {
+func (c *ChannelTopic) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
 	if c.failSend {
 		return &ResultStub{id: "", err: errors.New("publish error")}
 	}
@@ -104,14 +104,14 @@ func TestDemoPubSubClient_Flow(t *testing.T) {
 	// Topic and publish
 	topic := client.Topic("foo")
 	assert.Equal(t, "foo", topic.ID())
-	res := topic.Publish(context.Background(), &pubsub.Message{Data: []byte("test")})
+	res := topic.Publish(context.Background(), &types.Message{Data: []byte("test")})
 	id, err := res.Get(context.Background())
 	assert.NoError(t, err)
 	assert.Contains(t, id, "id-foo")
 
 	// Publish error route
 	failTopic := &ChannelTopic{name: "fail", failSend: true}
-	failRes := failTopic.Publish(context.Background(), &pubsub.Message{})
+	failRes := failTopic.Publish(context.Background(), &types.Message{})
 	_, err = failRes.Get(context.Background())
 	assert.EqualError(t, err, "publish error")
 
@@ -0,0 +1,8 @@
+package interfaces
+
+import "queue/core/domain/types"
+
+// IReceiveSettingsAware é implementado pelas assinaturas que aceitam controle de fluxo no recebimento.
+type IReceiveSettingsAware interface {
+	SetReceiveSettings(settings types.ReceiveSettings)
+}
@@ -0,0 +1,24 @@
+package interfaces_test
+
+import (
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settingsSubscription struct {
+	settings types.ReceiveSettings
+}
+
+func (s *settingsSubscription) SetReceiveSettings(settings types.ReceiveSettings) {
+	s.settings = settings
+}
+
+func TestIReceiveSettingsAware(t *testing.T) {
+	sub := &settingsSubscription{}
+	var aware interfaces.IReceiveSettingsAware = sub
+	aware.SetReceiveSettings(types.ReceiveSettings{MaxOutstandingMessages: 5})
+	assert.Equal(t, 5, sub.settings.MaxOutstandingMessages)
+}
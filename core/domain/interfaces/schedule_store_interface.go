@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"queue/core/domain/types"
+	"time"
+)
+
+// IScheduleStore guarda os agendamentos pendentes. Claim reserva os vencidos para publicação: a partir
+// daí eles saem de List, e Delete retorna types.ErrScheduleInFlight, até que Complete os remova ou Release
+// os devolva após uma falha. As reservas não sobrevivem a um reinício, para que nada seja perdido.
+type IScheduleStore interface {
+	Save(msg types.ScheduledMessage) error
+	List() ([]types.ScheduledMessage, error)
+	Delete(id string) (bool, error)
+	Claim(now time.Time) ([]types.ScheduledMessage, error)
+	Release(id string) error
+	Complete(id string) error
+}
+
+type IScheduler interface {
+	Schedule(topic string, msg *types.Message, deliverAt time.Time) (*types.ScheduledMessage, error)
+}
@@ -0,0 +1,58 @@
+package interfaces_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+)
+
+type SliceScheduleStore struct {
+	items []types.ScheduledMessage
+}
+
+func (s *SliceScheduleStore) Save(msg types.ScheduledMessage) error {
+	s.items = append(s.items, msg)
+	return nil
+}
+func (s *SliceScheduleStore) List() ([]types.ScheduledMessage, error) { return s.items, nil }
+func (s *SliceScheduleStore) Delete(id string) (bool, error) {
+	for i, m := range s.items {
+		if m.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (s *SliceScheduleStore) Claim(now time.Time) ([]types.ScheduledMessage, error) {
+	var due []types.ScheduledMessage
+	for _, m := range s.items {
+		if !m.DeliverAt.After(now) {
+			due = append(due, m)
+		}
+	}
+	return due, nil
+}
+func (s *SliceScheduleStore) Release(id string) error { return nil }
+func (s *SliceScheduleStore) Complete(id string) error {
+	_, err := s.Delete(id)
+	return err
+}
+
+func TestIScheduleStore_Flow(t *testing.T) {
+	var store interfaces.IScheduleStore = &SliceScheduleStore{}
+	now := time.Now()
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "a", DeliverAt: now}))
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "b", DeliverAt: now.Add(time.Hour)}))
+
+	due, err := store.Claim(now)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	assert.NoError(t, store.Complete("a"))
+	list, _ := store.List()
+	assert.Len(t, list, 1)
+}
@@ -1,5 +1,8 @@
 package interfaces
 
+import "context"
+
+// ISubscribeHandler consome a assinatura até o contexto ser cancelado ou o receptor encerrar.
 type ISubscribeHandler interface {
-	Handle(sub ISubscription) error
+	Handle(ctx context.Context, sub ISubscription) error
 }
@@ -5,9 +5,9 @@ import (
 	"errors"
 	"testing"
 
-	"cloud.google.com/go/pubsub"
 	"github.com/stretchr/testify/assert"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
 )
 
 type SampleSubscription struct {
@@ -17,7 +17,7 @@ type SampleSubscription struct {
 func (s *SampleSubscription) ID() string {
 	return s.Identifier
 }
-func (s *SampleSubscription) Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error {
+func (s *SampleSubscription) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
 	return nil
 }
 
@@ -26,7 +26,7 @@ type SimpleHandler struct {
 	ShouldError error
 }
 
-func (sh *SimpleHandler) Handle(sub interfaces.ISubscription) error {
+func (sh *SimpleHandler) Handle(ctx context.Context, sub interfaces.ISubscription) error {
 	sh.Handled = true
 	return sh.ShouldError
 }
@@ -35,7 +35,7 @@ func TestISubscribeHandler_Handle_Success(t *testing.T) {
 	handler := &SimpleHandler{}
 	sub := &SampleSubscription{Identifier: "sub-x"}
 
-	err := handler.Handle(sub)
+	err := handler.Handle(context.Background(), sub)
 	assert.True(t, handler.Handled, "Deveria marcar Handled como true")
 	assert.NoError(t, err)
 }
@@ -45,7 +45,7 @@ func TestISubscribeHandler_Handle_Error(t *testing.T) {
 	handler := &SimpleHandler{ShouldError: expectedErr}
 	sub := &SampleSubscription{Identifier: "sub-y"}
 
-	err := handler.Handle(sub)
+	err := handler.Handle(context.Background(), sub)
 	assert.True(t, handler.Handled, "Deveria marcar Handled como true")
 	assert.EqualError(t, err, "falha simulada")
 }
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+)
+
+// ISubscriptionMonitor acompanha os receptores das assinaturas e o destino de cada mensagem,
+// para que as falhas de uma assinatura fiquem visíveis sem interromper as demais.
+type ISubscriptionMonitor interface {
+	// SubscriptionsListed registra o resultado da listagem das assinaturas; nil indica sucesso.
+	SubscriptionsListed(err error)
+	ReceiverStarted(subscriptionID string)
+	// ReceiverFailed registra um receptor que encerrou antes do desligamento e será reiniciado.
+	ReceiverFailed(subscriptionID string, err error)
+	ReceiverStopped(subscriptionID string)
+	// MessageHandled registra o destino da mensagem e, quando houver, o erro dos handlers.
+	MessageHandled(subscriptionID string, outcome enum.MessageOutcomeEnum, err error)
+	Health() types.SubscriberHealth
+}
+
+// ISubscriptionMonitorAware é implementado pelos handlers de assinatura que reportam o destino das mensagens.
+type ISubscriptionMonitorAware interface {
+	SetMonitor(monitor ISubscriptionMonitor)
+}
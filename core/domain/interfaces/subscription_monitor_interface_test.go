@@ -0,0 +1,44 @@
+package interfaces_test
+
+import (
+	"queue/core/domain/enum"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingMonitor struct {
+	outcomes []enum.MessageOutcomeEnum
+}
+
+func (m *countingMonitor) SubscriptionsListed(err error)                   {}
+func (m *countingMonitor) ReceiverStarted(subscriptionID string)           {}
+func (m *countingMonitor) ReceiverFailed(subscriptionID string, err error) {}
+func (m *countingMonitor) ReceiverStopped(subscriptionID string)           {}
+func (m *countingMonitor) MessageHandled(subscriptionID string, outcome enum.MessageOutcomeEnum, err error) {
+	m.outcomes = append(m.outcomes, outcome)
+}
+func (m *countingMonitor) Health() types.SubscriberHealth {
+	return types.SubscriberHealth{Status: "ok"}
+}
+
+type monitoredHandler struct {
+	monitor interfaces.ISubscriptionMonitor
+}
+
+func (h *monitoredHandler) SetMonitor(monitor interfaces.ISubscriptionMonitor) {
+	h.monitor = monitor
+}
+
+func TestISubscriptionMonitor(t *testing.T) {
+	m := &countingMonitor{}
+	handler := &monitoredHandler{}
+	var aware interfaces.ISubscriptionMonitorAware = handler
+	aware.SetMonitor(m)
+
+	handler.monitor.MessageHandled("sub", enum.MessageAcked, nil)
+	assert.Equal(t, []enum.MessageOutcomeEnum{enum.MessageAcked}, m.outcomes)
+	assert.Equal(t, "ok", handler.monitor.Health().Status)
+}
@@ -1,6 +1,7 @@
 package interfaces_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func (m *MockHandlerStrategy) GetHandler(topic string) interfaces.ISubscribeHand
 
 type DummyHandler struct{}
 
-func (d *DummyHandler) Handle(sub interfaces.ISubscription) error {
+func (d *DummyHandler) Handle(ctx context.Context, sub interfaces.ISubscription) error {
 	return nil
 }
 
@@ -0,0 +1,7 @@
+package interfaces
+
+// ITopicRegistry define quais tópicos podem receber publicações e valida o campo data de cada um.
+type ITopicRegistry interface {
+	Validate(topic string, data interface{}) error
+	Has(topic string) bool
+}
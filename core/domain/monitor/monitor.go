@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// Monitor implementa interfaces.ISubscriptionMonitor em memória.
+type Monitor struct {
+	mu            sync.Mutex
+	listError     string
+	subscriptions map[string]*types.SubscriptionStatus
+}
+
+func NewMonitor() *Monitor {
+	return &Monitor{subscriptions: map[string]*types.SubscriptionStatus{}}
+}
+
+func (m *Monitor) SubscriptionsListed(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listError = ""
+	if err != nil {
+		m.listError = err.Error()
+	}
+}
+
+func (m *Monitor) ReceiverStarted(subscriptionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status(subscriptionID).State = enum.SubscriberRunning
+}
+
+func (m *Monitor) ReceiverFailed(subscriptionID string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status(subscriptionID)
+	status.State = enum.SubscriberRestarting
+	status.Restarts++
+	status.LastReceiverError = "receptor encerrado sem erro"
+	if err != nil {
+		status.LastReceiverError = err.Error()
+	}
+	now := time.Now()
+	status.LastErrorAt = &now
+}
+
+func (m *Monitor) ReceiverStopped(subscriptionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status(subscriptionID).State = enum.SubscriberStopped
+}
+
+func (m *Monitor) MessageHandled(subscriptionID string, outcome enum.MessageOutcomeEnum, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status(subscriptionID)
+	status.Messages[outcome]++
+	now := time.Now()
+	status.LastMessageAt = &now
+	if err != nil {
+		status.LastHandlerError = err.Error()
+		status.LastErrorAt = &now
+	}
+}
+
+// Health retorna uma cópia do estado atual, com as assinaturas ordenadas pelo ID.
+func (m *Monitor) Health() types.SubscriberHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	health := types.SubscriberHealth{
+		Status:        HealthOK,
+		ListError:     m.listError,
+		Subscriptions: make([]types.SubscriptionStatus, 0, len(m.subscriptions)),
+	}
+	if m.listError != "" {
+		health.Status = HealthDegraded
+	}
+	for _, status := range m.subscriptions {
+		if status.State != enum.SubscriberRunning {
+			health.Status = HealthDegraded
+		}
+		copied := *status
+		copied.Messages = make(map[enum.MessageOutcomeEnum]int64, len(status.Messages))
+		for outcome, count := range status.Messages {
+			copied.Messages[outcome] = count
+		}
+		health.Subscriptions = append(health.Subscriptions, copied)
+	}
+	slices.SortFunc(health.Subscriptions, func(a, b types.SubscriptionStatus) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return health
+}
+
+func (m *Monitor) status(subscriptionID string) *types.SubscriptionStatus {
+	status, ok := m.subscriptions[subscriptionID]
+	if !ok {
+		status = &types.SubscriptionStatus{ID: subscriptionID, Messages: map[enum.MessageOutcomeEnum]int64{}}
+		m.subscriptions[subscriptionID] = status
+	}
+	return status
+}
@@ -0,0 +1,82 @@
+package monitor_test
+
+import (
+	"errors"
+	"queue/core/domain/enum"
+	"queue/core/domain/monitor"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitor_ReceptoresAtivos(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.SubscriptionsListed(nil)
+	m.ReceiverStarted("sub-b")
+	m.ReceiverStarted("sub-a")
+
+	health := m.Health()
+	assert.Equal(t, monitor.HealthOK, health.Status)
+	assert.Len(t, health.Subscriptions, 2)
+	assert.Equal(t, "sub-a", health.Subscriptions[0].ID)
+	assert.Equal(t, enum.SubscriberRunning, health.Subscriptions[0].State)
+}
+
+func TestMonitor_ReceptorComFalhaDegrada(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.ReceiverStarted("sub-a")
+	m.ReceiverStarted("sub-b")
+	m.ReceiverFailed("sub-b", errors.New("conexão perdida"))
+
+	health := m.Health()
+	assert.Equal(t, monitor.HealthDegraded, health.Status)
+	failed := health.Subscriptions[1]
+	assert.Equal(t, enum.SubscriberRestarting, failed.State)
+	assert.Equal(t, 1, failed.Restarts)
+	assert.Equal(t, "conexão perdida", failed.LastReceiverError)
+	assert.NotNil(t, failed.LastErrorAt)
+	assert.Equal(t, enum.SubscriberRunning, health.Subscriptions[0].State)
+
+	m.ReceiverStarted("sub-b")
+	assert.Equal(t, monitor.HealthOK, m.Health().Status)
+	assert.Equal(t, 1, m.Health().Subscriptions[1].Restarts)
+}
+
+func TestMonitor_FalhaNaListagemDegrada(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.SubscriptionsListed(errors.New("sem permissão"))
+	health := m.Health()
+	assert.Equal(t, monitor.HealthDegraded, health.Status)
+	assert.Equal(t, "sem permissão", health.ListError)
+
+	m.SubscriptionsListed(nil)
+	assert.Equal(t, monitor.HealthOK, m.Health().Status)
+}
+
+func TestMonitor_ContaMensagens(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.ReceiverStarted("sub-a")
+	m.MessageHandled("sub-a", enum.MessageAcked, nil)
+	m.MessageHandled("sub-a", enum.MessageAcked, nil)
+	m.MessageHandled("sub-a", enum.MessageNacked, errors.New("handler falhou"))
+
+	health := m.Health()
+	status := health.Subscriptions[0]
+	assert.Equal(t, int64(2), status.Messages[enum.MessageAcked])
+	assert.Equal(t, int64(1), status.Messages[enum.MessageNacked])
+	assert.Equal(t, "handler falhou", status.LastHandlerError)
+	assert.NotNil(t, status.LastMessageAt)
+	// Falhas de mensagens não tornam o receptor indisponível
+	assert.Equal(t, monitor.HealthOK, health.Status)
+
+	// O retorno é uma cópia
+	status.Messages[enum.MessageAcked] = 100
+	assert.Equal(t, int64(2), m.Health().Subscriptions[0].Messages[enum.MessageAcked])
+}
+
+func TestMonitor_ReceptorEncerrado(t *testing.T) {
+	m := monitor.NewMonitor()
+	m.ReceiverStarted("sub-a")
+	m.ReceiverStopped("sub-a")
+	assert.Equal(t, enum.SubscriberStopped, m.Health().Subscriptions[0].State)
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"fmt"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"sort"
+	"sync"
+)
+
+// HandlerFactory cria a instância do handler a partir da configuração da aplicação.
+type HandlerFactory func(cfg *types.Config) interfaces.IEventHandler
+
+// HandlerRegistry associa nomes de handlers às suas fábricas, permitindo ligá-los às assinaturas por configuração.
+type HandlerRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]HandlerFactory
+}
+
+// Default é o registro usado pelos handlers que se registram no init do próprio pacote.
+var Default = NewHandlerRegistry()
+
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{factories: map[string]HandlerFactory{}}
+}
+
+// Register entra em pânico se o nome já estiver registrado, já que isso só acontece por erro de programação.
+func (r *HandlerRegistry) Register(name string, factory HandlerFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		panic(fmt.Sprintf("handler %s registrado mais de uma vez", name))
+	}
+	r.factories[name] = factory
+}
+
+func (r *HandlerRegistry) Build(name string, cfg *types.Config) (interfaces.IEventHandler, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("handler %s não registrado", name)
+	}
+	return factory(cfg), nil
+}
+
+func (r *HandlerRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Register adiciona o handler ao registro Default.
+func Register(name string, factory HandlerFactory) {
+	Default.Register(name, factory)
+}
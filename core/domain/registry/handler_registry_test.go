@@ -0,0 +1,42 @@
+package registry_test
+
+import (
+	"context"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/registry"
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedHandler struct {
+	env string
+}
+
+func (h *namedHandler) Supports(topic string) bool { return true }
+
+func (h *namedHandler) Handle(ctx context.Context, event *types.Event) error { return nil }
+
+func TestHandlerRegistry(t *testing.T) {
+	reg := registry.NewHandlerRegistry()
+	reg.Register("audit", func(cfg *types.Config) interfaces.IEventHandler {
+		return &namedHandler{env: cfg.Environment}
+	})
+	reg.Register("notification", func(cfg *types.Config) interfaces.IEventHandler { return &namedHandler{} })
+
+	handler, err := reg.Build("audit", &types.Config{Environment: "hml"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hml", handler.(*namedHandler).env)
+	assert.Equal(t, []string{"audit", "notification"}, reg.Names())
+
+	_, err = reg.Build("desconhecido", &types.Config{})
+	assert.EqualError(t, err, "handler desconhecido não registrado")
+}
+
+func TestHandlerRegistry_RegistroDuplicado(t *testing.T) {
+	reg := registry.NewHandlerRegistry()
+	factory := func(cfg *types.Config) interfaces.IEventHandler { return &namedHandler{} }
+	reg.Register("audit", factory)
+	assert.Panics(t, func() { reg.Register("audit", factory) })
+}
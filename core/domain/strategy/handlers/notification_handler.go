@@ -1,72 +1,103 @@
 package handlers
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"queue/core/application/subscription/dto"
 	"queue/core/domain/enum"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/registry"
+	"queue/core/domain/structs"
 	"queue/core/domain/types"
 	"queue/core/infra/http_service"
+	"queue/core/infra/metrics"
+	"queue/core/infra/retry"
+	"queue/core/infra/utils/functions"
+	"slices"
 )
 
+// NotificationHandlerName é o nome usado para ligar o handler às assinaturas na configuração.
+const NotificationHandlerName = "notification"
+
+func init() {
+	registry.Register(NotificationHandlerName, func(cfg *types.Config) interfaces.IEventHandler {
+		return NewNotificationHandler(cfg)
+	})
+}
+
+// NotificationHandler envia o evento para a API de notificações. Sem tópicos informados,
+// atende qualquer tópico entregue pela assinatura.
 type NotificationHandler struct {
 	Config      *types.Config
+	Topics      []string
+	Retry       types.RetryPolicy
 	httpService *httpservice.HttpService
 }
 
-func NewNotificationHandler(cfg *types.Config) *NotificationHandler {
-	return &NotificationHandler{
+func NewNotificationHandler(cfg *types.Config, topics ...string) *NotificationHandler {
+	handler := &NotificationHandler{
 		Config:      cfg,
+		Topics:      topics,
 		httpService: httpservice.NewHttpService(),
 	}
+	if cfg != nil {
+		handler.Retry = cfg.NotificationRetry
+	}
+	return handler
 }
 
-func (h *NotificationHandler) Handle(sub interfaces.ISubscription) error {
-	ctx := context.Background()
-	return sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
-		dto, err := parseNotificationMessage(msg.Data)
-		if err != nil {
-			msg.Nack()
-			return
-		}
-		if err := h.httpService.SendNotification(dto, h.Config); err != nil {
-			msg.Nack()
-			return
-		}
-		msg.Ack()
-	})
+func (h *NotificationHandler) Supports(topic string) bool {
+	if len(h.Topics) == 0 {
+		return true
+	}
+	return slices.Contains(h.Topics, topic)
 }
 
-func parseNotificationMessage(body []byte) (*subscriptiondto.CreateNotificationDto, error) {
-	// Extrai campo "data"
-	data, err := extractDataField(body)
+// Handle repete o envio conforme a política de retry. Dados inválidos e rejeições 4xx não
+// repetíveis retornam structs.PermanentError, pois uma nova entrega teria o mesmo resultado.
+// Uma resposta 2xx cujo corpo não pôde ser lido conta como sucesso: a API já aceitou a notificação.
+func (h *NotificationHandler) Handle(ctx context.Context, event *types.Event) error {
+	dto, err := parseNotificationData(event.Data)
 	if err != nil {
-		return nil, err
+		return structs.NewPermanentError(err)
 	}
-
-	// Converte para DTO
-	dto, err := parseNotificationData(data)
-	if err != nil {
-		return nil, err
+	err = retry.Do(ctx, h.Retry, h.isRetryable, func(attempt int) error {
+		err := h.httpService.SendNotification(ctx, dto, h.Config)
+		var decodeErr *functions.ResponseDecodeError
+		if errors.As(err, &decodeErr) {
+			slog.WarnContext(ctx, "Notificação aceita, mas a resposta da API não pôde ser lida", "event_id", event.ID, "error", err)
+			return nil
+		}
+		if err != nil && attempt < h.Retry.MaxAttempts && h.isRetryable(err) {
+			slog.WarnContext(ctx, "Tentativa de envio da notificação falhou", "event_id", event.ID, "notification_attempt", attempt, "max_attempts", h.Retry.MaxAttempts, "error", err)
+			metrics.NotificationRetried()
+		}
+		return err
+	})
+	if h.isPermanent(err) {
+		return structs.NewPermanentError(err)
 	}
-
-	return dto, nil
+	return err
 }
 
-func extractDataField(raw []byte) ([]byte, error) {
-	var envelope map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &envelope); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar envelope: %w", err)
+// isRetryable aceita os status configurados; erros de conexão chegam como status 500 e erros sem status são sempre repetidos.
+func (h *NotificationHandler) isRetryable(err error) bool {
+	var httpErr *functions.HttpException
+	if !errors.As(err, &httpErr) {
+		return true
 	}
-	data, ok := envelope["data"]
-	if !ok {
-		return nil, errors.New("campo 'data' não encontrado na mensagem")
+	return slices.Contains(h.Retry.RetryableStatus, httpErr.Status)
+}
+
+func (h *NotificationHandler) isPermanent(err error) bool {
+	var httpErr *functions.HttpException
+	if !errors.As(err, &httpErr) {
+		return false
 	}
-	return data, nil
+	return httpErr.Status >= 400 && httpErr.Status < 500 && !h.isRetryable(err)
 }
 
 func parseNotificationData(data []byte) (*subscriptiondto.CreateNotificationDto, error) {
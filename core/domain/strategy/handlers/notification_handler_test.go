@@ -1,43 +1,154 @@
 package handlers_test
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"queue/core/domain/strategy/handlers"
+	"queue/core/domain/structs"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"queue/core/domain/types"
 )
 
-type fakeSubscription struct {
-	callback func(context.Context, *pubsub.Message)
+func notificationAPI(t *testing.T, status int, received chan<- []byte) *types.Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if received != nil {
+			received <- body
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return &types.Config{URLs: types.URLsConfig{Notification: server.URL}}
 }
 
-func (f *fakeSubscription) ID() string { return "fake-sub" }
-func (f *fakeSubscription) Receive(ctx context.Context, fn func(context.Context, *pubsub.Message)) error {
-	f.callback = fn
-	return nil
+func TestNotificationHandler_Supports(t *testing.T) {
+	assert.True(t, handlers.NewNotificationHandler(&types.Config{}).Supports("qualquer"))
+
+	handler := handlers.NewNotificationHandler(&types.Config{}, "notifications")
+	assert.True(t, handler.Supports("notifications"))
+	assert.False(t, handler.Supports("audit"))
 }
 
-func TestNotificationHandler_Handle_ValidMessage(t *testing.T) {
-	sub := &fakeSubscription{}
-	handler := handlers.NewNotificationHandler(&types.Config{})
-	err := handler.Handle(sub)
-	if err != nil {
-		t.Fatalf("Handler.Handle error: %v", err)
-	}
-	msg := &pubsub.Message{
-		Data: []byte(`{"data":{"userId":1,"channel":"email","recipient":"foo@bar.com","payload":{"sub":"ok"}}}`),
-	}
-	sub.callback(context.Background(), msg)
+func TestNotificationHandler_Handle_ValidEvent(t *testing.T) {
+	received := make(chan []byte, 1)
+	handler := handlers.NewNotificationHandler(notificationAPI(t, http.StatusOK, received))
+
+	err := handler.Handle(context.Background(), &types.Event{
+		Data: []byte(`{"userId":1,"channel":"EMAIL","recipient":"foo@bar.com","payload":{"html":"ok"}}`),
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, string(<-received), `"recipient":"foo@bar.com"`)
 }
 
-func TestNotificationHandler_Handle_InvalidMessage(t *testing.T) {
-	sub := &fakeSubscription{}
+func TestNotificationHandler_Handle_InvalidEvent(t *testing.T) {
 	handler := handlers.NewNotificationHandler(&types.Config{})
-	_ = handler.Handle(sub)
-	msg := &pubsub.Message{
-		Data: []byte(`{not-json}`),
+	err := handler.Handle(context.Background(), &types.Event{Data: []byte(`{not-json}`)})
+	assert.True(t, structs.IsPermanent(err))
+	err = handler.Handle(context.Background(), &types.Event{Data: []byte(`{"userId":1}`)})
+	assert.True(t, structs.IsPermanent(err))
+}
+
+// flakyAPI responde com os status informados em sequência, repetindo o último.
+func flakyAPI(t *testing.T, calls *atomic.Int32, statuses ...int) *types.Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	return &types.Config{
+		URLs: types.URLsConfig{Notification: server.URL},
+		NotificationRetry: types.RetryPolicy{
+			MaxAttempts:     3,
+			InitialBackoff:  time.Millisecond,
+			RetryableStatus: []int{http.StatusServiceUnavailable},
+		},
 	}
-	sub.callback(context.Background(), msg)
+}
+
+var validNotification = &types.Event{
+	Data: []byte(`{"userId":1,"channel":"EMAIL","recipient":"foo@bar.com","payload":{}}`),
+}
+
+func TestNotificationHandler_Handle_RepeteStatusConfigurado(t *testing.T) {
+	var calls atomic.Int32
+	handler := handlers.NewNotificationHandler(flakyAPI(t, &calls, http.StatusServiceUnavailable, http.StatusOK))
+	assert.NoError(t, handler.Handle(context.Background(), validNotification))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestNotificationHandler_Handle_EsgotaTentativas(t *testing.T) {
+	var calls atomic.Int32
+	handler := handlers.NewNotificationHandler(flakyAPI(t, &calls, http.StatusServiceUnavailable))
+	err := handler.Handle(context.Background(), validNotification)
+	assert.Error(t, err)
+	assert.False(t, structs.IsPermanent(err))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestNotificationHandler_Handle_RejeicaoPermanente(t *testing.T) {
+	var calls atomic.Int32
+	handler := handlers.NewNotificationHandler(flakyAPI(t, &calls, http.StatusBadRequest))
+	err := handler.Handle(context.Background(), validNotification)
+	assert.True(t, structs.IsPermanent(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNotificationHandler_Handle_RespostaIlegivelNaoRepete(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte("aceito"))
+	}))
+	t.Cleanup(server.Close)
+	handler := handlers.NewNotificationHandler(&types.Config{
+		URLs: types.URLsConfig{Notification: server.URL},
+		NotificationRetry: types.RetryPolicy{
+			MaxAttempts:     3,
+			InitialBackoff:  time.Millisecond,
+			RetryableStatus: []int{http.StatusInternalServerError},
+		},
+	})
+
+	assert.NoError(t, handler.Handle(context.Background(), validNotification))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNotificationHandler_Handle_APIError(t *testing.T) {
+	handler := handlers.NewNotificationHandler(notificationAPI(t, http.StatusInternalServerError, nil))
+	err := handler.Handle(context.Background(), &types.Event{
+		Data: []byte(`{"userId":1,"channel":"EMAIL","recipient":"foo@bar.com","payload":{}}`),
+	})
+	assert.Error(t, err)
+}
+
+func TestNotificationHandler_Handle_CancelamentoInterrompeRequisicao(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	handler := handlers.NewNotificationHandler(&types.Config{
+		URLs:              types.URLsConfig{Notification: server.URL},
+		NotificationRetry: types.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := handler.Handle(ctx, validNotification)
+	assert.Error(t, err)
+	assert.False(t, structs.IsPermanent(err))
+	assert.Less(t, time.Since(start), time.Second)
 }
@@ -1,30 +1,102 @@
 package strategy
 
 import (
+	"log/slog"
+	"queue/core/domain/dispatcher"
 	"queue/core/domain/enum"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/registry"
 	"queue/core/domain/strategy/handlers"
 	"queue/core/domain/types"
+	"strings"
 )
 
+// DefaultHandlersConfig é usado quando nenhum arquivo de handlers é configurado e mantém o
+// mapeamento histórico: notifications-sub em prod e hml-notifications-sub em hml. Nos demais
+// ambientes, inclusive sem ENVIRONMENT, nenhuma assinatura é consumida.
+var DefaultHandlersConfig = types.HandlersConfig{
+	EnvironmentPrefixes: map[string]string{"prod": "", "hml": "hml-"},
+	Subscriptions: []types.SubscriptionHandlers{
+		{ID: "{prefix}notifications-sub", Handlers: []types.HandlerBinding{{Name: handlers.NotificationHandlerName}}},
+	},
+}
+
 type SubscriptionHandlerStrategy struct {
-	handler map[enum.TopicEnum]interfaces.ISubscribeHandler
+	handler map[string]interfaces.ISubscribeHandler
 }
 
-func NewSubscriptionHandlerStrategy(cfg *types.Config) *SubscriptionHandlerStrategy {
-	handler := map[enum.TopicEnum]interfaces.ISubscribeHandler{}
-	if cfg.Environment == "prod" {
-		handler[enum.Notification] = handlers.NewNotificationHandler(cfg)
+func NewSubscriptionHandlerStrategy(cfg *types.Config, middlewares ...interfaces.EventHandlerMiddleware) *SubscriptionHandlerStrategy {
+	return NewSubscriptionHandlerStrategyWithRegistry(cfg, registry.Default, middlewares...)
+}
+
+// NewSubscriptionHandlerStrategyWithRegistry monta um Dispatcher por assinatura com os handlers configurados.
+// Handlers desconhecidos são ignorados com um log, para não impedir o consumo das demais assinaturas.
+// Os middlewares envolvem cada handler na ordem informada, o primeiro sendo o mais interno.
+func NewSubscriptionHandlerStrategyWithRegistry(cfg *types.Config, reg *registry.HandlerRegistry, middlewares ...interfaces.EventHandlerMiddleware) *SubscriptionHandlerStrategy {
+	handlersCfg := cfg.Handlers
+	if len(handlersCfg.Subscriptions) == 0 {
+		handlersCfg = DefaultHandlersConfig
+	}
+	dispatchers := map[string]*dispatcher.Dispatcher{}
+	for _, sub := range handlersCfg.Subscriptions {
+		id, ok := ResolveSubscriptionID(sub.ID, cfg.Environment, handlersCfg.EnvironmentPrefixes)
+		if !ok {
+			slog.Warn("Assinatura ignorada: ambiente sem prefixo configurado", "subscription", sub.ID, "environment", cfg.Environment)
+			continue
+		}
+		for _, binding := range sub.Handlers {
+			handler, err := reg.Build(binding.Name, cfg)
+			if err != nil {
+				slog.Error("Erro ao configurar a assinatura", "subscription", id, "handler", binding.Name, "error", err)
+				continue
+			}
+			for _, middleware := range middlewares {
+				handler = middleware(id, binding.Name, handler)
+			}
+			d, ok := dispatchers[id]
+			if !ok {
+				d = dispatcher.NewDispatcher()
+				dispatchers[id] = d
+			}
+			timeout := binding.Timeout
+			if timeout <= 0 {
+				timeout = cfg.Handlers.DefaultTimeout
+			}
+			d.RegisterWithOptions(handler, dispatcher.HandlerOptions{
+				Name:    binding.Name,
+				Policy:  enum.HandlerPolicyEnum(binding.Policy),
+				Timeout: timeout,
+			})
+		}
 	}
-	if cfg.Environment == "hml" {
-		handler[enum.HmlNotification] = handlers.NewNotificationHandler(cfg)
+	handler := make(map[string]interfaces.ISubscribeHandler, len(dispatchers))
+	for id, d := range dispatchers {
+		handler[id] = d
 	}
 	return &SubscriptionHandlerStrategy{handler: handler}
 }
 
 func (f *SubscriptionHandlerStrategy) GetHandler(topic string) interfaces.ISubscribeHandler {
-	if handler, ok := f.handler[enum.TopicEnum(topic)]; ok {
+	if handler, ok := f.handler[topic]; ok {
 		return handler
 	}
 	return nil
 }
+
+// ResolveSubscriptionID substitui {env} pelo ambiente e {prefix} pelo prefixo configurado para ele.
+// Sem prefixos configurados, {prefix} vira "<ambiente>-". Retorna false quando o marcador não pode ser
+// resolvido (ambiente vazio ou fora dos prefixos configurados), para que a assinatura seja ignorada em
+// vez de cair no ID de outro ambiente.
+func ResolveSubscriptionID(template string, environment string, prefixes map[string]string) (string, bool) {
+	prefix, ok := prefixes[environment]
+	if !ok {
+		if strings.Contains(template, "{prefix}") && (environment == "" || len(prefixes) > 0) {
+			return "", false
+		}
+		prefix = environment + "-"
+	}
+	if strings.Contains(template, "{env}") && environment == "" {
+		return "", false
+	}
+	return strings.NewReplacer("{env}", environment, "{prefix}", prefix).Replace(template), true
+}
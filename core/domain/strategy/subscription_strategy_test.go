@@ -1,35 +1,144 @@
 package strategy_test
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
-	"queue/core/domain/enum"
+	"queue/core/domain/dispatcher"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/registry"
 	"queue/core/domain/strategy"
 	"queue/core/domain/types"
 	"testing"
+	"time"
 )
 
 func TestNewSubscriptionHandlerStrategy_Prod(t *testing.T) {
 	cfg := &types.Config{Environment: "prod"}
 	handlerFactory := strategy.NewSubscriptionHandlerStrategy(cfg)
 
-	handler := handlerFactory.GetHandler(string(enum.Notification))
+	handler := handlerFactory.GetHandler("notifications-sub")
 	assert.NotNil(t, handler, "deveria retornar um handler para topic de produção")
-	assert.Nil(t, handlerFactory.GetHandler(string(enum.HmlNotification)), "não deveria retornar handler para hml em produção")
+	assert.Nil(t, handlerFactory.GetHandler("hml-notifications-sub"), "não deveria retornar handler para hml em produção")
 }
 
 func TestNewSubscriptionHandlerStrategy_Hml(t *testing.T) {
 	cfg := &types.Config{Environment: "hml"}
 	handlerFactory := strategy.NewSubscriptionHandlerStrategy(cfg)
 
-	handler := handlerFactory.GetHandler(string(enum.HmlNotification))
+	handler := handlerFactory.GetHandler("hml-notifications-sub")
 	assert.NotNil(t, handler, "deveria retornar um handler para topic de hml")
-	assert.Nil(t, handlerFactory.GetHandler(string(enum.Notification)), "não deveria retornar handler para prod em hml")
+	assert.Nil(t, handlerFactory.GetHandler("notifications-sub"), "não deveria retornar handler para prod em hml")
 }
 
 func TestNewSubscriptionHandlerStrategy_Empty(t *testing.T) {
-	cfg := &types.Config{Environment: "other"}
-	handlerFactory := strategy.NewSubscriptionHandlerStrategy(cfg)
+	for _, environment := range []string{"other", "dev", ""} {
+		handlerFactory := strategy.NewSubscriptionHandlerStrategy(&types.Config{Environment: environment})
+
+		assert.Nil(t, handlerFactory.GetHandler("notifications-sub"), "não deveria retornar handler para nenhum topic em %q", environment)
+		assert.Nil(t, handlerFactory.GetHandler("hml-notifications-sub"), "não deveria retornar handler para nenhum topic em %q", environment)
+		assert.Nil(t, handlerFactory.GetHandler("other-notifications-sub"), "não deveria retornar handler para nenhum topic em %q", environment)
+	}
+}
+
+type auditHandler struct{}
+
+func (auditHandler) Supports(topic string) bool { return true }
+
+func (auditHandler) Handle(ctx context.Context, event *types.Event) error { return nil }
+
+func TestNewSubscriptionHandlerStrategy_Configurado(t *testing.T) {
+	reg := registry.NewHandlerRegistry()
+	reg.Register("audit", func(cfg *types.Config) interfaces.IEventHandler { return auditHandler{} })
+	cfg := &types.Config{
+		Environment: "staging",
+		Handlers: types.HandlersConfig{
+			Subscriptions: []types.SubscriptionHandlers{
+				{ID: "{prefix}audit-sub", Handlers: []types.HandlerBinding{{Name: "audit", Policy: "best-effort"}}},
+				{ID: "{env}.orders-sub", Handlers: []types.HandlerBinding{{Name: "audit"}, {Name: "desconhecido"}}},
+				{ID: "ignorada-sub", Handlers: []types.HandlerBinding{{Name: "desconhecido"}}},
+			},
+		},
+	}
+	handlerStrategy := strategy.NewSubscriptionHandlerStrategyWithRegistry(cfg, reg)
+
+	assert.NotNil(t, handlerStrategy.GetHandler("staging-audit-sub"))
+	assert.NotNil(t, handlerStrategy.GetHandler("staging.orders-sub"))
+	assert.Nil(t, handlerStrategy.GetHandler("ignorada-sub"))
+	assert.Nil(t, handlerStrategy.GetHandler("notifications-sub"))
+}
+
+func TestResolveSubscriptionID(t *testing.T) {
+	prefixes := map[string]string{"prod": "", "hml": "hml-"}
+	resolve := func(template, environment string, prefixes map[string]string) string {
+		id, ok := strategy.ResolveSubscriptionID(template, environment, prefixes)
+		if !ok {
+			return "<ignorada>"
+		}
+		return id
+	}
+	assert.Equal(t, "notifications-sub", resolve("{prefix}notifications-sub", "prod", prefixes))
+	assert.Equal(t, "hml-notifications-sub", resolve("{prefix}notifications-sub", "hml", prefixes))
+	assert.Equal(t, "<ignorada>", resolve("{prefix}notifications-sub", "dev", prefixes))
+	assert.Equal(t, "<ignorada>", resolve("{prefix}notifications-sub", "", prefixes))
+	assert.Equal(t, "dev-notifications-sub", resolve("{prefix}notifications-sub", "dev", nil))
+	assert.Equal(t, "<ignorada>", resolve("{prefix}notifications-sub", "", nil))
+	assert.Equal(t, "dev/notifications", resolve("{env}/notifications", "dev", nil))
+	assert.Equal(t, "<ignorada>", resolve("{env}/notifications", "", nil))
+	assert.Equal(t, "audit-sub", resolve("audit-sub", "", prefixes))
+}
+
+func TestNewSubscriptionHandlerStrategy_Middlewares(t *testing.T) {
+	reg := registry.NewHandlerRegistry()
+	reg.Register("audit", func(cfg *types.Config) interfaces.IEventHandler { return auditHandler{} })
+	cfg := &types.Config{
+		Handlers: types.HandlersConfig{
+			Subscriptions: []types.SubscriptionHandlers{
+				{ID: "audit-sub", Handlers: []types.HandlerBinding{{Name: "audit"}}},
+			},
+		},
+	}
+	var calls []string
+	middleware := func(tag string) interfaces.EventHandlerMiddleware {
+		return func(subscriptionID string, name string, handler interfaces.IEventHandler) interfaces.IEventHandler {
+			calls = append(calls, tag+":"+subscriptionID+"/"+name)
+			return handler
+		}
+	}
+	handlerStrategy := strategy.NewSubscriptionHandlerStrategyWithRegistry(cfg, reg, middleware("a"), middleware("b"))
+
+	assert.NotNil(t, handlerStrategy.GetHandler("audit-sub"))
+	assert.Equal(t, []string{"a:audit-sub/audit", "b:audit-sub/audit"}, calls)
+}
+
+type blockingHandler struct{}
+
+func (blockingHandler) Supports(topic string) bool { return true }
+
+func (blockingHandler) Handle(ctx context.Context, event *types.Event) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestNewSubscriptionHandlerStrategy_Timeout(t *testing.T) {
+	reg := registry.NewHandlerRegistry()
+	reg.Register("lento", func(cfg *types.Config) interfaces.IEventHandler { return blockingHandler{} })
+	cfg := &types.Config{
+		Handlers: types.HandlersConfig{
+			DefaultTimeout: time.Hour,
+			Subscriptions: []types.SubscriptionHandlers{
+				{ID: "proprio-sub", Handlers: []types.HandlerBinding{{Name: "lento", Timeout: 20 * time.Millisecond}}},
+				{ID: "padrao-sub", Handlers: []types.HandlerBinding{{Name: "lento"}}},
+			},
+		},
+	}
+	handlerStrategy := strategy.NewSubscriptionHandlerStrategyWithRegistry(cfg, reg)
+
+	err := handlerStrategy.GetHandler("proprio-sub").(interfaces.IDispatcher).Dispatch(context.Background(), &types.Event{Topic: "t"})
+	assert.ErrorIs(t, err, dispatcher.ErrHandlerTimeout)
 
-	assert.Nil(t, handlerFactory.GetHandler(string(enum.Notification)), "não deveria retornar handler para nenhum topic")
-	assert.Nil(t, handlerFactory.GetHandler(string(enum.HmlNotification)), "não deveria retornar handler para nenhum topic")
+	cfg.Handlers.DefaultTimeout = 20 * time.Millisecond
+	cfg.Handlers.Subscriptions = cfg.Handlers.Subscriptions[1:]
+	handlerStrategy = strategy.NewSubscriptionHandlerStrategyWithRegistry(cfg, reg)
+	err = handlerStrategy.GetHandler("padrao-sub").(interfaces.IDispatcher).Dispatch(context.Background(), &types.Event{Topic: "t"})
+	assert.ErrorIs(t, err, dispatcher.ErrHandlerTimeout)
 }
@@ -0,0 +1,43 @@
+package structs
+
+import "errors"
+
+// PermanentError indica uma falha que não se resolve com novas entregas, como dados inválidos
+// ou rejeição 4xx do destino. A mensagem deve sair da fila em vez de ser reentregue.
+type PermanentError struct {
+	Err error
+}
+
+func NewPermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// IsPermanent retorna true quando err é permanente. Para erros agregados com errors.Join,
+// todos precisam ser permanentes, já que uma falha temporária ainda justifica a reentrega.
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, e := range errs {
+			if !IsPermanent(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	}
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
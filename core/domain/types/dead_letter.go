@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// DeadLetterConfig define para onde vão as mensagens que esgotaram as entregas ou falharam de forma permanente.
+type DeadLetterConfig struct {
+	Topic               string
+	MaxDeliveryAttempts int
+	// Subscription é a assinatura do tópico de dead-letter usada pela API de inspeção e replay.
+	Subscription string
+	// AdminAuth são as credenciais exigidas pela API de inspeção e replay, separadas das de publicação.
+	AdminAuth BasicAuthConfig
+}
+
+// DeliveryFailure descreve uma mensagem que não pôde ser processada por uma assinatura.
+type DeliveryFailure struct {
+	SubscriptionID string
+	// Topic é o tópico original do evento; fica vazio quando a mensagem não pôde ser decodificada.
+	Topic     string
+	Message   *Message
+	Err       error
+	Permanent bool
+}
+
+// DeadLetterMessage é uma mensagem do dead-letter com os metadados da falha que a levou até lá.
+type DeadLetterMessage struct {
+	Message        *Message
+	SubscriptionID string
+	Topic          string
+	Error          string
+	Attempts       int
+	FailedAt       time.Time
+	OriginalID     string
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Event é a representação uniforme entregue aos handlers, seja a mensagem um CloudEvent
+// (modo binário ou estruturado) ou o envelope {meta, data} legado.
+type Event struct {
+	// Topic é o tópico lógico usado para escolher os handlers: meta.topic no envelope legado,
+	// a extensão topic ou, na ausência dela, o type no CloudEvent.
+	Topic           string
+	ID              string
+	Source          string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	Data            []byte
+	Extensions      map[string]string
+}
+
+// HasJSONData indica se o campo Data contém JSON; CloudEvents sem datacontenttype são tratados como JSON.
+func (e *Event) HasJSONData() bool {
+	ct := strings.ToLower(strings.TrimSpace(strings.Split(e.DataContentType, ";")[0]))
+	return ct == "" || ct == "application/json" || ct == "text/json" || strings.HasSuffix(ct, "+json")
+}
@@ -0,0 +1,23 @@
+package types_test
+
+import (
+	"queue/core/domain/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvent_HasJSONData(t *testing.T) {
+	cases := map[string]bool{
+		"":                                true,
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"application/vnd.acme+json":       true,
+		"text/plain":                      false,
+		"application/octet-stream":        false,
+	}
+	for contentType, want := range cases {
+		event := types.Event{DataContentType: contentType}
+		assert.Equal(t, want, event.HasJSONData(), contentType)
+	}
+}
@@ -0,0 +1,17 @@
+package types
+
+import "time"
+
+// IdempotencyRecord guarda o resultado da primeira publicação feita com uma chave de idempotência.
+type IdempotencyRecord struct {
+	Key        string    `json:"key"`
+	Topic      string    `json:"topic"`
+	MessageID  string    `json:"messageId,omitempty"`
+	ScheduleID string    `json:"scheduleId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func (r IdempotencyRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package types_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/types"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRecord_Expired(t *testing.T) {
+	now := time.Now()
+	record := types.IdempotencyRecord{Key: "k", ExpiresAt: now.Add(time.Minute)}
+
+	assert.False(t, record.Expired(now))
+	assert.True(t, record.Expired(now.Add(time.Minute)))
+}
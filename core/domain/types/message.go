@@ -0,0 +1,35 @@
+package types
+
+import "time"
+
+// Message é a representação de uma mensagem independente do broker utilizado.
+type Message struct {
+	ID              string
+	Data            []byte
+	Attributes      map[string]string
+	OrderingKey     string
+	PublishTime     time.Time
+	DeliveryAttempt *int
+
+	ack  func()
+	nack func()
+}
+
+// NewReceivedMessage cria uma mensagem recebida do broker com os callbacks de Ack/Nack.
+func NewReceivedMessage(msg Message, ack func(), nack func()) *Message {
+	msg.ack = ack
+	msg.nack = nack
+	return &msg
+}
+
+func (m *Message) Ack() {
+	if m.ack != nil {
+		m.ack()
+	}
+}
+
+func (m *Message) Nack() {
+	if m.nack != nil {
+		m.nack()
+	}
+}
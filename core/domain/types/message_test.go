@@ -0,0 +1,31 @@
+package types_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/types"
+	"testing"
+)
+
+func TestMessage_AckNack(t *testing.T) {
+	acked, nacked := false, false
+	msg := types.NewReceivedMessage(types.Message{ID: "1", Data: []byte("x")},
+		func() { acked = true },
+		func() { nacked = true },
+	)
+
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, []byte("x"), msg.Data)
+
+	msg.Ack()
+	assert.True(t, acked)
+	msg.Nack()
+	assert.True(t, nacked)
+}
+
+func TestMessage_AckNackSemCallbacks(t *testing.T) {
+	msg := &types.Message{Data: []byte("x")}
+	assert.NotPanics(t, func() {
+		msg.Ack()
+		msg.Nack()
+	})
+}
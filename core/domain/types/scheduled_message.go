@@ -0,0 +1,28 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrScheduleInFlight indica que o agendamento já está sendo publicado e não pode mais ser cancelado.
+var ErrScheduleInFlight = errors.New("mensagem agendada já está sendo publicada")
+
+// ScheduledMessage é uma mensagem retida até DeliverAt, quando é publicada no tópico.
+type ScheduledMessage struct {
+	ID          string            `json:"id"`
+	Topic       string            `json:"topic"`
+	Data        []byte            `json:"data"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+	DeliverAt   time.Time         `json:"deliverAt"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+func (s ScheduledMessage) Message() *Message {
+	return &Message{
+		Data:        s.Data,
+		Attributes:  s.Attributes,
+		OrderingKey: s.OrderingKey,
+	}
+}
@@ -0,0 +1,24 @@
+package types_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"queue/core/domain/types"
+	"testing"
+	"time"
+)
+
+func TestScheduledMessage_Message(t *testing.T) {
+	scheduled := types.ScheduledMessage{
+		ID:          "sched-1",
+		Topic:       "topic",
+		Data:        []byte("x"),
+		Attributes:  map[string]string{"k": "v"},
+		OrderingKey: "user-1",
+		DeliverAt:   time.Now(),
+	}
+
+	msg := scheduled.Message()
+	assert.Equal(t, []byte("x"), msg.Data)
+	assert.Equal(t, "v", msg.Attributes["k"])
+	assert.Equal(t, "user-1", msg.OrderingKey)
+}
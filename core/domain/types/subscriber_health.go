@@ -0,0 +1,28 @@
+package types
+
+import (
+	"queue/core/domain/enum"
+	"time"
+)
+
+// SubscriptionStatus resume o receptor de uma assinatura e as mensagens processadas desde o início do serviço.
+type SubscriptionStatus struct {
+	ID    string                   `json:"id"`
+	State enum.SubscriberStateEnum `json:"state"`
+	// Restarts conta as vezes em que o receptor encerrou com falha e foi reiniciado.
+	Restarts          int        `json:"restarts"`
+	LastReceiverError string     `json:"lastReceiverError,omitempty"`
+	LastHandlerError  string     `json:"lastHandlerError,omitempty"`
+	LastErrorAt       *time.Time `json:"lastErrorAt,omitempty"`
+	LastMessageAt     *time.Time `json:"lastMessageAt,omitempty"`
+	// Messages conta as mensagens por destino: acked, nacked, dead-lettered ou discarded.
+	Messages map[enum.MessageOutcomeEnum]int64 `json:"messages"`
+}
+
+// SubscriberHealth é o estado do consumo das assinaturas. Status é "ok" quando todos os receptores
+// estão ativos e a listagem de assinaturas funcionou, e "degraded" caso contrário.
+type SubscriberHealth struct {
+	Status        string               `json:"status"`
+	ListError     string               `json:"listError,omitempty"`
+	Subscriptions []SubscriptionStatus `json:"subscriptions"`
+}
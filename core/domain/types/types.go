@@ -1,6 +1,10 @@
 package types
 
-import "github.com/gin-contrib/cors"
+import (
+	"github.com/gin-contrib/cors"
+	"log/slog"
+	"time"
+)
 
 type BasicAuthConfig struct {
 	Username string
@@ -8,8 +12,9 @@ type BasicAuthConfig struct {
 }
 
 type GoogleConfig struct {
-	ProjectID   string
-	Credentials string
+	ProjectID    string
+	Credentials  string
+	EmulatorHost string
 }
 
 type URLsConfig struct {
@@ -20,11 +25,160 @@ type URLsConfig struct {
 	Queue        string
 }
 
+// ReceiveSettings controla o fluxo de recebimento de uma assinatura; valores zero mantêm o padrão do broker.
+type ReceiveSettings struct {
+	MaxOutstandingMessages int
+	MaxOutstandingBytes    int
+	NumGoroutines          int
+}
+
+// Merge retorna as configurações com os campos não informados preenchidos pelos padrões.
+func (s ReceiveSettings) Merge(defaults ReceiveSettings) ReceiveSettings {
+	if s.MaxOutstandingMessages == 0 {
+		s.MaxOutstandingMessages = defaults.MaxOutstandingMessages
+	}
+	if s.MaxOutstandingBytes == 0 {
+		s.MaxOutstandingBytes = defaults.MaxOutstandingBytes
+	}
+	if s.NumGoroutines == 0 {
+		s.NumGoroutines = defaults.NumGoroutines
+	}
+	return s
+}
+
+type SubscriptionConfig struct {
+	ID             string
+	Topic          string
+	AckDeadline    time.Duration
+	EnableOrdering bool
+	Receive        ReceiveSettings
+}
+
+type BrokerConfig struct {
+	Type          string
+	AckDeadline   time.Duration
+	AutoProvision bool
+	Topics        []string
+	Subscriptions []SubscriptionConfig
+	// Receive é aplicado às assinaturas sem configuração própria.
+	Receive ReceiveSettings
+	// LoadErr guarda a falha ao ler BROKER_TOPOLOGY_FILE, que impede a inicialização.
+	LoadErr error
+}
+
+// ReceiveSettingsFor combina a configuração da assinatura com os padrões do broker.
+func (c BrokerConfig) ReceiveSettingsFor(subscriptionID string) ReceiveSettings {
+	for _, sub := range c.Subscriptions {
+		if sub.ID == subscriptionID {
+			return sub.Receive.Merge(c.Receive)
+		}
+	}
+	return c.Receive
+}
+
+// RetryPolicy define as novas tentativas feitas pelo handler antes de devolver a falha ao broker.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryableStatus lista os status HTTP que justificam nova tentativa.
+	RetryableStatus []int
+}
+
+// SubscriberConfig define o intervalo de espera antes de reiniciar um receptor que encerrou.
+type SubscriberConfig struct {
+	RestartMinBackoff time.Duration
+	RestartMaxBackoff time.Duration
+}
+
+type SchedulerConfig struct {
+	StoreFile    string
+	PollInterval time.Duration
+}
+
+type IdempotencyConfig struct {
+	StoreFile string
+	TTL       time.Duration
+}
+
+// DedupConfig controla a deduplicação das entregas nos handlers de assinatura. Key lista os campos
+// que formam a chave (vazio usa o id do evento); sem StoreFile as chaves ficam em memória, limitadas a MaxEntries.
+type DedupConfig struct {
+	Enabled    bool
+	Key        []string
+	TTL        time.Duration
+	MaxEntries int
+	StoreFile  string
+}
+
+// MetricsConfig controla a exposição das métricas do Prometheus em /metrics.
+type MetricsConfig struct {
+	Enabled bool
+}
+
+// TracingConfig escolhe o exportador dos spans (none, stdout ou otlp) e a fração de traces amostrados
+// entre os que não chegam com uma decisão de amostragem no traceparent.
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// LogConfig define o formato (json ou text) e o nível mínimo dos logs.
+type LogConfig struct {
+	Format string
+	Level  slog.Level
+}
+
+type TopicRegistryConfig struct {
+	File string
+}
+
+// HandlerBinding liga um handler registrado a uma assinatura. Timeout zero usa HandlersConfig.DefaultTimeout.
+type HandlerBinding struct {
+	Name    string
+	Policy  string
+	Timeout time.Duration
+}
+
+// SubscriptionHandlers liga uma assinatura aos handlers registrados. O ID aceita os marcadores
+// {env} (nome do ambiente) e {prefix} (prefixo do ambiente).
+type SubscriptionHandlers struct {
+	ID       string
+	Handlers []HandlerBinding
+}
+
+type HandlersConfig struct {
+	EnvironmentPrefixes map[string]string
+	Subscriptions       []SubscriptionHandlers
+	// DefaultTimeout limita o processamento de cada mensagem pelos handlers sem tempo limite próprio.
+	DefaultTimeout time.Duration
+	// LoadErr guarda a falha ao ler SUBSCRIPTION_HANDLERS_FILE, que impede a inicialização.
+	LoadErr error
+}
+
 type Config struct {
 	Environment string
-	Port        int
-	CorsConfig  cors.Config
-	Auth        BasicAuthConfig
-	Google      GoogleConfig
-	URLs        URLsConfig
+	// Mode é publisher, subscriber ou all; vazio equivale a all.
+	Mode string
+	Port int
+	// ShutdownTimeout limita a espera pelas requisições e mensagens em andamento no desligamento.
+	ShutdownTimeout time.Duration
+	CorsConfig      cors.Config
+	Auth            BasicAuthConfig
+	Google          GoogleConfig
+	Broker          BrokerConfig
+	Scheduler       SchedulerConfig
+	Idempotency     IdempotencyConfig
+	TopicRegistry   TopicRegistryConfig
+	Handlers        HandlersConfig
+	Subscriber      SubscriberConfig
+	// NotificationRetry é a política de novas tentativas do envio para a API de notificações.
+	NotificationRetry RetryPolicy
+	DeadLetter        DeadLetterConfig
+	Dedup             DedupConfig
+	Metrics           MetricsConfig
+	Tracing           TracingConfig
+	Log               LogConfig
+	URLs              URLsConfig
 }
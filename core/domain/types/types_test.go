@@ -73,3 +73,15 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, "http://localhost/storage", cfg.URLs.Storage)
 	assert.Equal(t, "http://localhost/queue", cfg.URLs.Queue)
 }
+
+func TestBrokerConfig_ReceiveSettingsFor(t *testing.T) {
+	cfg := types.BrokerConfig{
+		Receive: types.ReceiveSettings{MaxOutstandingMessages: 10, NumGoroutines: 2},
+		Subscriptions: []types.SubscriptionConfig{
+			{ID: "sub-a", Topic: "t", Receive: types.ReceiveSettings{MaxOutstandingMessages: 50, MaxOutstandingBytes: 1024}},
+		},
+	}
+
+	assert.Equal(t, types.ReceiveSettings{MaxOutstandingMessages: 50, MaxOutstandingBytes: 1024, NumGoroutines: 2}, cfg.ReceiveSettingsFor("sub-a"))
+	assert.Equal(t, cfg.Receive, cfg.ReceiveSettingsFor("sub-b"))
+}
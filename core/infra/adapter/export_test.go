@@ -0,0 +1,8 @@
+package pubsubadapter
+
+// OpenTopics expõe aos testes a quantidade de tópicos mantidos pelo adapter.
+func (a *PubSubClientAdapter) OpenTopics() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.open)
+}
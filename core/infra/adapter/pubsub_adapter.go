@@ -3,26 +3,59 @@ package pubsubadapter
 import (
 	"cloud.google.com/go/pubsub"
 	"context"
+	"log/slog"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"sync"
 )
 
 type PubSubClientAdapter struct {
 	client *pubsub.Client
+	mu     sync.Mutex
+	// open guarda um tópico por ID, reaproveitado entre publicações e encerrado apenas em Close.
+	open map[string]*topicAdapter
 }
 
 func NewPubSubClientAdapter(client *pubsub.Client) *PubSubClientAdapter {
-	return &PubSubClientAdapter{client: client}
+	return &PubSubClientAdapter{client: client, open: map[string]*topicAdapter{}}
 }
 
 func (a *PubSubClientAdapter) Subscriptions(ctx context.Context) interfaces.ISubscriptionIterator {
 	return &subscriptionIteratorAdapter{a.client.Subscriptions(ctx)}
 }
 
+// Topic devolve o tópico do ID, criado na primeira chamada e compartilhado pelas seguintes, para que
+// cada publicação não abra um novo agrupador com as suas goroutines. A ordenação é habilitada já na
+// criação, antes de qualquer publicação; mensagens sem chave de ordenação não são afetadas.
 func (a *PubSubClientAdapter) Topic(id string) interfaces.ITopic {
-	return &topicAdapter{a.client.Topic(id)}
-}
-
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if topic, ok := a.open[id]; ok {
+		return topic
+	}
+	topic := a.client.Topic(id)
+	topic.EnableMessageOrdering = true
+	adapter := &topicAdapter{topic: topic}
+	a.open[id] = adapter
+	return adapter
+}
+
+// Close encerra os tópicos abertos, aguardando o envio das publicações pendentes,
+// antes de fechar a conexão com o Pub/Sub.
 func (a *PubSubClientAdapter) Close() error {
+	a.mu.Lock()
+	pending := make([]*topicAdapter, 0, len(a.open))
+	for _, topic := range a.open {
+		pending = append(pending, topic)
+	}
+	a.open = map[string]*topicAdapter{}
+	a.mu.Unlock()
+	for _, topic := range pending {
+		topic.topic.Stop()
+	}
+	if len(pending) > 0 {
+		slog.Info("Publicações pendentes enviadas", "topics", len(pending))
+	}
 	return a.client.Close()
 }
 
@@ -33,7 +66,11 @@ type subscriptionIteratorAdapter struct {
 }
 
 func (s *subscriptionIteratorAdapter) Next() (interfaces.ISubscription, error) {
-	return s.iter.Next()
+	sub, err := s.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+	return &pubsubSubscriptionAdapter{sub}, nil
 }
 
 type topicAdapter struct {
@@ -44,16 +81,49 @@ func (t *topicAdapter) ID() string {
 	return t.topic.ID()
 }
 
+// Stop apenas envia as publicações pendentes: o tópico é compartilhado com as demais publicações
+// e só é encerrado no Close do client.
 func (t *topicAdapter) Stop() {
-	t.topic.Stop()
+	t.topic.Flush()
 }
 
-func (t *topicAdapter) Publish(ctx context.Context, msg *pubsub.Message) interfaces.IPublishResult {
-	return t.topic.Publish(ctx, msg)
+func (t *topicAdapter) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
+	return &PublishResultAdapter{
+		result:      t.topic.Publish(ctx, ToPubSubMessage(msg)),
+		topic:       t.topic,
+		orderingKey: msg.OrderingKey,
+	}
 }
 
 /*--------------------------------------- RECEIVE --------------------------------------------------------------*/
 
+type pubsubSubscriptionAdapter struct {
+	sub *pubsub.Subscription
+}
+
+func (s *pubsubSubscriptionAdapter) ID() string {
+	return s.sub.ID()
+}
+
+// SetReceiveSettings aplica apenas os valores informados, mantendo os padrões da biblioteca nos demais.
+func (s *pubsubSubscriptionAdapter) SetReceiveSettings(settings types.ReceiveSettings) {
+	if settings.MaxOutstandingMessages > 0 {
+		s.sub.ReceiveSettings.MaxOutstandingMessages = settings.MaxOutstandingMessages
+	}
+	if settings.MaxOutstandingBytes > 0 {
+		s.sub.ReceiveSettings.MaxOutstandingBytes = settings.MaxOutstandingBytes
+	}
+	if settings.NumGoroutines > 0 {
+		s.sub.ReceiveSettings.NumGoroutines = settings.NumGoroutines
+	}
+}
+
+func (s *pubsubSubscriptionAdapter) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
+	return s.sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		f(ctx, FromPubSubMessage(msg))
+	})
+}
+
 type SubscriptionAdapter struct {
 	sub interfaces.ISubscription
 }
@@ -66,16 +136,45 @@ func (s *SubscriptionAdapter) ID() string {
 	return s.sub.ID()
 }
 
-func (s *SubscriptionAdapter) Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error {
+func (s *SubscriptionAdapter) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
 	return s.sub.Receive(ctx, f)
 }
 
 /*---------------------------------				RESULT ADAPTER			-------------------------------------*/
 
 type PublishResultAdapter struct {
-	result *pubsub.PublishResult
+	result      *pubsub.PublishResult
+	topic       *pubsub.Topic
+	orderingKey string
 }
 
+// Get retoma a publicação da chave de ordenação após uma falha, já que o Pub/Sub
+// pausa a chave até que ResumePublish seja chamado.
 func (a *PublishResultAdapter) Get(ctx context.Context) (string, error) {
-	return a.result.Get(ctx)
+	id, err := a.result.Get(ctx)
+	if err != nil && a.orderingKey != "" && a.topic != nil {
+		a.topic.ResumePublish(a.orderingKey)
+	}
+	return id, err
+}
+
+/*---------------------------------				MESSAGE MAPPING			-------------------------------------*/
+
+func ToPubSubMessage(msg *types.Message) *pubsub.Message {
+	return &pubsub.Message{
+		Data:        msg.Data,
+		Attributes:  msg.Attributes,
+		OrderingKey: msg.OrderingKey,
+	}
+}
+
+func FromPubSubMessage(msg *pubsub.Message) *types.Message {
+	return types.NewReceivedMessage(types.Message{
+		ID:              msg.ID,
+		Data:            msg.Data,
+		Attributes:      msg.Attributes,
+		OrderingKey:     msg.OrderingKey,
+		PublishTime:     msg.PublishTime,
+		DeliveryAttempt: msg.DeliveryAttempt,
+	}, msg.Ack, msg.Nack)
 }
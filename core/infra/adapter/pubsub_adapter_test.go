@@ -3,24 +3,28 @@ package pubsubadapter_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"queue/core/infra/adapter"
+	"sync"
 	"testing"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/stretchr/testify/assert"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
 )
 
 // --- Mocks ---
 
 type mockSubscription struct {
 	id          string
-	receiveFunc func(ctx context.Context, f func(context.Context, *pubsub.Message)) error
+	receiveFunc func(ctx context.Context, f func(context.Context, *types.Message)) error
 }
 
 func (m *mockSubscription) ID() string {
 	return m.id
 }
-func (m *mockSubscription) Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error {
+func (m *mockSubscription) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
 	if m.receiveFunc != nil {
 		return m.receiveFunc(ctx, f)
 	}
@@ -54,14 +58,14 @@ func TestSubscriptionAdapter_ID(t *testing.T) {
 
 func TestSubscriptionAdapter_Receive(t *testing.T) {
 	called := false
-	f := func(ctx context.Context, msg *pubsub.Message) {
+	f := func(ctx context.Context, msg *types.Message) {
 		called = true
 	}
 
 	mockSub := &mockSubscription{
 		id: "x",
-		receiveFunc: func(ctx context.Context, recvFunc func(context.Context, *pubsub.Message)) error {
-			recvFunc(ctx, &pubsub.Message{})
+		receiveFunc: func(ctx context.Context, recvFunc func(context.Context, *types.Message)) error {
+			recvFunc(ctx, &types.Message{})
 			return nil
 		},
 	}
@@ -74,3 +78,74 @@ func TestSubscriptionAdapter_Receive(t *testing.T) {
 		t.Error("expected receive function to be called")
 	}
 }
+
+// --- Test message mapping ---
+
+func TestToPubSubMessage(t *testing.T) {
+	msg := &types.Message{
+		Data:        []byte("payload"),
+		Attributes:  map[string]string{"k": "v"},
+		OrderingKey: "user-1",
+	}
+	out := pubsubadapter.ToPubSubMessage(msg)
+	if string(out.Data) != "payload" || out.Attributes["k"] != "v" || out.OrderingKey != "user-1" {
+		t.Errorf("mensagem convertida incorretamente: %+v", out)
+	}
+}
+
+func TestFromPubSubMessage(t *testing.T) {
+	attempt := 3
+	in := &pubsub.Message{
+		ID:              "abc",
+		Data:            []byte("payload"),
+		Attributes:      map[string]string{"k": "v"},
+		OrderingKey:     "user-1",
+		DeliveryAttempt: &attempt,
+	}
+	out := pubsubadapter.FromPubSubMessage(in)
+	if out.ID != "abc" || string(out.Data) != "payload" || out.Attributes["k"] != "v" || out.OrderingKey != "user-1" {
+		t.Errorf("mensagem convertida incorretamente: %+v", out)
+	}
+	if out.DeliveryAttempt == nil || *out.DeliveryAttempt != 3 {
+		t.Errorf("expected delivery attempt 3, got %v", out.DeliveryAttempt)
+	}
+}
+
+func TestPubSubClientAdapter_TopicReaproveitado(t *testing.T) {
+	ctx := context.Background()
+	adapter := pubsubadapter.NewPubSubClientAdapter(newFakePubSubClient(t))
+	assert.NoError(t, adapter.Provision(ctx, types.BrokerConfig{Topics: []string{"notifications", "audit"}}))
+
+	for i := 0; i < 5; i++ {
+		topic := adapter.Topic("notifications")
+		_, err := topic.Publish(ctx, &types.Message{Data: []byte("x")}).Get(ctx)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, adapter.OpenTopics())
+
+	// Stop é chamado pelos publicadores em lote e não pode encerrar o tópico compartilhado
+	adapter.Topic("notifications").Stop()
+	_, err := adapter.Topic("notifications").Publish(ctx, &types.Message{Data: []byte("x")}).Get(ctx)
+	assert.NoError(t, err)
+
+	adapter.Topic("audit")
+	assert.Equal(t, 2, adapter.OpenTopics())
+}
+
+func TestTopicAdapter_PublicacaoConcorrenteComChaveDeOrdenacao(t *testing.T) {
+	ctx := context.Background()
+	adapter := pubsubadapter.NewPubSubClientAdapter(newFakePubSubClient(t))
+	assert.NoError(t, adapter.Provision(ctx, types.BrokerConfig{Topics: []string{"ordered"}}))
+
+	topic := adapter.Topic("ordered")
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := topic.Publish(ctx, &types.Message{Data: []byte("x"), OrderingKey: fmt.Sprintf("user-%d", i%4)}).Get(ctx)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+}
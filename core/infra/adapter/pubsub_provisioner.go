@@ -0,0 +1,65 @@
+package pubsubadapter
+
+import (
+	"cloud.google.com/go/pubsub"
+	"context"
+	"fmt"
+	"log/slog"
+	"queue/core/domain/types"
+)
+
+// Provision cria os tópicos e assinaturas declarados que ainda não existem no projeto.
+func (a *PubSubClientAdapter) Provision(ctx context.Context, cfg types.BrokerConfig) error {
+	topics := map[string]*pubsub.Topic{}
+	ensureTopic := func(id string) (*pubsub.Topic, error) {
+		if topic, ok := topics[id]; ok {
+			return topic, nil
+		}
+		topic := a.client.Topic(id)
+		exists, err := topic.Exists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao verificar tópico %s: %w", id, err)
+		}
+		if !exists {
+			if topic, err = a.client.CreateTopic(ctx, id); err != nil {
+				return nil, fmt.Errorf("erro ao criar tópico %s: %w", id, err)
+			}
+			slog.InfoContext(ctx, "Tópico criado", "topic", id)
+		}
+		topics[id] = topic
+		return topic, nil
+	}
+
+	for _, id := range cfg.Topics {
+		if _, err := ensureTopic(id); err != nil {
+			return err
+		}
+	}
+	for _, sub := range cfg.Subscriptions {
+		topic, err := ensureTopic(sub.Topic)
+		if err != nil {
+			return err
+		}
+		exists, err := a.client.Subscription(sub.ID).Exists(ctx)
+		if err != nil {
+			return fmt.Errorf("erro ao verificar assinatura %s: %w", sub.ID, err)
+		}
+		if exists {
+			continue
+		}
+		ackDeadline := sub.AckDeadline
+		if ackDeadline == 0 {
+			ackDeadline = cfg.AckDeadline
+		}
+		_, err = a.client.CreateSubscription(ctx, sub.ID, pubsub.SubscriptionConfig{
+			Topic:                 topic,
+			AckDeadline:           ackDeadline,
+			EnableMessageOrdering: sub.EnableOrdering,
+		})
+		if err != nil {
+			return fmt.Errorf("erro ao criar assinatura %s: %w", sub.ID, err)
+		}
+		slog.InfoContext(ctx, "Assinatura criada", "subscription", sub.ID, "topic", sub.Topic)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package pubsubadapter_test
+
+import (
+	"context"
+	"queue/core/domain/types"
+	"queue/core/infra/adapter"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func newFakePubSubClient(t *testing.T) *pubsub.Client {
+	t.Helper()
+	srv := pstest.NewServer()
+	t.Cleanup(func() { _ = srv.Close() })
+	conn, err := grpc.NewClient(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("erro ao conectar no servidor fake: %v", err)
+	}
+	client, err := pubsub.NewClient(context.Background(), "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("erro ao criar client: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestPubSubClientAdapter_Provision(t *testing.T) {
+	ctx := context.Background()
+	client := newFakePubSubClient(t)
+	adapter := pubsubadapter.NewPubSubClientAdapter(client)
+	cfg := types.BrokerConfig{
+		AckDeadline: 20 * time.Second,
+		Topics:      []string{"audit"},
+		Subscriptions: []types.SubscriptionConfig{
+			{ID: "notifications-sub", Topic: "notifications"},
+			{ID: "hml-notifications-sub", Topic: "notifications", AckDeadline: 30 * time.Second},
+		},
+	}
+
+	assert.NoError(t, adapter.Provision(ctx, cfg))
+	// Segunda execução não deve falhar com recursos já existentes
+	assert.NoError(t, adapter.Provision(ctx, cfg))
+
+	for _, id := range []string{"audit", "notifications"} {
+		exists, err := client.Topic(id).Exists(ctx)
+		assert.NoError(t, err)
+		assert.True(t, exists, "tópico %s deveria existir", id)
+	}
+	subCfg, err := client.Subscription("notifications-sub").Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications", subCfg.Topic.ID())
+	assert.Equal(t, 20*time.Second, subCfg.AckDeadline)
+
+	subCfg, err = client.Subscription("hml-notifications-sub").Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, subCfg.AckDeadline)
+}
+
+func TestTopicAdapter_PublishComChaveDeOrdenacao(t *testing.T) {
+	ctx := context.Background()
+	client := newFakePubSubClient(t)
+	adapter := pubsubadapter.NewPubSubClientAdapter(client)
+	assert.NoError(t, adapter.Provision(ctx, types.BrokerConfig{
+		Subscriptions: []types.SubscriptionConfig{{ID: "ordered-sub", Topic: "ordered", EnableOrdering: true}},
+	}))
+
+	topic := adapter.Topic("ordered")
+	defer topic.Stop()
+	id, err := topic.Publish(ctx, &types.Message{
+		Data:        []byte("x"),
+		Attributes:  map[string]string{"tenant": "acme"},
+		OrderingKey: "user-1",
+	}).Get(ctx)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	subCfg, err := client.Subscription("ordered-sub").Config(ctx)
+	assert.NoError(t, err)
+	assert.True(t, subCfg.EnableMessageOrdering)
+}
@@ -0,0 +1,237 @@
+package cloudevents
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"strings"
+	"time"
+)
+
+const (
+	SpecVersion = "1.0"
+	// ContentType identifica o modo estruturado no HTTP.
+	ContentType = "application/cloudevents+json"
+	// AttributePrefix é o prefixo dos atributos do CloudEvent no broker e dos headers no modo binário do HTTP.
+	AttributePrefix = "ce-"
+	// ContentTypeAttribute carrega o datacontenttype no modo binário do broker.
+	ContentTypeAttribute = "content-type"
+	// TopicExtension permite escolher o tópico explicitamente; sem ela o tópico é o type do evento.
+	TopicExtension = "topic"
+)
+
+var coreAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"subject":         true,
+	"time":            true,
+	"datacontenttype": true,
+	"data":            true,
+	"data_base64":     true,
+}
+
+// IsHTTPRequest identifica requisições CloudEvents nos modos estruturado e binário.
+func IsHTTPRequest(header http.Header) bool {
+	return isStructuredContentType(header.Get("Content-Type")) || header.Get("Ce-Specversion") != ""
+}
+
+// FromHTTPRequest lê o CloudEvent do corpo (modo estruturado) ou dos headers ce-* (modo binário).
+func FromHTTPRequest(header http.Header, body []byte) (*types.Event, error) {
+	if isStructuredContentType(header.Get("Content-Type")) {
+		return ParseStructured(body)
+	}
+	attrs := map[string]string{}
+	for key, values := range header {
+		name := strings.ToLower(key)
+		if !strings.HasPrefix(name, AttributePrefix) || len(values) == 0 {
+			continue
+		}
+		value, err := url.PathUnescape(values[0])
+		if err != nil {
+			value = values[0]
+		}
+		attrs[strings.TrimPrefix(name, AttributePrefix)] = value
+	}
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		attrs["datacontenttype"] = contentType
+	}
+	return fromAttributes(attrs, body)
+}
+
+// ParseStructured lê um CloudEvent no formato JSON estruturado.
+func ParseStructured(body []byte) (*types.Event, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar CloudEvent: %w", err)
+	}
+	attrs := map[string]string{}
+	for name, value := range raw {
+		if name == "data" || name == "data_base64" {
+			continue
+		}
+		attrs[name] = jsonScalar(value)
+	}
+	event, err := fromAttributes(attrs, nil)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := raw["data_base64"]; ok {
+		var encoded string
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return nil, fmt.Errorf("data_base64 inválido: %w", err)
+		}
+		if event.Data, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("data_base64 inválido: %w", err)
+		}
+		return event, nil
+	}
+	if data, ok := raw["data"]; ok {
+		event.Data = []byte(data)
+		// Dados não JSON chegam como string JSON no modo estruturado.
+		var text string
+		if !event.HasJSONData() && json.Unmarshal(data, &text) == nil {
+			event.Data = []byte(text)
+		}
+	}
+	return event, nil
+}
+
+// Attributes converte o evento nos atributos da mensagem publicada no modo binário do broker.
+func Attributes(event *types.Event) map[string]string {
+	attrs := map[string]string{
+		AttributePrefix + "specversion": SpecVersion,
+		AttributePrefix + "id":          event.ID,
+		AttributePrefix + "source":      event.Source,
+		AttributePrefix + "type":        event.Type,
+	}
+	if event.Subject != "" {
+		attrs[AttributePrefix+"subject"] = event.Subject
+	}
+	if !event.Time.IsZero() {
+		attrs[AttributePrefix+"time"] = event.Time.Format(time.RFC3339Nano)
+	}
+	if event.DataContentType != "" {
+		attrs[ContentTypeAttribute] = event.DataContentType
+	}
+	for name, value := range event.Extensions {
+		attrs[AttributePrefix+name] = value
+	}
+	return attrs
+}
+
+// FromMessage decodifica a mensagem recebida do broker em um evento uniforme, aceitando
+// CloudEvents no modo binário (atributos ce-*), no modo estruturado e o envelope {meta, data}.
+func FromMessage(msg *types.Message) (*types.Event, error) {
+	if msg.Attributes[AttributePrefix+"specversion"] != "" {
+		attrs := map[string]string{}
+		for key, value := range msg.Attributes {
+			if strings.HasPrefix(key, AttributePrefix) {
+				attrs[strings.TrimPrefix(key, AttributePrefix)] = value
+			}
+		}
+		if contentType, ok := msg.Attributes[ContentTypeAttribute]; ok {
+			attrs["datacontenttype"] = contentType
+		}
+		return fromAttributes(attrs, msg.Data)
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Data, &envelope); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar envelope: %w", err)
+	}
+	if _, ok := envelope["specversion"]; ok {
+		return ParseStructured(msg.Data)
+	}
+	data, ok := envelope["data"]
+	if !ok {
+		return nil, errors.New("campo 'data' não encontrado na mensagem")
+	}
+	var meta struct {
+		Topic string `json:"topic"`
+	}
+	_ = json.Unmarshal(envelope["meta"], &meta)
+	return &types.Event{
+		Topic:           meta.Topic,
+		ID:              msg.ID,
+		Type:            meta.Topic,
+		Time:            msg.PublishTime,
+		DataContentType: "application/json",
+		Data:            []byte(data),
+		Extensions:      copyAttributes(msg.Attributes),
+	}, nil
+}
+
+func fromAttributes(attrs map[string]string, data []byte) (*types.Event, error) {
+	var msgs []string
+	if version := attrs["specversion"]; version != SpecVersion {
+		msgs = append(msgs, fmt.Sprintf("A versão %q do CloudEvents não é suportada; use %s.", version, SpecVersion))
+	}
+	for _, name := range []string{"id", "source", "type"} {
+		if attrs[name] == "" {
+			msgs = append(msgs, fmt.Sprintf("O atributo %s do CloudEvent é obrigatório.", name))
+		}
+	}
+	event := &types.Event{
+		ID:              attrs["id"],
+		Source:          attrs["source"],
+		Type:            attrs["type"],
+		Subject:         attrs["subject"],
+		DataContentType: attrs["datacontenttype"],
+		Data:            data,
+	}
+	if value := attrs["time"]; value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			msgs = append(msgs, "O atributo time do CloudEvent deve estar no formato RFC 3339.")
+		}
+		event.Time = parsed
+	}
+	if len(msgs) > 0 {
+		return nil, &structs.ValidationMessagesError{Messages: msgs}
+	}
+	for name, value := range attrs {
+		if coreAttributes[name] {
+			continue
+		}
+		if event.Extensions == nil {
+			event.Extensions = map[string]string{}
+		}
+		event.Extensions[name] = value
+	}
+	event.Topic = event.Extensions[TopicExtension]
+	if event.Topic == "" {
+		event.Topic = event.Type
+	}
+	return event, nil
+}
+
+func isStructuredContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), ContentType)
+}
+
+// jsonScalar converte o valor de um atributo para string; strings JSON perdem as aspas.
+func jsonScalar(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
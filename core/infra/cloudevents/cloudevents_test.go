@@ -0,0 +1,153 @@
+package cloudevents_test
+
+import (
+	"errors"
+	"net/http"
+	"queue/core/domain/structs"
+	"queue/core/domain/types"
+	"queue/core/infra/cloudevents"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsHTTPRequest(t *testing.T) {
+	assert.True(t, cloudevents.IsHTTPRequest(http.Header{"Content-Type": {"application/cloudevents+json; charset=utf-8"}}))
+	assert.True(t, cloudevents.IsHTTPRequest(http.Header{"Ce-Specversion": {"1.0"}}))
+	assert.False(t, cloudevents.IsHTTPRequest(http.Header{"Content-Type": {"application/json"}}))
+}
+
+func TestFromHTTPRequest_Estruturado(t *testing.T) {
+	header := http.Header{"Content-Type": {cloudevents.ContentType}}
+	body := `{"specversion":"1.0","id":"1","source":"/crm","type":"user.created","subject":"42",
+		"time":"2025-01-02T08:00:00Z","tenant":"acme","retries":3,"data":{"name":"Ada"}}`
+
+	event, err := cloudevents.FromHTTPRequest(header, []byte(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", event.ID)
+	assert.Equal(t, "/crm", event.Source)
+	assert.Equal(t, "user.created", event.Type)
+	assert.Equal(t, "42", event.Subject)
+	assert.Equal(t, time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC), event.Time)
+	assert.Equal(t, map[string]string{"tenant": "acme", "retries": "3"}, event.Extensions)
+	assert.Equal(t, "user.created", event.Topic)
+	assert.JSONEq(t, `{"name":"Ada"}`, string(event.Data))
+}
+
+func TestParseStructured_DadosNaoJSON(t *testing.T) {
+	event, err := cloudevents.ParseStructured([]byte(`{"specversion":"1.0","id":"1","source":"s","type":"t","datacontenttype":"text/plain","data":"olá"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "olá", string(event.Data))
+
+	event, err = cloudevents.ParseStructured([]byte(`{"specversion":"1.0","id":"1","source":"s","type":"t","data_base64":"b2zDoQ=="}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "olá", string(event.Data))
+}
+
+func TestFromHTTPRequest_Binario(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Ce-Specversion", "1.0")
+	header.Set("Ce-Id", "1")
+	header.Set("Ce-Source", "/crm")
+	header.Set("Ce-Type", "user.created")
+	header.Set("Ce-Tenant", "acme%20corp")
+
+	event, err := cloudevents.FromHTTPRequest(header, []byte(`{"name":"Ada"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "user.created", event.Type)
+	assert.Equal(t, "user.created", event.Topic)
+	assert.Equal(t, "application/json", event.DataContentType)
+	assert.Equal(t, map[string]string{"tenant": "acme corp"}, event.Extensions)
+	assert.Equal(t, `{"name":"Ada"}`, string(event.Data))
+}
+
+func TestFromHTTPRequest_AtributosObrigatorios(t *testing.T) {
+	_, err := cloudevents.ParseStructured([]byte(`{"specversion":"0.3","time":"ontem"}`))
+	var ve *structs.ValidationMessagesError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, []string{
+		`A versão "0.3" do CloudEvents não é suportada; use 1.0.`,
+		"O atributo id do CloudEvent é obrigatório.",
+		"O atributo source do CloudEvent é obrigatório.",
+		"O atributo type do CloudEvent é obrigatório.",
+		"O atributo time do CloudEvent deve estar no formato RFC 3339.",
+	}, ve.Messages)
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := cloudevents.Attributes(&types.Event{
+		ID:              "1",
+		Source:          "/crm",
+		Type:            "user.created",
+		Subject:         "42",
+		Time:            time.Date(2025, 1, 2, 8, 0, 0, 0, time.UTC),
+		DataContentType: "application/json",
+		Extensions:      map[string]string{"tenant": "acme"},
+	})
+	assert.Equal(t, map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          "1",
+		"ce-source":      "/crm",
+		"ce-type":        "user.created",
+		"ce-subject":     "42",
+		"ce-time":        "2025-01-02T08:00:00Z",
+		"content-type":   "application/json",
+		"ce-tenant":      "acme",
+	}, attrs)
+}
+
+func TestFromMessage(t *testing.T) {
+	publishTime := time.Now()
+	original := &types.Event{ID: "1", Source: "/crm", Type: "user.created", Extensions: map[string]string{"tenant": "acme"}}
+
+	cases := map[string]*types.Message{
+		"binário": {
+			Data:       []byte(`{"name":"Ada"}`),
+			Attributes: cloudevents.Attributes(original),
+		},
+		"estruturado": {
+			Data: []byte(`{"specversion":"1.0","id":"1","source":"/crm","type":"user.created","tenant":"acme","data":{"name":"Ada"}}`),
+		},
+	}
+	for name, msg := range cases {
+		t.Run(name, func(t *testing.T) {
+			event, err := cloudevents.FromMessage(msg)
+			assert.NoError(t, err)
+			assert.Equal(t, "1", event.ID)
+			assert.Equal(t, "user.created", event.Type)
+			assert.Equal(t, "acme", event.Extensions["tenant"])
+			assert.JSONEq(t, `{"name":"Ada"}`, string(event.Data))
+		})
+	}
+
+	t.Run("envelope legado", func(t *testing.T) {
+		event, err := cloudevents.FromMessage(&types.Message{
+			ID:          "msg-1",
+			Data:        []byte(`{"meta":{"topic":"notifications"},"data":{"name":"Ada"}}`),
+			Attributes:  map[string]string{"tenant": "acme"},
+			PublishTime: publishTime,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "msg-1", event.ID)
+		assert.Equal(t, "notifications", event.Topic)
+		assert.Equal(t, "notifications", event.Type)
+		assert.Equal(t, publishTime, event.Time)
+		assert.Equal(t, "acme", event.Extensions["tenant"])
+		assert.JSONEq(t, `{"name":"Ada"}`, string(event.Data))
+	})
+
+	t.Run("inválido", func(t *testing.T) {
+		_, err := cloudevents.FromMessage(&types.Message{Data: []byte(`{not-json}`)})
+		assert.Error(t, err)
+		_, err = cloudevents.FromMessage(&types.Message{Data: []byte(`{"meta":{}}`)})
+		assert.EqualError(t, err, "campo 'data' não encontrado na mensagem")
+	})
+}
+
+func TestParseStructured_TopicoPorExtensao(t *testing.T) {
+	event, err := cloudevents.ParseStructured([]byte(`{"specversion":"1.0","id":"1","source":"s","type":"com.acme.user.created","topic":"notifications"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications", event.Topic)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type topologyFile struct {
+	Topics        []string               `json:"topics"`
+	Subscriptions []topologySubscription `json:"subscriptions"`
+}
+
+type topologySubscription struct {
+	ID                     string `json:"id"`
+	Topic                  string `json:"topic"`
+	AckDeadlineSeconds     int    `json:"ackDeadlineSeconds"`
+	EnableMessageOrdering  bool   `json:"enableMessageOrdering"`
+	MaxOutstandingMessages int    `json:"maxOutstandingMessages"`
+	MaxOutstandingBytes    int    `json:"maxOutstandingBytes"`
+	NumGoroutines          int    `json:"numGoroutines"`
+}
+
+// loadBrokerConfig devolve o erro do arquivo de topologia configurado, para que o serviço não suba
+// sem os tópicos e assinaturas que ele declarava.
+func loadBrokerConfig() (types.BrokerConfig, error) {
+	ackDeadline, _ := strconv.Atoi(os.Getenv("BROKER_ACK_DEADLINE_SECONDS"))
+	autoProvision, _ := strconv.ParseBool(os.Getenv("BROKER_AUTO_PROVISION"))
+	cfg := types.BrokerConfig{
+		Type:          loadBrokerType(),
+		AckDeadline:   time.Duration(ackDeadline) * time.Second,
+		AutoProvision: autoProvision,
+		Subscriptions: parseSubscriptions(os.Getenv("BROKER_SUBSCRIPTIONS")),
+		Receive:       loadReceiveSettings(),
+	}
+	if path := os.Getenv("BROKER_TOPOLOGY_FILE"); path != "" {
+		if err := LoadTopologyFile(path, &cfg); err != nil {
+			return cfg, fmt.Errorf("erro ao carregar BROKER_TOPOLOGY_FILE %s: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// loadReceiveSettings lê os limites de recebimento aplicados às assinaturas sem configuração própria.
+func loadReceiveSettings() types.ReceiveSettings {
+	maxMessages, _ := strconv.Atoi(os.Getenv("BROKER_MAX_OUTSTANDING_MESSAGES"))
+	maxBytes, _ := strconv.Atoi(os.Getenv("BROKER_MAX_OUTSTANDING_BYTES"))
+	goroutines, _ := strconv.Atoi(os.Getenv("BROKER_NUM_GOROUTINES"))
+	return types.ReceiveSettings{
+		MaxOutstandingMessages: maxMessages,
+		MaxOutstandingBytes:    maxBytes,
+		NumGoroutines:          goroutines,
+	}
+}
+
+func loadBrokerType() string {
+	broker := strings.ToLower(strings.TrimSpace(os.Getenv("BROKER")))
+	if broker == "" {
+		return string(enum.PubSubBroker)
+	}
+	return broker
+}
+
+// parseSubscriptions interpreta o formato "assinatura=tópico,assinatura2=tópico2".
+func parseSubscriptions(raw string) []types.SubscriptionConfig {
+	var subs []types.SubscriptionConfig
+	for _, item := range strings.Split(raw, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		subs = append(subs, types.SubscriptionConfig{
+			ID:    strings.TrimSpace(parts[0]),
+			Topic: strings.TrimSpace(parts[1]),
+		})
+	}
+	return subs
+}
+
+// LoadTopologyFile acrescenta à configuração os tópicos e assinaturas declarados em um arquivo JSON.
+func LoadTopologyFile(path string, cfg *types.BrokerConfig) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file topologyFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return fmt.Errorf("arquivo de topologia inválido: %w", err)
+	}
+	cfg.Topics = append(cfg.Topics, file.Topics...)
+	for _, sub := range file.Subscriptions {
+		if sub.ID == "" || sub.Topic == "" {
+			return fmt.Errorf("assinatura sem id ou tópico no arquivo de topologia")
+		}
+		cfg.Subscriptions = append(cfg.Subscriptions, types.SubscriptionConfig{
+			ID:             sub.ID,
+			Topic:          sub.Topic,
+			AckDeadline:    time.Duration(sub.AckDeadlineSeconds) * time.Second,
+			EnableOrdering: sub.EnableMessageOrdering,
+			Receive: types.ReceiveSettings{
+				MaxOutstandingMessages: sub.MaxOutstandingMessages,
+				MaxOutstandingBytes:    sub.MaxOutstandingBytes,
+				NumGoroutines:          sub.NumGoroutines,
+			},
+		})
+	}
+	return nil
+}
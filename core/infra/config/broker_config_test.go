@@ -0,0 +1,82 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"queue/core/domain/types"
+	"queue/core/infra/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTopology(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topology.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("erro ao escrever arquivo: %v", err)
+	}
+	return path
+}
+
+func TestLoadTopologyFile(t *testing.T) {
+	path := writeTopology(t, `{
+		"topics": ["audit"],
+		"subscriptions": [{"id": "notifications-sub", "topic": "notifications", "ackDeadlineSeconds": 30, "enableMessageOrdering": true, "maxOutstandingMessages": 50, "maxOutstandingBytes": 1048576, "numGoroutines": 2}]
+	}`)
+	cfg := types.BrokerConfig{
+		Subscriptions: []types.SubscriptionConfig{{ID: "env-sub", Topic: "env"}},
+	}
+
+	err := config.LoadTopologyFile(path, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"audit"}, cfg.Topics)
+	assert.Equal(t, []types.SubscriptionConfig{
+		{ID: "env-sub", Topic: "env"},
+		{
+			ID: "notifications-sub", Topic: "notifications", AckDeadline: 30 * time.Second, EnableOrdering: true,
+			Receive: types.ReceiveSettings{MaxOutstandingMessages: 50, MaxOutstandingBytes: 1048576, NumGoroutines: 2},
+		},
+	}, cfg.Subscriptions)
+}
+
+func TestLoadTopologyFile_Invalido(t *testing.T) {
+	cfg := types.BrokerConfig{}
+	assert.Error(t, config.LoadTopologyFile(writeTopology(t, `{not-json}`), &cfg))
+	assert.Error(t, config.LoadTopologyFile(writeTopology(t, `{"subscriptions":[{"id":"sem-topico"}]}`), &cfg))
+	assert.Error(t, config.LoadTopologyFile(filepath.Join(t.TempDir(), "nao-existe.json"), &cfg))
+}
+
+func TestLoadConfig_TopologiaEEmulador(t *testing.T) {
+	path := writeTopology(t, `{"subscriptions": [{"id": "notifications-sub", "topic": "notifications"}]}`)
+	t.Setenv("BROKER_SUBSCRIPTIONS", "")
+	t.Setenv("BROKER_TOPOLOGY_FILE", path)
+	t.Setenv("BROKER_AUTO_PROVISION", "true")
+	t.Setenv("PUBSUB_EMULATOR_HOST", "localhost:8085")
+
+	cfg := config.LoadConfig()
+	assert.True(t, cfg.Broker.AutoProvision)
+	assert.Equal(t, "localhost:8085", cfg.Google.EmulatorHost)
+	assert.Equal(t, []types.SubscriptionConfig{{ID: "notifications-sub", Topic: "notifications"}}, cfg.Broker.Subscriptions)
+}
+
+func TestLoadConfig_TopologiaInvalida(t *testing.T) {
+	t.Setenv("BROKER_TOPOLOGY_FILE", filepath.Join(t.TempDir(), "nao-existe.json"))
+	assert.ErrorContains(t, config.LoadConfig().Broker.LoadErr, "BROKER_TOPOLOGY_FILE")
+
+	t.Setenv("BROKER_TOPOLOGY_FILE", writeTopology(t, `{not-json}`))
+	assert.ErrorContains(t, config.LoadConfig().Broker.LoadErr, "BROKER_TOPOLOGY_FILE")
+
+	t.Setenv("BROKER_TOPOLOGY_FILE", "")
+	assert.NoError(t, config.LoadConfig().Broker.LoadErr)
+}
+
+func TestLoadConfig_ReceiveSettings(t *testing.T) {
+	t.Setenv("BROKER_MAX_OUTSTANDING_MESSAGES", "100")
+	t.Setenv("BROKER_MAX_OUTSTANDING_BYTES", "")
+	t.Setenv("BROKER_NUM_GOROUTINES", "4")
+
+	cfg := config.LoadConfig()
+	assert.Equal(t, types.ReceiveSettings{MaxOutstandingMessages: 100, NumGoroutines: 4}, cfg.Broker.Receive)
+}
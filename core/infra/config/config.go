@@ -2,17 +2,57 @@ package config
 
 import (
 	"github.com/gin-contrib/cors"
+	"log/slog"
+	"net/http"
 	"os"
+	"queue/core/domain/enum"
 	"queue/core/domain/types"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	defaultIdempotencyTTLSeconds = 86400
+	defaultRestartMinBackoff     = time.Second
+	defaultRestartMaxBackoff     = time.Minute
+	defaultShutdownTimeout       = 30 * time.Second
+	defaultRetryMaxAttempts      = 3
+	defaultRetryInitialBackoff   = 200 * time.Millisecond
+	defaultRetryMaxBackoff       = 5 * time.Second
+	defaultDedupTTL              = 24 * time.Hour
+	defaultHandlerTimeout        = 30 * time.Second
+	defaultServiceName           = "queue"
+	defaultSchedulerStoreFile    = "data/scheduled_messages.json"
+)
+
+// defaultRetryableStatus são as respostas em que a API de notificações costuma se recuperar sozinha.
+var defaultRetryableStatus = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
 func LoadConfig() *types.Config {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
-	return &types.Config{
-		Environment: os.Getenv("ENVIRONMENT"),
-		Port:        port,
+	schedulerPoll, _ := strconv.Atoi(os.Getenv("SCHEDULER_POLL_INTERVAL_SECONDS"))
+	idempotencyTTL, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_SECONDS"))
+	if err != nil || idempotencyTTL <= 0 {
+		idempotencyTTL = defaultIdempotencyTTLSeconds
+	}
+	broker, brokerErr := loadBrokerConfig()
+	broker.LoadErr = brokerErr
+	handlers, handlersErr := loadHandlersConfig()
+	handlers.LoadErr = handlersErr
+	cfg := &types.Config{
+		Environment:     os.Getenv("ENVIRONMENT"),
+		Mode:            strings.ToLower(strings.TrimSpace(envOrDefault("RUN_MODE", string(enum.AllMode)))),
+		Port:            port,
+		ShutdownTimeout: durationSecondsOrDefault("SHUTDOWN_TIMEOUT_SECONDS", defaultShutdownTimeout),
 		CorsConfig: cors.Config{
 			AllowOrigins:     []string{"*"},
 			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
@@ -26,9 +66,32 @@ func LoadConfig() *types.Config {
 			Password: os.Getenv("BASIC_AUTH_PASSWORD"),
 		},
 		Google: types.GoogleConfig{
-			ProjectID:   os.Getenv("PROJECT_ID"),
-			Credentials: os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"),
+			ProjectID:    os.Getenv("PROJECT_ID"),
+			Credentials:  os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"),
+			EmulatorHost: os.Getenv("PUBSUB_EMULATOR_HOST"),
+		},
+		Broker: broker,
+		Scheduler: types.SchedulerConfig{
+			StoreFile:    envOrDefault("SCHEDULER_STORE_FILE", defaultSchedulerStoreFile),
+			PollInterval: time.Duration(schedulerPoll) * time.Second,
+		},
+		Idempotency: types.IdempotencyConfig{
+			StoreFile: os.Getenv("IDEMPOTENCY_STORE_FILE"),
+			TTL:       time.Duration(idempotencyTTL) * time.Second,
 		},
+		TopicRegistry: types.TopicRegistryConfig{
+			File: os.Getenv("TOPIC_REGISTRY_FILE"),
+		},
+		Handlers:          handlers,
+		Subscriber:        loadSubscriberConfig(),
+		NotificationRetry: loadNotificationRetry(),
+		DeadLetter:        loadDeadLetterConfig(),
+		Dedup:             loadDedupConfig(),
+		Metrics: types.MetricsConfig{
+			Enabled: boolOrDefault("METRICS_ENABLED", true),
+		},
+		Tracing: loadTracingConfig(),
+		Log:     loadLogConfig(),
 		URLs: types.URLsConfig{
 			Frontend:     os.Getenv("FRONTEND_URL"),
 			API:          os.Getenv("API_URL"),
@@ -37,4 +100,154 @@ func LoadConfig() *types.Config {
 			Queue:        os.Getenv("QUEUE_URL"),
 		},
 	}
+	addDeadLetterTopology(cfg)
+	return cfg
+}
+
+// addDeadLetterTopology inclui o tópico de dead-letter e a assinatura de inspeção na topologia,
+// para que sejam criados junto com os demais.
+func addDeadLetterTopology(cfg *types.Config) {
+	dl := cfg.DeadLetter
+	if dl.Topic == "" {
+		return
+	}
+	if !slices.Contains(cfg.Broker.Topics, dl.Topic) {
+		cfg.Broker.Topics = append(cfg.Broker.Topics, dl.Topic)
+	}
+	if dl.Subscription == "" {
+		return
+	}
+	for _, sub := range cfg.Broker.Subscriptions {
+		if sub.ID == dl.Subscription {
+			return
+		}
+	}
+	cfg.Broker.Subscriptions = append(cfg.Broker.Subscriptions, types.SubscriptionConfig{ID: dl.Subscription, Topic: dl.Topic})
+}
+
+func loadSubscriberConfig() types.SubscriberConfig {
+	return types.SubscriberConfig{
+		RestartMinBackoff: durationSecondsOrDefault("SUBSCRIBER_RESTART_MIN_BACKOFF_SECONDS", defaultRestartMinBackoff),
+		RestartMaxBackoff: durationSecondsOrDefault("SUBSCRIBER_RESTART_MAX_BACKOFF_SECONDS", defaultRestartMaxBackoff),
+	}
+}
+
+func loadDeadLetterConfig() types.DeadLetterConfig {
+	attempts, _ := strconv.Atoi(os.Getenv("DEAD_LETTER_MAX_DELIVERY_ATTEMPTS"))
+	return types.DeadLetterConfig{
+		Topic:               os.Getenv("DEAD_LETTER_TOPIC"),
+		MaxDeliveryAttempts: attempts,
+		Subscription:        os.Getenv("DEAD_LETTER_SUBSCRIPTION"),
+		AdminAuth: types.BasicAuthConfig{
+			Username: os.Getenv("DEAD_LETTER_ADMIN_USERNAME"),
+			Password: os.Getenv("DEAD_LETTER_ADMIN_PASSWORD"),
+		},
+	}
+}
+
+func loadDedupConfig() types.DedupConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEDUP_ENABLED"))
+	maxEntries, _ := strconv.Atoi(os.Getenv("DEDUP_MAX_ENTRIES"))
+	var key []string
+	for _, field := range strings.Split(os.Getenv("DEDUP_KEY"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			key = append(key, field)
+		}
+	}
+	return types.DedupConfig{
+		Enabled:    enabled,
+		Key:        key,
+		TTL:        durationSecondsOrDefault("DEDUP_TTL_SECONDS", defaultDedupTTL),
+		MaxEntries: maxEntries,
+		StoreFile:  os.Getenv("DEDUP_STORE_FILE"),
+	}
+}
+
+func loadTracingConfig() types.TracingConfig {
+	ratio, err := strconv.ParseFloat(os.Getenv("TRACING_SAMPLE_RATIO"), 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		ratio = 1
+	}
+	return types.TracingConfig{
+		Exporter:    strings.ToLower(strings.TrimSpace(envOrDefault("TRACING_EXPORTER", string(enum.NoTracingExporter)))),
+		ServiceName: envOrDefault("OTEL_SERVICE_NAME", defaultServiceName),
+		SampleRatio: ratio,
+	}
+}
+
+// loadLogConfig usa JSON por padrão e texto quando ENVIRONMENT é vazio, dev ou local.
+// Níveis inválidos em LOG_LEVEL caem para info.
+func loadLogConfig() types.LogConfig {
+	format := enum.JSONLogFormat
+	switch os.Getenv("ENVIRONMENT") {
+	case "", "dev", "local":
+		format = enum.TextLogFormat
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(os.Getenv("LOG_LEVEL")))); err != nil {
+		level = slog.LevelInfo
+	}
+	return types.LogConfig{
+		Format: strings.ToLower(strings.TrimSpace(envOrDefault("LOG_FORMAT", string(format)))),
+		Level:  level,
+	}
+}
+
+func loadNotificationRetry() types.RetryPolicy {
+	attempts, err := strconv.Atoi(os.Getenv("NOTIFICATION_RETRY_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		attempts = defaultRetryMaxAttempts
+	}
+	return types.RetryPolicy{
+		MaxAttempts:     attempts,
+		InitialBackoff:  durationMillisOrDefault("NOTIFICATION_RETRY_INITIAL_BACKOFF_MS", defaultRetryInitialBackoff),
+		MaxBackoff:      durationMillisOrDefault("NOTIFICATION_RETRY_MAX_BACKOFF_MS", defaultRetryMaxBackoff),
+		RetryableStatus: parseStatusCodes(os.Getenv("NOTIFICATION_RETRY_STATUS_CODES"), defaultRetryableStatus),
+	}
+}
+
+// parseStatusCodes interpreta o formato "429,503", ignorando valores inválidos.
+func parseStatusCodes(raw string, fallback []int) []int {
+	var codes []int
+	for _, item := range strings.Split(raw, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(item))
+		if err == nil && code > 0 {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return fallback
+	}
+	return codes
+}
+
+func durationMillisOrDefault(key string, fallback time.Duration) time.Duration {
+	millis, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || millis <= 0 {
+		return fallback
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+func durationSecondsOrDefault(key string, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func boolOrDefault(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
@@ -1,9 +1,12 @@
 package config_test
 
 import (
+	"log/slog"
 	"os"
+	"queue/core/domain/types"
 	"queue/core/infra/config"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -20,6 +23,9 @@ func TestLoadConfig(t *testing.T) {
 	os.Setenv("NOTIFICATION_URL", "http://notification")
 	os.Setenv("STORAGE_URL", "http://storage")
 	os.Setenv("QUEUE_URL", "http://queue")
+	os.Setenv("BROKER", "Memory")
+	os.Setenv("BROKER_ACK_DEADLINE_SECONDS", "30")
+	os.Setenv("BROKER_SUBSCRIPTIONS", "notifications-sub=notifications, audit-sub=notifications,invalido")
 	cfg := config.LoadConfig()
 	assert.Equal(t, "test", cfg.Environment)
 	assert.Equal(t, 3003, cfg.Port)
@@ -32,4 +38,188 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "http://notification", cfg.URLs.Notification)
 	assert.Equal(t, "http://storage", cfg.URLs.Storage)
 	assert.Equal(t, "http://queue", cfg.URLs.Queue)
+	assert.Equal(t, "memory", cfg.Broker.Type)
+	assert.Equal(t, 30*time.Second, cfg.Broker.AckDeadline)
+	assert.Equal(t, []types.SubscriptionConfig{
+		{ID: "notifications-sub", Topic: "notifications"},
+		{ID: "audit-sub", Topic: "notifications"},
+	}, cfg.Broker.Subscriptions)
+}
+
+func TestLoadConfig_Scheduler(t *testing.T) {
+	t.Setenv("SCHEDULER_STORE_FILE", "")
+	t.Setenv("SCHEDULER_POLL_INTERVAL_SECONDS", "5")
+	cfg := config.LoadConfig()
+	assert.Equal(t, "data/scheduled_messages.json", cfg.Scheduler.StoreFile)
+	assert.Equal(t, 5*time.Second, cfg.Scheduler.PollInterval)
+
+	t.Setenv("SCHEDULER_STORE_FILE", "/tmp/agendamentos.json")
+	assert.Equal(t, "/tmp/agendamentos.json", config.LoadConfig().Scheduler.StoreFile)
+}
+
+func TestLoadConfig_Idempotency(t *testing.T) {
+	t.Setenv("IDEMPOTENCY_TTL_SECONDS", "")
+	t.Setenv("IDEMPOTENCY_STORE_FILE", "")
+	cfg := config.LoadConfig()
+	assert.Equal(t, 24*time.Hour, cfg.Idempotency.TTL)
+	assert.Empty(t, cfg.Idempotency.StoreFile)
+
+	t.Setenv("IDEMPOTENCY_TTL_SECONDS", "60")
+	t.Setenv("IDEMPOTENCY_STORE_FILE", "/tmp/idempotencia.json")
+	cfg = config.LoadConfig()
+	assert.Equal(t, time.Minute, cfg.Idempotency.TTL)
+	assert.Equal(t, "/tmp/idempotencia.json", cfg.Idempotency.StoreFile)
+}
+
+func TestLoadConfig_TopicRegistry(t *testing.T) {
+	t.Setenv("TOPIC_REGISTRY_FILE", "config/topics.json")
+	assert.Equal(t, "config/topics.json", config.LoadConfig().TopicRegistry.File)
+}
+
+func TestLoadConfig_BrokerPadrao(t *testing.T) {
+	os.Unsetenv("BROKER")
+	os.Unsetenv("BROKER_SUBSCRIPTIONS")
+	cfg := config.LoadConfig()
+	assert.Equal(t, "pubsub", cfg.Broker.Type)
+	assert.Empty(t, cfg.Broker.Subscriptions)
+}
+
+func TestLoadConfig_Subscriber(t *testing.T) {
+	t.Setenv("SUBSCRIBER_RESTART_MIN_BACKOFF_SECONDS", "")
+	t.Setenv("SUBSCRIBER_RESTART_MAX_BACKOFF_SECONDS", "")
+	cfg := config.LoadConfig()
+	assert.Equal(t, time.Second, cfg.Subscriber.RestartMinBackoff)
+	assert.Equal(t, time.Minute, cfg.Subscriber.RestartMaxBackoff)
+
+	t.Setenv("SUBSCRIBER_RESTART_MIN_BACKOFF_SECONDS", "2")
+	t.Setenv("SUBSCRIBER_RESTART_MAX_BACKOFF_SECONDS", "30")
+	cfg = config.LoadConfig()
+	assert.Equal(t, 2*time.Second, cfg.Subscriber.RestartMinBackoff)
+	assert.Equal(t, 30*time.Second, cfg.Subscriber.RestartMaxBackoff)
+}
+
+func TestLoadConfig_ShutdownTimeout(t *testing.T) {
+	t.Setenv("SHUTDOWN_TIMEOUT_SECONDS", "")
+	assert.Equal(t, 30*time.Second, config.LoadConfig().ShutdownTimeout)
+
+	t.Setenv("SHUTDOWN_TIMEOUT_SECONDS", "10")
+	assert.Equal(t, 10*time.Second, config.LoadConfig().ShutdownTimeout)
+}
+
+func TestLoadConfig_NotificationRetry(t *testing.T) {
+	t.Setenv("NOTIFICATION_RETRY_MAX_ATTEMPTS", "")
+	t.Setenv("NOTIFICATION_RETRY_INITIAL_BACKOFF_MS", "")
+	t.Setenv("NOTIFICATION_RETRY_MAX_BACKOFF_MS", "")
+	t.Setenv("NOTIFICATION_RETRY_STATUS_CODES", "")
+	cfg := config.LoadConfig()
+	assert.Equal(t, types.RetryPolicy{
+		MaxAttempts:     3,
+		InitialBackoff:  200 * time.Millisecond,
+		MaxBackoff:      5 * time.Second,
+		RetryableStatus: []int{408, 429, 500, 502, 503, 504},
+	}, cfg.NotificationRetry)
+
+	t.Setenv("NOTIFICATION_RETRY_MAX_ATTEMPTS", "5")
+	t.Setenv("NOTIFICATION_RETRY_INITIAL_BACKOFF_MS", "100")
+	t.Setenv("NOTIFICATION_RETRY_MAX_BACKOFF_MS", "1000")
+	t.Setenv("NOTIFICATION_RETRY_STATUS_CODES", "429, 503,abc")
+	cfg = config.LoadConfig()
+	assert.Equal(t, types.RetryPolicy{
+		MaxAttempts:     5,
+		InitialBackoff:  100 * time.Millisecond,
+		MaxBackoff:      time.Second,
+		RetryableStatus: []int{429, 503},
+	}, cfg.NotificationRetry)
+}
+
+func TestLoadConfig_DeadLetter(t *testing.T) {
+	t.Setenv("BROKER_TOPOLOGY_FILE", "")
+	t.Setenv("DEAD_LETTER_TOPIC", "dlq.notifications")
+	t.Setenv("DEAD_LETTER_MAX_DELIVERY_ATTEMPTS", "7")
+	t.Setenv("DEAD_LETTER_SUBSCRIPTION", "")
+	t.Setenv("DEAD_LETTER_ADMIN_USERNAME", "")
+	t.Setenv("DEAD_LETTER_ADMIN_PASSWORD", "")
+	cfg := config.LoadConfig()
+	assert.Equal(t, types.DeadLetterConfig{Topic: "dlq.notifications", MaxDeliveryAttempts: 7}, cfg.DeadLetter)
+	assert.Contains(t, cfg.Broker.Topics, "dlq.notifications")
+
+	t.Setenv("DEAD_LETTER_SUBSCRIPTION", "dlq.notifications-sub")
+	t.Setenv("BROKER_SUBSCRIPTIONS", "")
+	cfg = config.LoadConfig()
+	assert.Equal(t, []types.SubscriptionConfig{{ID: "dlq.notifications-sub", Topic: "dlq.notifications"}}, cfg.Broker.Subscriptions)
+
+	t.Setenv("DEAD_LETTER_ADMIN_USERNAME", "ops")
+	t.Setenv("DEAD_LETTER_ADMIN_PASSWORD", "456")
+	assert.Equal(t, types.BasicAuthConfig{Username: "ops", Password: "456"}, config.LoadConfig().DeadLetter.AdminAuth)
+}
+
+func TestLoadConfig_Dedup(t *testing.T) {
+	t.Setenv("DEDUP_ENABLED", "")
+	t.Setenv("DEDUP_KEY", "")
+	t.Setenv("DEDUP_TTL_SECONDS", "")
+	t.Setenv("DEDUP_MAX_ENTRIES", "")
+	t.Setenv("DEDUP_STORE_FILE", "")
+	assert.Equal(t, types.DedupConfig{TTL: 24 * time.Hour}, config.LoadConfig().Dedup)
+
+	t.Setenv("DEDUP_ENABLED", "true")
+	t.Setenv("DEDUP_KEY", "data.userId, data.channel,data,")
+	t.Setenv("DEDUP_TTL_SECONDS", "600")
+	t.Setenv("DEDUP_MAX_ENTRIES", "1000")
+	t.Setenv("DEDUP_STORE_FILE", "data/dedup.jsonl")
+	assert.Equal(t, types.DedupConfig{
+		Enabled:    true,
+		Key:        []string{"data.userId", "data.channel", "data"},
+		TTL:        10 * time.Minute,
+		MaxEntries: 1000,
+		StoreFile:  "data/dedup.jsonl",
+	}, config.LoadConfig().Dedup)
+}
+
+func TestLoadConfig_Metrics(t *testing.T) {
+	t.Setenv("METRICS_ENABLED", "")
+	assert.True(t, config.LoadConfig().Metrics.Enabled)
+
+	t.Setenv("METRICS_ENABLED", "false")
+	assert.False(t, config.LoadConfig().Metrics.Enabled)
+
+	t.Setenv("METRICS_ENABLED", "talvez")
+	assert.True(t, config.LoadConfig().Metrics.Enabled)
+}
+
+func TestLoadConfig_Tracing(t *testing.T) {
+	t.Setenv("TRACING_EXPORTER", "")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	t.Setenv("TRACING_SAMPLE_RATIO", "")
+	assert.Equal(t, types.TracingConfig{Exporter: "none", ServiceName: "queue", SampleRatio: 1}, config.LoadConfig().Tracing)
+
+	t.Setenv("TRACING_EXPORTER", " OTLP ")
+	t.Setenv("OTEL_SERVICE_NAME", "queue-publisher")
+	t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+	assert.Equal(t, types.TracingConfig{Exporter: "otlp", ServiceName: "queue-publisher", SampleRatio: 0.25}, config.LoadConfig().Tracing)
+
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+	assert.Equal(t, 1.0, config.LoadConfig().Tracing.SampleRatio)
+}
+
+func TestLoadConfig_Log(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("LOG_LEVEL", "")
+	assert.Equal(t, types.LogConfig{Format: "text", Level: slog.LevelInfo}, config.LoadConfig().Log)
+
+	t.Setenv("ENVIRONMENT", "prod")
+	t.Setenv("LOG_LEVEL", "debug")
+	assert.Equal(t, types.LogConfig{Format: "json", Level: slog.LevelDebug}, config.LoadConfig().Log)
+
+	t.Setenv("LOG_FORMAT", " Text ")
+	t.Setenv("LOG_LEVEL", "verbose")
+	assert.Equal(t, types.LogConfig{Format: "text", Level: slog.LevelInfo}, config.LoadConfig().Log)
+}
+
+func TestLoadConfig_Mode(t *testing.T) {
+	t.Setenv("RUN_MODE", "")
+	assert.Equal(t, "all", config.LoadConfig().Mode)
+
+	t.Setenv("RUN_MODE", " Subscriber ")
+	assert.Equal(t, "subscriber", config.LoadConfig().Mode)
 }
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"time"
+)
+
+type handlersFile struct {
+	EnvironmentPrefixes map[string]string          `json:"environmentPrefixes"`
+	Subscriptions       []handlersFileSubscription `json:"subscriptions"`
+}
+
+type handlersFileSubscription struct {
+	ID       string                `json:"id"`
+	Handlers []handlersFileBinding `json:"handlers"`
+}
+
+type handlersFileBinding struct {
+	Name           string `json:"name"`
+	Policy         string `json:"policy"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+}
+
+// loadHandlersConfig devolve o erro do arquivo configurado em vez de cair no mapeamento padrão,
+// que consumiria assinaturas que o arquivo não previa.
+func loadHandlersConfig() (types.HandlersConfig, error) {
+	var cfg types.HandlersConfig
+	var err error
+	if path := os.Getenv("SUBSCRIPTION_HANDLERS_FILE"); path != "" {
+		cfg, err = LoadHandlersFile(path)
+		if err != nil {
+			err = fmt.Errorf("erro ao carregar SUBSCRIPTION_HANDLERS_FILE %s: %w", path, err)
+		}
+	}
+	cfg.DefaultTimeout = durationSecondsOrDefault("HANDLER_TIMEOUT_SECONDS", defaultHandlerTimeout)
+	return cfg, err
+}
+
+// LoadHandlersFile lê o mapeamento de assinaturas para handlers registrados.
+func LoadHandlersFile(path string) (types.HandlersConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return types.HandlersConfig{}, err
+	}
+	var file handlersFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return types.HandlersConfig{}, fmt.Errorf("arquivo de handlers inválido: %w", err)
+	}
+	cfg := types.HandlersConfig{EnvironmentPrefixes: file.EnvironmentPrefixes}
+	for _, sub := range file.Subscriptions {
+		if sub.ID == "" {
+			return types.HandlersConfig{}, fmt.Errorf("assinatura sem id no arquivo de handlers")
+		}
+		bindings := make([]types.HandlerBinding, 0, len(sub.Handlers))
+		for _, h := range sub.Handlers {
+			if h.Name == "" {
+				return types.HandlersConfig{}, fmt.Errorf("handler sem nome na assinatura %s", sub.ID)
+			}
+			switch enum.HandlerPolicyEnum(h.Policy) {
+			case "", enum.RequiredHandler, enum.BestEffortHandler:
+			default:
+				return types.HandlersConfig{}, fmt.Errorf("política %s inválida para o handler %s", h.Policy, h.Name)
+			}
+			if h.TimeoutSeconds < 0 {
+				return types.HandlersConfig{}, fmt.Errorf("tempo limite inválido para o handler %s", h.Name)
+			}
+			bindings = append(bindings, types.HandlerBinding{
+				Name:    h.Name,
+				Policy:  h.Policy,
+				Timeout: time.Duration(h.TimeoutSeconds) * time.Second,
+			})
+		}
+		cfg.Subscriptions = append(cfg.Subscriptions, types.SubscriptionHandlers{ID: sub.ID, Handlers: bindings})
+	}
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"path/filepath"
+	"queue/core/domain/types"
+	"queue/core/infra/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadHandlersFile(t *testing.T) {
+	path := writeTopology(t, `{
+		"environmentPrefixes": {"prod": "", "staging": "stg-"},
+		"subscriptions": [
+			{"id": "{prefix}notifications-sub", "handlers": [{"name": "notification"}, {"name": "audit", "policy": "best-effort", "timeoutSeconds": 5}]}
+		]
+	}`)
+	cfg, err := config.LoadHandlersFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, types.HandlersConfig{
+		EnvironmentPrefixes: map[string]string{"prod": "", "staging": "stg-"},
+		Subscriptions: []types.SubscriptionHandlers{{
+			ID: "{prefix}notifications-sub",
+			Handlers: []types.HandlerBinding{
+				{Name: "notification"},
+				{Name: "audit", Policy: "best-effort", Timeout: 5 * time.Second},
+			},
+		}},
+	}, cfg)
+}
+
+func TestLoadHandlersFile_Invalido(t *testing.T) {
+	cases := map[string]string{
+		"json":     `{`,
+		"sem id":   `{"subscriptions": [{"handlers": [{"name": "notification"}]}]}`,
+		"sem nome": `{"subscriptions": [{"id": "sub", "handlers": [{}]}]}`,
+		"política": `{"subscriptions": [{"id": "sub", "handlers": [{"name": "notification", "policy": "talvez"}]}]}`,
+		"timeout":  `{"subscriptions": [{"id": "sub", "handlers": [{"name": "notification", "timeoutSeconds": -1}]}]}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.LoadHandlersFile(writeTopology(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_Handlers(t *testing.T) {
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", "")
+	assert.Empty(t, config.LoadConfig().Handlers.Subscriptions)
+
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", writeTopology(t, `{"subscriptions": [{"id": "sub", "handlers": [{"name": "notification"}]}]}`))
+	handlers := config.LoadConfig().Handlers
+	assert.NoError(t, handlers.LoadErr)
+	assert.Equal(t, "sub", handlers.Subscriptions[0].ID)
+}
+
+func TestLoadConfig_HandlersArquivoInvalido(t *testing.T) {
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", filepath.Join(t.TempDir(), "inexistente.json"))
+	handlers := config.LoadConfig().Handlers
+	assert.ErrorContains(t, handlers.LoadErr, "SUBSCRIPTION_HANDLERS_FILE")
+	assert.Empty(t, handlers.Subscriptions)
+
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", writeTopology(t, `{"subscriptions": [{"handlers": []}]}`))
+	assert.ErrorContains(t, config.LoadConfig().Handlers.LoadErr, "assinatura sem id")
+}
+
+func TestLoadConfig_HandlerTimeout(t *testing.T) {
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", "")
+	t.Setenv("HANDLER_TIMEOUT_SECONDS", "")
+	assert.Equal(t, 30*time.Second, config.LoadConfig().Handlers.DefaultTimeout)
+
+	t.Setenv("HANDLER_TIMEOUT_SECONDS", "10")
+	t.Setenv("SUBSCRIPTION_HANDLERS_FILE", writeTopology(t, `{"subscriptions": [{"id": "sub", "handlers": [{"name": "notification"}]}]}`))
+	assert.Equal(t, 10*time.Second, config.LoadConfig().Handlers.DefaultTimeout)
+}
@@ -0,0 +1,153 @@
+package deadletter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Atributos adicionados às mensagens republicadas no dead-letter.
+const (
+	SubscriptionAttribute = "dlq-subscription"
+	TopicAttribute        = "dlq-topic"
+	ErrorAttribute        = "dlq-error"
+	AttemptsAttribute     = "dlq-attempts"
+	FailedAtAttribute     = "dlq-failed-at"
+	MessageIDAttribute    = "dlq-message-id"
+
+	attributePrefix = "dlq-"
+)
+
+const (
+	DefaultMaxDeliveryAttempts = 5
+	// maxAttributeLength respeita o limite de 1024 bytes por valor de atributo do Pub/Sub.
+	maxAttributeLength = 1024
+)
+
+// DeadLetterQueue implementa interfaces.IDeadLetterQueue. O número de entregas vem do contador
+// do broker quando disponível; caso contrário é contado localmente por assinatura e mensagem.
+type DeadLetterQueue struct {
+	client      interfaces.IPubSubClient
+	topic       string
+	maxAttempts int
+	mu          sync.Mutex
+	attempts    map[string]int
+}
+
+func NewDeadLetterQueue(client interfaces.IPubSubClient, cfg types.DeadLetterConfig) *DeadLetterQueue {
+	maxAttempts := cfg.MaxDeliveryAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxDeliveryAttempts
+	}
+	return &DeadLetterQueue{
+		client:      client,
+		topic:       cfg.Topic,
+		maxAttempts: maxAttempts,
+		attempts:    map[string]int{},
+	}
+}
+
+// Failed republica a mensagem no dead-letter quando a falha é permanente ou as entregas se esgotaram.
+func (q *DeadLetterQueue) Failed(ctx context.Context, failure types.DeliveryFailure) (bool, error) {
+	attempt := q.attempt(failure.SubscriptionID, failure.Message)
+	if !failure.Permanent && attempt < q.maxAttempts {
+		return false, nil
+	}
+	err := q.publish(ctx, failure, attempt)
+	metrics.DeadLetterObserved(failure.SubscriptionID, err)
+	if err != nil {
+		return false, err
+	}
+	q.forget(failure.SubscriptionID, failure.Message)
+	slog.WarnContext(ctx, "Mensagem enviada ao dead-letter",
+		"dead_letter_topic", q.topic, "delivery_attempts", attempt, "error", failure.Err)
+	return true, nil
+}
+
+func (q *DeadLetterQueue) Succeeded(subscriptionID string, msg *types.Message) {
+	if msg.DeliveryAttempt == nil {
+		q.forget(subscriptionID, msg)
+	}
+}
+
+func (q *DeadLetterQueue) attempt(subscriptionID string, msg *types.Message) int {
+	if msg.DeliveryAttempt != nil {
+		return *msg.DeliveryAttempt
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := attemptKey(subscriptionID, msg)
+	q.attempts[key]++
+	return q.attempts[key]
+}
+
+func (q *DeadLetterQueue) forget(subscriptionID string, msg *types.Message) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.attempts, attemptKey(subscriptionID, msg))
+}
+
+func (q *DeadLetterQueue) publish(ctx context.Context, failure types.DeliveryFailure, attempt int) error {
+	attributes := make(map[string]string, len(failure.Message.Attributes)+6)
+	for k, v := range failure.Message.Attributes {
+		attributes[k] = v
+	}
+	attributes[SubscriptionAttribute] = failure.SubscriptionID
+	attributes[TopicAttribute] = failure.Topic
+	attributes[ErrorAttribute] = truncate(failure.Err.Error(), maxAttributeLength)
+	attributes[AttemptsAttribute] = strconv.Itoa(attempt)
+	attributes[FailedAtAttribute] = time.Now().UTC().Format(time.RFC3339)
+	attributes[MessageIDAttribute] = failure.Message.ID
+
+	topic := q.client.Topic(q.topic)
+	defer topic.Stop()
+	if _, err := topic.Publish(ctx, &types.Message{Data: failure.Message.Data, Attributes: attributes}).Get(ctx); err != nil {
+		return fmt.Errorf("erro ao publicar no dead-letter %s: %w", q.topic, err)
+	}
+	return nil
+}
+
+// Parse extrai da mensagem os metadados gravados quando ela foi enviada ao dead-letter.
+func Parse(msg *types.Message) types.DeadLetterMessage {
+	attempts, _ := strconv.Atoi(msg.Attributes[AttemptsAttribute])
+	failedAt, _ := time.Parse(time.RFC3339, msg.Attributes[FailedAtAttribute])
+	return types.DeadLetterMessage{
+		Message:        msg,
+		SubscriptionID: msg.Attributes[SubscriptionAttribute],
+		Topic:          msg.Attributes[TopicAttribute],
+		Error:          msg.Attributes[ErrorAttribute],
+		Attempts:       attempts,
+		FailedAt:       failedAt,
+		OriginalID:     msg.Attributes[MessageIDAttribute],
+	}
+}
+
+// OriginalAttributes devolve os atributos da mensagem sem os metadados do dead-letter, para o replay.
+func OriginalAttributes(attributes map[string]string) map[string]string {
+	original := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		if !strings.HasPrefix(k, attributePrefix) {
+			original[k] = v
+		}
+	}
+	return original
+}
+
+func attemptKey(subscriptionID string, msg *types.Message) string {
+	return subscriptionID + "/" + msg.ID
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	// O corte pode cair no meio de um caractere multibyte, que é descartado.
+	return strings.ToValidUTF8(value[:limit], "")
+}
@@ -0,0 +1,134 @@
+package deadletter_test
+
+import (
+	"context"
+	"errors"
+	"queue/core/domain/types"
+	"queue/core/infra/dead_letter"
+	"queue/core/infra/memory_broker"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBroker(t *testing.T) *memorybroker.MemoryBroker {
+	t.Helper()
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("dlq-sub", "dlq"))
+	return broker
+}
+
+// receiveOne lê a primeira mensagem da assinatura do dead-letter, ou nil se nada chegar.
+func receiveOne(t *testing.T, broker *memorybroker.MemoryBroker) *types.Message {
+	t.Helper()
+	sub, _ := broker.Subscriptions(context.Background()).Next()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var received *types.Message
+	_ = sub.Receive(ctx, func(_ context.Context, msg *types.Message) {
+		received = msg
+		msg.Ack()
+		cancel()
+	})
+	return received
+}
+
+func failure(msg *types.Message, permanent bool) types.DeliveryFailure {
+	return types.DeliveryFailure{
+		SubscriptionID: "notifications-sub",
+		Topic:          "notifications",
+		Message:        msg,
+		Err:            errors.New("falha simulada"),
+		Permanent:      permanent,
+	}
+}
+
+func TestDeadLetterQueue_ContagemLocal(t *testing.T) {
+	broker := newBroker(t)
+	queue := deadletter.NewDeadLetterQueue(broker, types.DeadLetterConfig{Topic: "dlq", MaxDeliveryAttempts: 3})
+	msg := &types.Message{ID: "42", Data: []byte(`{not-json}`), Attributes: map[string]string{"origem": "crm"}}
+
+	for i := 0; i < 2; i++ {
+		sent, err := queue.Failed(context.Background(), failure(msg, false))
+		assert.NoError(t, err)
+		assert.False(t, sent)
+	}
+	sent, err := queue.Failed(context.Background(), failure(msg, false))
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	dead := receiveOne(t, broker)
+	assert.NotNil(t, dead)
+	assert.Equal(t, `{not-json}`, string(dead.Data))
+	assert.Equal(t, "crm", dead.Attributes["origem"])
+	assert.Equal(t, "notifications-sub", dead.Attributes[deadletter.SubscriptionAttribute])
+	assert.Equal(t, "notifications", dead.Attributes[deadletter.TopicAttribute])
+	assert.Equal(t, "falha simulada", dead.Attributes[deadletter.ErrorAttribute])
+	assert.Equal(t, "3", dead.Attributes[deadletter.AttemptsAttribute])
+	assert.Equal(t, "42", dead.Attributes[deadletter.MessageIDAttribute])
+	assert.NotEmpty(t, dead.Attributes[deadletter.FailedAtAttribute])
+}
+
+func TestDeadLetterQueue_SucessoZeraContagem(t *testing.T) {
+	queue := deadletter.NewDeadLetterQueue(newBroker(t), types.DeadLetterConfig{Topic: "dlq", MaxDeliveryAttempts: 2})
+	msg := &types.Message{ID: "1"}
+
+	sent, _ := queue.Failed(context.Background(), failure(msg, false))
+	assert.False(t, sent)
+	queue.Succeeded("notifications-sub", msg)
+	sent, _ = queue.Failed(context.Background(), failure(msg, false))
+	assert.False(t, sent)
+}
+
+func TestDeadLetterQueue_ContadorDoBroker(t *testing.T) {
+	queue := deadletter.NewDeadLetterQueue(newBroker(t), types.DeadLetterConfig{Topic: "dlq"})
+	attempt := deadletter.DefaultMaxDeliveryAttempts
+	sent, err := queue.Failed(context.Background(), failure(&types.Message{ID: "1", DeliveryAttempt: &attempt}, false))
+	assert.NoError(t, err)
+	assert.True(t, sent)
+}
+
+func TestDeadLetterQueue_ErroPermanenteVaiDireto(t *testing.T) {
+	broker := newBroker(t)
+	queue := deadletter.NewDeadLetterQueue(broker, types.DeadLetterConfig{Topic: "dlq", MaxDeliveryAttempts: 10})
+	msg := &types.Message{ID: "1", Data: []byte("x")}
+	f := failure(msg, true)
+	f.Err = errors.New(strings.Repeat("é", 600))
+
+	sent, err := queue.Failed(context.Background(), f)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+	dead := receiveOne(t, broker)
+	assert.Equal(t, "1", dead.Attributes[deadletter.AttemptsAttribute])
+	assert.LessOrEqual(t, len(dead.Attributes[deadletter.ErrorAttribute]), 1024)
+}
+
+func TestDeadLetterQueue_FalhaAoPublicar(t *testing.T) {
+	queue := deadletter.NewDeadLetterQueue(newBroker(t), types.DeadLetterConfig{Topic: "nao-existe"})
+	sent, err := queue.Failed(context.Background(), failure(&types.Message{ID: "1"}, true))
+	assert.Error(t, err)
+	assert.False(t, sent)
+}
+
+func TestParseEOriginalAttributes(t *testing.T) {
+	msg := &types.Message{ID: "dlq-1", Attributes: map[string]string{
+		"origem":                         "crm",
+		deadletter.SubscriptionAttribute: "notifications-sub",
+		deadletter.TopicAttribute:        "notifications",
+		deadletter.ErrorAttribute:        "falha",
+		deadletter.AttemptsAttribute:     "5",
+		deadletter.FailedAtAttribute:     "2026-01-02T03:04:05Z",
+		deadletter.MessageIDAttribute:    "42",
+	}}
+
+	dead := deadletter.Parse(msg)
+	assert.Equal(t, "notifications-sub", dead.SubscriptionID)
+	assert.Equal(t, "notifications", dead.Topic)
+	assert.Equal(t, "falha", dead.Error)
+	assert.Equal(t, 5, dead.Attempts)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), dead.FailedAt)
+	assert.Equal(t, "42", dead.OriginalID)
+	assert.Equal(t, map[string]string{"origem": "crm"}, deadletter.OriginalAttributes(msg.Attributes))
+}
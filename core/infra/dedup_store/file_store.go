@@ -0,0 +1,143 @@
+package dedupstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// minCompactLines evita reescrever arquivos pequenos a cada gravação.
+const minCompactLines = 1024
+
+type fileRecord struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FileStore acrescenta cada chave como uma linha JSON no arquivo, para que marcar um evento não
+// exija regravar todas as chaves. O arquivo é compactado, descartando as expiradas, quando passa a
+// ter mais que o dobro de linhas das chaves válidas.
+type FileStore struct {
+	mu    sync.Mutex
+	path  string
+	keys  map[string]time.Time
+	lines int
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{path: path, keys: map[string]time.Time{}}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chaves de deduplicação: %w", err)
+	}
+	lines := bytes.Split(raw, []byte("\n"))
+	now := time.Now()
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var record fileRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			// A última linha pode ter ficado incompleta se o processo parou durante a gravação; o arquivo
+			// é regravado sem ela para que a próxima linha acrescentada não fique colada a ela
+			if i == len(lines)-1 {
+				if err := store.compact(); err != nil {
+					return nil, err
+				}
+				break
+			}
+			return nil, fmt.Errorf("arquivo de chaves de deduplicação inválido na linha %d: %w", i+1, err)
+		}
+		store.lines++
+		if now.Before(record.ExpiresAt) {
+			store.keys[record.Key] = record.ExpiresAt
+		} else {
+			delete(store.keys, record.Key)
+		}
+	}
+	return store, nil
+}
+
+func (s *FileStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt, ok := s.keys[key]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+func (s *FileStore) Mark(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := fileRecord{Key: key, ExpiresAt: time.Now().Add(ttl)}
+	if err := s.append(record); err != nil {
+		return err
+	}
+	s.keys[key] = record.ExpiresAt
+	s.lines++
+	if s.lines > max(2*len(s.keys), minCompactLines) {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *FileStore) append(record fileRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de deduplicação: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar chave de deduplicação: %w", err)
+	}
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao gravar chave de deduplicação: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar chave de deduplicação: %w", err)
+	}
+	return nil
+}
+
+// compact regrava o arquivo apenas com as chaves válidas.
+func (s *FileStore) compact() error {
+	now := time.Now()
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for key, expiresAt := range s.keys {
+		if !now.Before(expiresAt) {
+			delete(s.keys, key)
+			continue
+		}
+		raw, err := json.Marshal(fileRecord{Key: key, ExpiresAt: expiresAt})
+		if err != nil {
+			return err
+		}
+		w.Write(raw)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("erro ao compactar chaves de deduplicação: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("erro ao compactar chaves de deduplicação: %w", err)
+	}
+	s.lines = len(s.keys)
+	return nil
+}
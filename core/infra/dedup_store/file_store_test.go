@@ -0,0 +1,80 @@
+package dedupstore_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"queue/core/infra/dedup_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore_PersisteEntreInstancias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "dedup.jsonl")
+	store, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Mark("a", time.Hour))
+	assert.NoError(t, store.Mark("b", -time.Second))
+
+	reopened, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	seen, err := reopened.Seen("a")
+	assert.NoError(t, err)
+	assert.True(t, seen)
+	seen, _ = reopened.Seen("b")
+	assert.False(t, seen)
+}
+
+func TestFileStore_IgnoraUltimaLinhaIncompleta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedup.jsonl")
+	store, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Mark("a", time.Hour))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = file.WriteString(`{"key":"b","expi`)
+	assert.NoError(t, file.Close())
+
+	reopened, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	seen, _ := reopened.Seen("a")
+	assert.True(t, seen)
+	assert.NoError(t, reopened.Mark("c", time.Hour))
+
+	again, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	for _, key := range []string{"a", "c"} {
+		seen, _ := again.Seen(key)
+		assert.True(t, seen, key)
+	}
+}
+
+func TestFileStore_ArquivoInvalido(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedup.jsonl")
+	assert.NoError(t, os.WriteFile(path, []byte("{\n{\"key\":\"a\"}\n"), 0o600))
+	_, err := dedupstore.NewFileStore(path)
+	assert.Error(t, err)
+}
+
+func TestFileStore_CompactaChavesRepetidas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedup.jsonl")
+	store, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	for i := 0; i < 3000; i++ {
+		assert.NoError(t, store.Mark(fmt.Sprintf("k%d", i%10), time.Hour))
+	}
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Less(t, bytes.Count(raw, []byte("\n")), 1100)
+
+	reopened, err := dedupstore.NewFileStore(path)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		seen, _ := reopened.Seen(fmt.Sprintf("k%d", i))
+		assert.True(t, seen)
+	}
+}
@@ -0,0 +1,77 @@
+package dedupstore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries limita o MemoryStore quando nenhuma capacidade é informada.
+const DefaultMaxEntries = 100000
+
+type entry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// MemoryStore é um LRU com TTL: ao atingir a capacidade, as chaves usadas há mais tempo são descartadas.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (s *MemoryStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+	if !time.Now().Before(el.Value.(*entry).expiresAt) {
+		s.remove(el)
+		return false, nil
+	}
+	s.order.MoveToFront(el)
+	return true, nil
+}
+
+func (s *MemoryStore) Mark(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*entry).expiresAt = expiresAt
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(&entry{key: key, expiresAt: expiresAt})
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Len retorna a quantidade de chaves guardadas, incluindo as expiradas ainda não descartadas.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(el *list.Element) {
+	s.order.Remove(el)
+	delete(s.entries, el.Value.(*entry).key)
+}
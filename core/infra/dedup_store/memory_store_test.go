@@ -0,0 +1,49 @@
+package dedupstore_test
+
+import (
+	"queue/core/infra/dedup_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_SeenMark(t *testing.T) {
+	store := dedupstore.NewMemoryStore(0)
+	seen, err := store.Seen("k")
+	assert.NoError(t, err)
+	assert.False(t, seen)
+
+	assert.NoError(t, store.Mark("k", time.Hour))
+	seen, err = store.Seen("k")
+	assert.NoError(t, err)
+	assert.True(t, seen)
+}
+
+func TestMemoryStore_IgnoraExpiradas(t *testing.T) {
+	store := dedupstore.NewMemoryStore(0)
+	assert.NoError(t, store.Mark("k", -time.Second))
+
+	seen, err := store.Seen("k")
+	assert.NoError(t, err)
+	assert.False(t, seen)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestMemoryStore_DescartaMenosUsadas(t *testing.T) {
+	store := dedupstore.NewMemoryStore(2)
+	assert.NoError(t, store.Mark("a", time.Hour))
+	assert.NoError(t, store.Mark("b", time.Hour))
+	// Consultar "a" a torna a mais recente, então "b" é a descartada
+	seen, _ := store.Seen("a")
+	assert.True(t, seen)
+	assert.NoError(t, store.Mark("c", time.Hour))
+
+	assert.Equal(t, 2, store.Len())
+	seen, _ = store.Seen("b")
+	assert.False(t, seen)
+	seen, _ = store.Seen("a")
+	assert.True(t, seen)
+	seen, _ = store.Seen("c")
+	assert.True(t, seen)
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"queue/core/application/subscription/dto"
 	"queue/core/domain/types"
+	"queue/core/infra/metrics"
 	"queue/core/infra/utils/functions"
+	"time"
 )
 
 type HttpService struct {
@@ -24,14 +26,17 @@ func (s *HttpService) WithClient(client functions.IHttpClient) *HttpService {
 	return s
 }
 
-func (s *HttpService) SendNotification(data *subscriptiondto.CreateNotificationDto, cfg *types.Config) error {
+// SendNotification envia a notificação com o contexto informado, que cancela a requisição em andamento.
+func (s *HttpService) SendNotification(ctx context.Context, data *subscriptiondto.CreateNotificationDto, cfg *types.Config) error {
 	url := cfg.URLs.Notification + "/notification"
 	headers := functions.CreateBasicAuthHeader(
 		cfg.Auth.Username,
 		cfg.Auth.Password,
 	)
-	config := BuildRequestConfig("POST", url, data, nil, nil, headers, true)
+	config := BuildRequestConfig("POST", url, data, nil, ctx, headers, true)
+	start := time.Now()
 	_, err := functions.Send[any](s.client, config)
+	metrics.NotificationObserved(err, time.Since(start))
 	return err
 }
 
@@ -50,7 +50,7 @@ func TestSendNotification_Success(t *testing.T) {
 		Payload:   map[string]string{"k": "v"},
 	}
 
-	err := service.SendNotification(nData, cfg)
+	err := service.SendNotification(context.Background(), nData, cfg)
 	if err != nil {
 		t.Errorf("esperava nil, recebeu erro: %v", err)
 	}
@@ -91,13 +91,31 @@ func TestSendNotification_ErroNetwork(t *testing.T) {
 		Payload:   map[string]string{"k": "v"},
 	}
 
-	err := service.SendNotification(nData, cfg)
+	err := service.SendNotification(context.Background(), nData, cfg)
 	if err == nil {
 		t.Error("esperava erro de status HTTP, recebeu nil")
 	}
 
 }
 
+func TestSendNotification_UsaContexto(t *testing.T) {
+	mockClient := &MockHttpClient{
+		resp: &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+		},
+	}
+	service := httpservice.NewHttpService().WithClient(mockClient)
+	cfg := &types.Config{URLs: types.URLsConfig{Notification: "http://localhost:1234"}}
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "valor")
+
+	_ = service.SendNotification(ctx, &subscriptiondto.CreateNotificationDto{UserID: 1, Channel: "EMAIL", Recipient: "a@b.com"}, cfg)
+	if mockClient.lastRequest == nil || mockClient.lastRequest.Context().Value(ctxKey{}) != "valor" {
+		t.Error("esperava que a requisição usasse o contexto informado")
+	}
+}
+
 func TestBuildRequestConfig(t *testing.T) {
 	headers := map[string]string{"Authorization": "Basic x"}
 	params := map[string]string{"p": "v"}
@@ -0,0 +1,163 @@
+package idempotencystore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"queue/core/domain/types"
+	"sync"
+	"time"
+)
+
+// minCompactLines evita reescrever arquivos pequenos a cada gravação.
+const minCompactLines = 1024
+
+// FileStore acrescenta cada chave como uma linha JSON no arquivo, como o store de deduplicação, para
+// que uma publicação não exija regravar todas as chaves. O arquivo é compactado, descartando as
+// expiradas, quando passa a ter mais que o dobro de linhas das chaves guardadas.
+type FileStore struct {
+	mu        sync.Mutex
+	path      string
+	records   map[string]types.IdempotencyRecord
+	lines     int
+	lastPurge time.Time
+}
+
+// NewFileStore também aceita o formato anterior, um único array JSON, convertendo-o para linhas.
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{path: path, records: map[string]types.IdempotencyRecord{}, lastPurge: time.Now()}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chaves de idempotência: %w", err)
+	}
+	now := time.Now()
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []types.IdempotencyRecord
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("arquivo de chaves de idempotência inválido: %w", err)
+		}
+		for _, record := range list {
+			if !record.Expired(now) {
+				store.records[record.Key] = record
+			}
+		}
+		if err := store.compact(); err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	lines := bytes.Split(raw, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var record types.IdempotencyRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			// A última linha pode ter ficado incompleta se o processo parou durante a gravação; o arquivo
+			// é regravado sem ela para que a próxima linha acrescentada não fique colada a ela
+			if i == len(lines)-1 {
+				if err := store.compact(); err != nil {
+					return nil, err
+				}
+				break
+			}
+			return nil, fmt.Errorf("arquivo de chaves de idempotência inválido na linha %d: %w", i+1, err)
+		}
+		store.lines++
+		if record.Expired(now) {
+			delete(store.records, record.Key)
+		} else {
+			store.records[record.Key] = record
+		}
+	}
+	return store, nil
+}
+
+func (s *FileStore) Get(key string) (*types.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok || record.Expired(time.Now()) {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *FileStore) Save(record types.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(record); err != nil {
+		return err
+	}
+	s.records[record.Key] = record
+	s.lines++
+	if now := time.Now(); now.Sub(s.lastPurge) >= purgeInterval {
+		purgeExpired(s.records, now)
+		s.lastPurge = now
+	}
+	if s.lines > max(2*len(s.records), minCompactLines) {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *FileStore) append(record types.IdempotencyRecord) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de idempotência: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar chave de idempotência: %w", err)
+	}
+	if _, err := file.Write(append(raw, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao gravar chave de idempotência: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar chave de idempotência: %w", err)
+	}
+	return nil
+}
+
+// compact regrava o arquivo apenas com as chaves válidas.
+func (s *FileStore) compact() error {
+	now := time.Now()
+	purgeExpired(s.records, now)
+	s.lastPurge = now
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for _, record := range s.records {
+		raw, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		w.Write(raw)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de idempotência: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("erro ao compactar chaves de idempotência: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("erro ao compactar chaves de idempotência: %w", err)
+	}
+	s.lines = len(s.records)
+	return nil
+}
@@ -0,0 +1,120 @@
+package idempotencystore_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"queue/core/domain/types"
+	"queue/core/infra/idempotency_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore_PersisteEntreInstancias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "idempotency.json")
+	now := time.Now().UTC()
+
+	store, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "a", Topic: "t", MessageID: "1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "b", Topic: "t", ScheduleID: "s", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	reopened, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	record, err := reopened.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", record.MessageID)
+	record, err = reopened.Get("b")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", record.ScheduleID)
+}
+
+func TestFileStore_DescartaExpiradasAoAbrir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	now := time.Now()
+	store, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "a", ExpiresAt: now.Add(50 * time.Millisecond)}))
+	time.Sleep(60 * time.Millisecond)
+
+	reopened, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	record, err := reopened.Get("a")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestFileStore_ArquivoInvalido(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{\n{\"key\":\"a\"}\n"), 0o600))
+	_, err := idempotencystore.NewFileStore(path)
+	assert.Error(t, err)
+}
+
+func TestFileStore_IgnoraUltimaLinhaIncompleta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	now := time.Now()
+	store, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "a", MessageID: "1", ExpiresAt: now.Add(time.Hour)}))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = file.WriteString(`{"key":"b","expi`)
+	assert.NoError(t, file.Close())
+
+	reopened, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, reopened.Save(types.IdempotencyRecord{Key: "c", MessageID: "3", ExpiresAt: now.Add(time.Hour)}))
+
+	again, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	for key, id := range map[string]string{"a": "1", "c": "3"} {
+		record, err := again.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, id, record.MessageID)
+	}
+}
+
+func TestFileStore_CompactaChavesRepetidas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	now := time.Now()
+	store, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	for i := 0; i < 3000; i++ {
+		assert.NoError(t, store.Save(types.IdempotencyRecord{Key: fmt.Sprintf("k%d", i%10), MessageID: fmt.Sprint(i), ExpiresAt: now.Add(time.Hour)}))
+	}
+
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Less(t, bytes.Count(raw, []byte("\n")), 1100)
+
+	reopened, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		record, err := reopened.Get(fmt.Sprintf("k%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprint(2990+i), record.MessageID)
+	}
+}
+
+func TestFileStore_ConverteFormatoAnterior(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano)
+	legacy := fmt.Sprintf(`[{"key":"a","messageId":"1","expiresAt":%q}]`, expiresAt)
+	assert.NoError(t, os.WriteFile(path, []byte(legacy), 0o600))
+
+	store, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "b", MessageID: "2", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	reopened, err := idempotencystore.NewFileStore(path)
+	assert.NoError(t, err)
+	for key, id := range map[string]string{"a": "1", "b": "2"} {
+		record, err := reopened.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, id, record.MessageID)
+	}
+}
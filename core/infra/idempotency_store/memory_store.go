@@ -0,0 +1,54 @@
+package idempotencystore
+
+import (
+	"queue/core/domain/types"
+	"sync"
+	"time"
+)
+
+// purgeInterval espaça as varreduras das chaves expiradas, que percorrem todas as chaves guardadas.
+// Entre elas, as expiradas continuam ocupando memória, mas Get já não as devolve.
+const purgeInterval = time.Minute
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]types.IdempotencyRecord
+	lastPurge time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]types.IdempotencyRecord{}, lastPurge: time.Now()}
+}
+
+func (s *MemoryStore) Get(key string) (*types.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	if record.Expired(time.Now()) {
+		delete(s.records, key)
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *MemoryStore) Save(record types.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Key] = record
+	if now := time.Now(); now.Sub(s.lastPurge) >= purgeInterval {
+		purgeExpired(s.records, now)
+		s.lastPurge = now
+	}
+	return nil
+}
+
+func purgeExpired(records map[string]types.IdempotencyRecord, now time.Time) {
+	for key, record := range records {
+		if record.Expired(now) {
+			delete(records, key)
+		}
+	}
+}
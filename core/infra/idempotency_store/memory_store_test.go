@@ -0,0 +1,34 @@
+package idempotencystore_test
+
+import (
+	"queue/core/domain/types"
+	"queue/core/infra/idempotency_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_SaveGet(t *testing.T) {
+	store := idempotencystore.NewMemoryStore()
+	now := time.Now()
+
+	record, err := store.Get("k")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "k", Topic: "t", MessageID: "1", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	record, err = store.Get("k")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", record.MessageID)
+}
+
+func TestMemoryStore_IgnoraExpiradas(t *testing.T) {
+	store := idempotencystore.NewMemoryStore()
+	now := time.Now()
+	assert.NoError(t, store.Save(types.IdempotencyRecord{Key: "k", MessageID: "1", ExpiresAt: now.Add(-time.Second)}))
+
+	record, err := store.Get("k")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestIDHeader é o cabeçalho HTTP lido e devolvido pela API com o id da requisição.
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDAttribute é o atributo da mensagem que leva o id da requisição de publicação até o subscriber.
+	RequestIDAttribute = "x-request-id"
+)
+
+type contextKey int
+
+const (
+	attrsKey contextKey = iota
+	requestIDKey
+)
+
+// Setup instala como logger padrão do slog, e do pacote log, um logger no formato configurado.
+func Setup(cfg types.LogConfig) {
+	slog.SetDefault(New(os.Stderr, cfg))
+}
+
+// New cria um logger que grava em w e acrescenta a cada linha os atributos guardados no contexto
+// com With e os ids do trace ativo.
+func New(w io.Writer, cfg types.LogConfig) *slog.Logger {
+	options := &slog.HandlerOptions{Level: cfg.Level}
+	var handler slog.Handler
+	if enum.LogFormatEnum(cfg.Format) == enum.JSONLogFormat {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// Fatal registra o erro e encerra o processo, como log.Fatalf.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// With devolve ctx com atributos que passam a aparecer em todo log feito com esse contexto,
+// como slog.InfoContext(ctx, ...). Os argumentos seguem o formato chave-valor do slog.
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.NewRecord(time.Time{}, 0, "", 0)
+	record.Add(args...)
+	current, _ := ctx.Value(attrsKey).([]slog.Attr)
+	attrs := make([]slog.Attr, len(current), len(current)+record.NumAttrs())
+	copy(attrs, current)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey, attrs)
+}
+
+// WithRequestID guarda o id da requisição para os logs e para as mensagens publicadas com ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return With(context.WithValue(ctx, requestIDKey, id), "request_id", id)
+}
+
+// RequestID devolve o id guardado com WithRequestID ou vazio.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithMessage identifica nos logs a mensagem recebida: assinatura, id, tentativa de entrega, quando o
+// broker a informa, e o id da requisição que a publicou.
+func WithMessage(ctx context.Context, subscriptionID string, msg *types.Message) context.Context {
+	args := []any{"subscription", subscriptionID, "message_id", msg.ID}
+	if msg.DeliveryAttempt != nil {
+		args = append(args, "attempt", *msg.DeliveryAttempt)
+	}
+	return WithAttributesRequestID(With(ctx, args...), msg.Attributes)
+}
+
+// WithAttributesRequestID guarda o id da requisição gravado nos atributos de uma mensagem, quando houver.
+func WithAttributesRequestID(ctx context.Context, attributes map[string]string) context.Context {
+	if id := attributes[RequestIDAttribute]; id != "" {
+		return WithRequestID(ctx, id)
+	}
+	return ctx
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,93 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"queue/core/domain/types"
+	"queue/core/infra/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), buf.String())
+	return line
+}
+
+func TestNew_JSONComAtributosDoContexto(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, types.LogConfig{Format: "json"})
+	ctx := logging.With(logging.WithRequestID(context.Background(), "req-1"), "subscription", "sub")
+
+	logger.InfoContext(ctx, "processada", "status", 200)
+	line := decode(t, &buf)
+	assert.Equal(t, "processada", line["msg"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "sub", line["subscription"])
+	assert.Equal(t, 200.0, line["status"])
+	assert.NotContains(t, line, "trace_id")
+}
+
+func TestNew_TextoENivel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, types.LogConfig{Format: "text", Level: slog.LevelWarn})
+	logger.Info("ignorada")
+	assert.Empty(t, buf.String())
+
+	logger.With("worker", "agendador").WarnContext(logging.With(context.Background(), "topic", "t"), "atrasada")
+	assert.Contains(t, buf.String(), "msg=atrasada worker=agendador topic=t")
+}
+
+func TestNew_IdsDoTrace(t *testing.T) {
+	var buf bytes.Buffer
+	span := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+
+	logging.New(&buf, types.LogConfig{Format: "json"}).InfoContext(ctx, "com trace")
+	line := decode(t, &buf)
+	assert.Equal(t, span.TraceID().String(), line["trace_id"])
+	assert.Equal(t, span.SpanID().String(), line["span_id"])
+}
+
+func TestWith_NaoAlteraOContextoPai(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, types.LogConfig{Format: "json"})
+	parent := logging.With(context.Background(), "subscription", "sub")
+	_ = logging.With(parent, "handler", "audit")
+
+	logger.InfoContext(parent, "pai")
+	assert.NotContains(t, decode(t, &buf), "handler")
+}
+
+func TestRequestID(t *testing.T) {
+	assert.Empty(t, logging.RequestID(context.Background()))
+	assert.Equal(t, "req-1", logging.RequestID(logging.WithRequestID(context.Background(), "req-1")))
+}
+
+func TestWithMessage(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, types.LogConfig{Format: "json"})
+	attempt := 3
+	msg := &types.Message{ID: "m-1", DeliveryAttempt: &attempt, Attributes: map[string]string{logging.RequestIDAttribute: "req-1"}}
+
+	ctx := logging.WithMessage(context.Background(), "sub", msg)
+	assert.Equal(t, "req-1", logging.RequestID(ctx))
+	logger.InfoContext(ctx, "recebida")
+	line := decode(t, &buf)
+	assert.Equal(t, "sub", line["subscription"])
+	assert.Equal(t, "m-1", line["message_id"])
+	assert.Equal(t, 3.0, line["attempt"])
+	assert.Equal(t, "req-1", line["request_id"])
+
+	buf.Reset()
+	logger.InfoContext(logging.WithMessage(context.Background(), "sub", &types.Message{ID: "m-2"}), "sem tentativa")
+	line = decode(t, &buf)
+	assert.NotContains(t, line, "attempt")
+	assert.NotContains(t, line, "request_id")
+}
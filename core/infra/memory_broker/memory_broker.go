@@ -0,0 +1,384 @@
+package memorybroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/api/iterator"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAckDeadline    = 10 * time.Second
+	defaultMaxOutstanding = 10
+	deadlineCheckInterval = 100 * time.Millisecond
+)
+
+var ErrBrokerClosed = errors.New("broker em memória encerrado")
+
+// MemoryBroker implementa interfaces.IPubSubClient inteiramente em memória,
+// para execução local e testes sem depender do Google Pub/Sub.
+type MemoryBroker struct {
+	mu            sync.RWMutex
+	topics        map[string]*memoryTopic
+	subscriptions []*memorySubscription
+	ackDeadline   time.Duration
+	nextID        int64
+	closed        bool
+	done          chan struct{}
+}
+
+func NewMemoryBroker(ackDeadline time.Duration) *MemoryBroker {
+	if ackDeadline <= 0 {
+		ackDeadline = defaultAckDeadline
+	}
+	return &MemoryBroker{
+		topics:      map[string]*memoryTopic{},
+		ackDeadline: ackDeadline,
+		done:        make(chan struct{}),
+	}
+}
+
+// NewMemoryBrokerFromConfig cria o broker já com os tópicos e assinaturas declarados na configuração.
+func NewMemoryBrokerFromConfig(cfg types.BrokerConfig) (*MemoryBroker, error) {
+	broker := NewMemoryBroker(cfg.AckDeadline)
+	if err := broker.Provision(context.Background(), cfg); err != nil {
+		return nil, err
+	}
+	return broker, nil
+}
+
+// Provision cria os tópicos e assinaturas declarados, ignorando os que já existem.
+func (b *MemoryBroker) Provision(ctx context.Context, cfg types.BrokerConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range cfg.Topics {
+		b.createTopic(id)
+	}
+	for _, sub := range cfg.Subscriptions {
+		if existing := b.findSubscription(sub.ID); existing != nil {
+			if existing.topic != sub.Topic {
+				return fmt.Errorf("assinatura %s já existe no tópico %s", sub.ID, existing.topic)
+			}
+			continue
+		}
+		b.createSubscription(sub.ID, sub.Topic, sub.AckDeadline)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) CreateTopic(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.createTopic(id)
+}
+
+func (b *MemoryBroker) createTopic(id string) *memoryTopic {
+	if t, ok := b.topics[id]; ok {
+		return t
+	}
+	t := &memoryTopic{id: id, broker: b}
+	b.topics[id] = t
+	return t
+}
+
+// CreateSubscription cria a assinatura, criando também o tópico caso ainda não exista.
+func (b *MemoryBroker) CreateSubscription(id string, topicID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.findSubscription(id) != nil {
+		return fmt.Errorf("assinatura %s já existe", id)
+	}
+	b.createSubscription(id, topicID, 0)
+	return nil
+}
+
+func (b *MemoryBroker) createSubscription(id string, topicID string, ackDeadline time.Duration) {
+	if ackDeadline <= 0 {
+		ackDeadline = b.ackDeadline
+	}
+	topic := b.createTopic(topicID)
+	sub := newMemorySubscription(id, topicID, ackDeadline, b)
+	topic.subscriptions = append(topic.subscriptions, sub)
+	b.subscriptions = append(b.subscriptions, sub)
+}
+
+func (b *MemoryBroker) findSubscription(id string) *memorySubscription {
+	for _, s := range b.subscriptions {
+		if s.id == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscriptions(ctx context.Context) interfaces.ISubscriptionIterator {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	subs := make([]interfaces.ISubscription, len(b.subscriptions))
+	for i, s := range b.subscriptions {
+		subs[i] = s
+	}
+	return &subscriptionIterator{subs: subs}
+}
+
+func (b *MemoryBroker) Topic(id string) interfaces.ITopic {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if t, ok := b.topics[id]; ok {
+		return t
+	}
+	return &memoryTopic{id: id, broker: b}
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.done)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) publish(topicID string, msg *types.Message) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return "", ErrBrokerClosed
+	}
+	topic, ok := b.topics[topicID]
+	if !ok {
+		return "", fmt.Errorf("tópico %s não encontrado", topicID)
+	}
+	b.nextID++
+	id := strconv.FormatInt(b.nextID, 10)
+	publishTime := time.Now()
+	for _, sub := range topic.subscriptions {
+		sub.enqueue(types.Message{
+			ID:          id,
+			Data:        append([]byte(nil), msg.Data...),
+			Attributes:  copyAttributes(msg.Attributes),
+			OrderingKey: msg.OrderingKey,
+			PublishTime: publishTime,
+		})
+	}
+	return id, nil
+}
+
+/* ------------------------------------- ITERATORS--------------------------------------------------------------*/
+
+type subscriptionIterator struct {
+	index int
+	subs  []interfaces.ISubscription
+}
+
+func (it *subscriptionIterator) Next() (interfaces.ISubscription, error) {
+	if it.index < len(it.subs) {
+		sub := it.subs[it.index]
+		it.index++
+		return sub, nil
+	}
+	return nil, iterator.Done
+}
+
+/* ------------------------------------- TOPIC ------------------------------------------------------------------*/
+
+type memoryTopic struct {
+	id            string
+	broker        *MemoryBroker
+	subscriptions []*memorySubscription
+}
+
+func (t *memoryTopic) ID() string { return t.id }
+
+func (t *memoryTopic) Stop() {}
+
+func (t *memoryTopic) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
+	id, err := t.broker.publish(t.id, msg)
+	return &publishResult{id: id, err: err}
+}
+
+type publishResult struct {
+	id  string
+	err error
+}
+
+func (r *publishResult) Get(ctx context.Context) (string, error) {
+	return r.id, r.err
+}
+
+/*--------------------------------------- RECEIVE --------------------------------------------------------------*/
+
+type pendingMessage struct {
+	msg      types.Message
+	attempts int
+	delivery int
+	inFlight bool
+	deadline time.Time
+}
+
+type memorySubscription struct {
+	id          string
+	topic       string
+	ackDeadline time.Duration
+	broker      *MemoryBroker
+	mu          sync.Mutex
+	pending     []*pendingMessage
+	notify      chan struct{}
+	// maxOutstanding limita os callbacks simultâneos; zero usa defaultMaxOutstanding.
+	maxOutstanding int
+}
+
+func newMemorySubscription(id string, topic string, ackDeadline time.Duration, broker *MemoryBroker) *memorySubscription {
+	return &memorySubscription{
+		id:          id,
+		topic:       topic,
+		ackDeadline: ackDeadline,
+		broker:      broker,
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+func (s *memorySubscription) ID() string { return s.id }
+
+// SetReceiveSettings aplica o limite de mensagens em andamento nas próximas chamadas de Receive.
+func (s *memorySubscription) SetReceiveSettings(settings types.ReceiveSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxOutstanding = settings.MaxOutstandingMessages
+}
+
+// Receive entrega as mensagens pendentes até o contexto ser cancelado ou o broker
+// ser encerrado, aguardando os callbacks em andamento antes de retornar.
+func (s *memorySubscription) Receive(ctx context.Context, f func(context.Context, *types.Message)) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	s.mu.Lock()
+	maxOutstanding := s.maxOutstanding
+	s.mu.Unlock()
+	if maxOutstanding <= 0 {
+		maxOutstanding = defaultMaxOutstanding
+	}
+	slots := make(chan struct{}, maxOutstanding)
+	ticker := time.NewTicker(deadlineCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.broker.done:
+			return nil
+		case slots <- struct{}{}:
+		}
+
+		msg := s.next()
+		for msg == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-s.broker.done:
+				return nil
+			case <-s.notify:
+			case <-ticker.C:
+			}
+			msg = s.next()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			f(ctx, msg)
+		}()
+	}
+}
+
+func (s *memorySubscription) enqueue(msg types.Message) {
+	s.mu.Lock()
+	s.pending = append(s.pending, &pendingMessage{msg: msg})
+	s.mu.Unlock()
+	s.signal()
+}
+
+func (s *memorySubscription) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next reserva a próxima mensagem disponível, incluindo as que tiveram o prazo de ack expirado.
+// Mensagens com a mesma chave de ordenação só são entregues depois que a anterior for confirmada.
+func (s *memorySubscription) next() *types.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	blockedKeys := map[string]bool{}
+	for _, p := range s.pending {
+		key := p.msg.OrderingKey
+		if key != "" && blockedKeys[key] {
+			continue
+		}
+		if key != "" {
+			blockedKeys[key] = true
+		}
+		if p.inFlight && now.Before(p.deadline) {
+			continue
+		}
+		p.inFlight = true
+		p.attempts++
+		p.delivery++
+		p.deadline = now.Add(s.ackDeadline)
+		msg := p.msg
+		attempt := p.attempts
+		msg.DeliveryAttempt = &attempt
+		delivery := p.delivery
+		return types.NewReceivedMessage(msg,
+			func() { s.ack(p, delivery) },
+			func() { s.nack(p, delivery) },
+		)
+	}
+	return nil
+}
+
+func (s *memorySubscription) ack(p *pendingMessage, delivery int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.delivery != delivery || !p.inFlight {
+		return
+	}
+	for i, item := range s.pending {
+		if item == p {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *memorySubscription) nack(p *pendingMessage, delivery int) {
+	s.mu.Lock()
+	if p.delivery != delivery || !p.inFlight {
+		s.mu.Unlock()
+		return
+	}
+	p.inFlight = false
+	s.mu.Unlock()
+	s.signal()
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,251 @@
+package memorybroker_test
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/api/iterator"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/memory_broker"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveN(t *testing.T, broker *memorybroker.MemoryBroker, subID string, n int, fn func(*types.Message)) []*types.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var received []*types.Message
+	sub := findSubscription(t, broker, subID)
+	err := sub.Receive(ctx, func(ctx context.Context, msg *types.Message) {
+		mu.Lock()
+		received = append(received, msg)
+		done := len(received) >= n
+		mu.Unlock()
+		fn(msg)
+		if done {
+			cancel()
+		}
+	})
+	assert.NoError(t, err)
+	return received
+}
+
+func findSubscription(t *testing.T, broker *memorybroker.MemoryBroker, id string) interfaces.ISubscription {
+	t.Helper()
+	it := broker.Subscriptions(context.Background())
+	for {
+		sub, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			t.Fatalf("assinatura %s não encontrada", id)
+		}
+		if sub.ID() == id {
+			return sub
+		}
+	}
+}
+
+func TestMemoryBroker_FanOut(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("sub-a", "topic"))
+	assert.NoError(t, broker.CreateSubscription("sub-b", "topic"))
+
+	id, err := broker.Topic("topic").Publish(context.Background(), &types.Message{
+		Data:       []byte("hello"),
+		Attributes: map[string]string{"k": "v"},
+	}).Get(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, id)
+
+	for _, subID := range []string{"sub-a", "sub-b"} {
+		msgs := receiveN(t, broker, subID, 1, func(m *types.Message) { m.Ack() })
+		assert.Len(t, msgs, 1)
+		assert.Equal(t, id, msgs[0].ID)
+		assert.Equal(t, "hello", string(msgs[0].Data))
+		assert.Equal(t, "v", msgs[0].Attributes["k"])
+		assert.Equal(t, 1, *msgs[0].DeliveryAttempt)
+	}
+}
+
+func TestMemoryBroker_NackRedelivers(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	_, err := broker.Topic("topic").Publish(context.Background(), &types.Message{Data: []byte("x")}).Get(context.Background())
+	assert.NoError(t, err)
+
+	msgs := receiveN(t, broker, "sub", 2, func(m *types.Message) {
+		if *m.DeliveryAttempt == 1 {
+			m.Nack()
+			return
+		}
+		m.Ack()
+	})
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, msgs[0].ID, msgs[1].ID)
+	assert.Equal(t, 2, *msgs[1].DeliveryAttempt)
+}
+
+func TestMemoryBroker_AckDeadlineRedelivers(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(150 * time.Millisecond)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	_, err := broker.Topic("topic").Publish(context.Background(), &types.Message{Data: []byte("x")}).Get(context.Background())
+	assert.NoError(t, err)
+
+	msgs := receiveN(t, broker, "sub", 2, func(m *types.Message) {
+		if *m.DeliveryAttempt == 2 {
+			m.Ack()
+		}
+	})
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, 2, *msgs[1].DeliveryAttempt)
+}
+
+func TestMemoryBroker_AckedMessageIsNotRedelivered(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(50 * time.Millisecond)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	_, _ = broker.Topic("topic").Publish(context.Background(), &types.Message{Data: []byte("x")}).Get(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	count := 0
+	var mu sync.Mutex
+	err := findSubscription(t, broker, "sub").Receive(ctx, func(ctx context.Context, m *types.Message) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		m.Ack()
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestMemoryBroker_PublishUnknownTopic(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(0)
+	_, err := broker.Topic("nao-existe").Publish(context.Background(), &types.Message{}).Get(context.Background())
+	assert.Error(t, err)
+}
+
+func TestMemoryBroker_DuplicateSubscription(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(0)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	assert.Error(t, broker.CreateSubscription("sub", "topic"))
+}
+
+func TestMemoryBroker_Close(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(0)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	sub := findSubscription(t, broker, "sub")
+
+	done := make(chan error)
+	go func() {
+		done <- sub.Receive(context.Background(), func(ctx context.Context, m *types.Message) {})
+	}()
+	assert.NoError(t, broker.Close())
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Receive deveria retornar após Close")
+	}
+
+	_, err := broker.Topic("topic").Publish(context.Background(), &types.Message{}).Get(context.Background())
+	assert.ErrorIs(t, err, memorybroker.ErrBrokerClosed)
+}
+
+func TestNewMemoryBrokerFromConfig(t *testing.T) {
+	broker, err := memorybroker.NewMemoryBrokerFromConfig(types.BrokerConfig{
+		Subscriptions: []types.SubscriptionConfig{
+			{ID: "sub-a", Topic: "topic"},
+			{ID: "sub-b", Topic: "other"},
+		},
+	})
+	assert.NoError(t, err)
+	it := broker.Subscriptions(context.Background())
+	first, _ := it.Next()
+	second, _ := it.Next()
+	_, err = it.Next()
+	assert.Equal(t, "sub-a", first.ID())
+	assert.Equal(t, "sub-b", second.ID())
+	assert.ErrorIs(t, err, iterator.Done)
+}
+
+func TestMemoryBroker_Provision(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(0)
+	assert.NoError(t, broker.CreateSubscription("sub-a", "topic"))
+	cfg := types.BrokerConfig{
+		Topics: []string{"audit"},
+		Subscriptions: []types.SubscriptionConfig{
+			{ID: "sub-a", Topic: "topic"},
+			{ID: "sub-b", Topic: "topic"},
+		},
+	}
+	assert.NoError(t, broker.Provision(context.Background(), cfg))
+	assert.NoError(t, broker.Provision(context.Background(), cfg))
+	findSubscription(t, broker, "sub-b")
+
+	_, err := broker.Topic("audit").Publish(context.Background(), &types.Message{}).Get(context.Background())
+	assert.NoError(t, err)
+
+	err = broker.Provision(context.Background(), types.BrokerConfig{
+		Subscriptions: []types.SubscriptionConfig{{ID: "sub-a", Topic: "outro"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestMemoryBroker_OrderingKey(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	for _, data := range []string{"1", "2", "3"} {
+		_, err := broker.Topic("topic").Publish(context.Background(), &types.Message{
+			Data:        []byte(data),
+			OrderingKey: "user-1",
+		}).Get(context.Background())
+		assert.NoError(t, err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	receiveN(t, broker, "sub", 4, func(m *types.Message) {
+		mu.Lock()
+		order = append(order, string(m.Data))
+		mu.Unlock()
+		// A primeira entrega da mensagem "2" falha e deve ser repetida antes da "3"
+		if string(m.Data) == "2" && *m.DeliveryAttempt == 1 {
+			time.Sleep(20 * time.Millisecond)
+			m.Nack()
+			return
+		}
+		m.Ack()
+	})
+	assert.Equal(t, []string{"1", "2", "2", "3"}, order)
+}
+
+func TestMemoryBroker_ReceiveSettingsLimitaMensagensEmAndamento(t *testing.T) {
+	broker := memorybroker.NewMemoryBroker(time.Second)
+	assert.NoError(t, broker.CreateSubscription("sub", "topic"))
+	for i := 0; i < 5; i++ {
+		_, err := broker.Topic("topic").Publish(context.Background(), &types.Message{Data: []byte("x")}).Get(context.Background())
+		assert.NoError(t, err)
+	}
+	sub := findSubscription(t, broker, "sub")
+	sub.(interfaces.IReceiveSettingsAware).SetReceiveSettings(types.ReceiveSettings{MaxOutstandingMessages: 1})
+
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	receiveN(t, broker, "sub", 5, func(m *types.Message) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		m.Ack()
+	})
+	assert.Equal(t, 1, peak)
+}
@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"queue/core/domain/enum"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"queue/core/infra/utils/functions"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "queue"
+
+// Resultados das chamadas aos handlers, usados no rótulo outcome de queue_handler_calls_total.
+const (
+	HandlerSuccess = "success"
+	HandlerError   = "error"
+	HandlerTimeout = "timeout"
+)
+
+// Resultados do envio ao dead-letter, usados no rótulo result de queue_dead_letter_messages_total.
+const (
+	DeadLetterPublished = "published"
+	DeadLetterFailed    = "failed"
+)
+
+// Registry reúne as métricas do serviço e os coletores do runtime do Go e do processo.
+// É um registro próprio, e não o padrão do pacote prometheus, para que /metrics exponha só o que é declarado aqui.
+var Registry = prometheus.NewRegistry()
+
+var (
+	publishRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_requests_total",
+		Help:      "Mensagens recebidas para publicação por tópico e resultado.",
+	}, []string{"topic", "outcome"})
+	publishDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "publish_duration_seconds",
+		Help:      "Tempo até o broker confirmar a publicação, por tópico.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"topic"})
+	messagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Mensagens recebidas por assinatura, incluindo reentregas.",
+	}, []string{"subscription"})
+	messagesProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_processed_total",
+		Help:      "Destino das mensagens recebidas por assinatura: acked, nacked, dead-lettered ou discarded.",
+	}, []string{"subscription", "outcome"})
+	handlerCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_calls_total",
+		Help:      "Execuções dos handlers por assinatura, handler e resultado.",
+	}, []string{"subscription", "handler", "outcome"})
+	handlerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Duração das execuções dos handlers por assinatura e handler.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"subscription", "handler"})
+	notificationRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_requests_total",
+		Help:      "Requisições à API de notificações por status HTTP; erros de conexão contam como 500.",
+	}, []string{"status_code"})
+	notificationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "notification_request_duration_seconds",
+		Help:      "Duração das requisições à API de notificações por status HTTP.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"status_code"})
+	notificationRetries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "notification_retries_total",
+		Help:      "Novas tentativas de envio de notificação após uma falha repetível.",
+	})
+	deadLetterMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dead_letter_messages_total",
+		Help:      "Mensagens enviadas ao dead-letter por assinatura e resultado do envio.",
+	}, []string{"subscription", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		publishRequests,
+		publishDuration,
+		messagesReceived,
+		messagesProcessed,
+		handlerCalls,
+		handlerDuration,
+		notificationRequests,
+		notificationDuration,
+		notificationRetries,
+		deadLetterMessages,
+	)
+}
+
+// Handler responde no formato de exposição do Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// UnknownTopic substitui no rótulo topic os tópicos informados pelo cliente que não puderam ser confirmados,
+// para que tópicos arbitrários não criem séries novas.
+const UnknownTopic = "unknown"
+
+// PublishObserved registra uma publicação. A latência só é registrada quando o broker foi chamado.
+func PublishObserved(topic string, outcome enum.PublishOutcomeEnum, duration time.Duration) {
+	publishRequests.WithLabelValues(topic, string(outcome)).Inc()
+	if outcome == enum.PublishPublished || outcome == enum.PublishFailed {
+		publishDuration.WithLabelValues(topic).Observe(duration.Seconds())
+	}
+}
+
+func MessageReceived(subscriptionID string) {
+	messagesReceived.WithLabelValues(subscriptionID).Inc()
+}
+
+func MessageProcessed(subscriptionID string, outcome enum.MessageOutcomeEnum) {
+	messagesProcessed.WithLabelValues(subscriptionID, string(outcome)).Inc()
+}
+
+// HandlerObserved classifica como timeout o erro de um handler cujo contexto expirou.
+func HandlerObserved(ctx context.Context, subscriptionID string, handler string, err error, duration time.Duration) {
+	outcome := HandlerSuccess
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		outcome = HandlerTimeout
+	case err != nil:
+		outcome = HandlerError
+	}
+	handlerCalls.WithLabelValues(subscriptionID, handler, outcome).Inc()
+	handlerDuration.WithLabelValues(subscriptionID, handler).Observe(duration.Seconds())
+}
+
+// NotificationObserved usa o status da resposta; erros sem HttpException são contados como 500,
+// como os erros de conexão devolvidos por functions.Send.
+func NotificationObserved(err error, duration time.Duration) {
+	status := http.StatusOK
+	var httpErr *functions.HttpException
+	var decodeErr *functions.ResponseDecodeError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Status
+	} else if errors.As(err, &decodeErr) {
+		status = decodeErr.Status
+	} else if err != nil {
+		status = http.StatusInternalServerError
+	}
+	code := strconv.Itoa(status)
+	notificationRequests.WithLabelValues(code).Inc()
+	notificationDuration.WithLabelValues(code).Observe(duration.Seconds())
+}
+
+func NotificationRetried() {
+	notificationRetries.Inc()
+}
+
+func DeadLetterObserved(subscriptionID string, err error) {
+	result := DeadLetterPublished
+	if err != nil {
+		result = DeadLetterFailed
+	}
+	deadLetterMessages.WithLabelValues(subscriptionID, result).Inc()
+}
+
+// Middleware mede cada handler das assinaturas; deve ser o último da lista para medir também os demais middlewares.
+func Middleware(subscriptionID string, name string, handler interfaces.IEventHandler) interfaces.IEventHandler {
+	return &measuredHandler{subscriptionID: subscriptionID, name: name, inner: handler}
+}
+
+type measuredHandler struct {
+	subscriptionID string
+	name           string
+	inner          interfaces.IEventHandler
+}
+
+func (h *measuredHandler) Supports(topic string) bool {
+	return h.inner.Supports(topic)
+}
+
+func (h *measuredHandler) Handle(ctx context.Context, event *types.Event) error {
+	start := time.Now()
+	err := h.inner.Handle(ctx, event)
+	HandlerObserved(ctx, h.subscriptionID, h.name, err, time.Since(start))
+	return err
+}
@@ -0,0 +1,122 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"queue/core/infra/metrics"
+	"queue/core/infra/utils/functions"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Os coletores são globais, então cada teste usa rótulos próprios para não depender dos demais.
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestPublishObserved(t *testing.T) {
+	metrics.PublishObserved("metrics-publish", enum.PublishPublished, 10*time.Millisecond)
+	metrics.PublishObserved("metrics-publish", enum.PublishPublished, 20*time.Millisecond)
+	metrics.PublishObserved("metrics-publish", enum.PublishRejected, 0)
+
+	body := scrape(t)
+	assert.Contains(t, body, `queue_publish_requests_total{outcome="published",topic="metrics-publish"} 2`)
+	assert.Contains(t, body, `queue_publish_requests_total{outcome="rejected",topic="metrics-publish"} 1`)
+	// Rejeições não chegam ao broker e ficam fora da latência
+	assert.Contains(t, body, `queue_publish_duration_seconds_count{topic="metrics-publish"} 2`)
+}
+
+func TestMessageMetrics(t *testing.T) {
+	metrics.MessageReceived("metrics-sub")
+	metrics.MessageReceived("metrics-sub")
+	metrics.MessageProcessed("metrics-sub", enum.MessageAcked)
+	metrics.MessageProcessed("metrics-sub", enum.MessageNacked)
+
+	body := scrape(t)
+	assert.Contains(t, body, `queue_messages_received_total{subscription="metrics-sub"} 2`)
+	assert.Contains(t, body, `queue_messages_processed_total{outcome="acked",subscription="metrics-sub"} 1`)
+	assert.Contains(t, body, `queue_messages_processed_total{outcome="nacked",subscription="metrics-sub"} 1`)
+}
+
+func TestHandlerObserved(t *testing.T) {
+	metrics.HandlerObserved(context.Background(), "metrics-handler-sub", "audit", nil, time.Millisecond)
+	metrics.HandlerObserved(context.Background(), "metrics-handler-sub", "audit", errors.New("falhou"), time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	metrics.HandlerObserved(ctx, "metrics-handler-sub", "audit", ctx.Err(), time.Millisecond)
+
+	body := scrape(t)
+	for _, outcome := range []string{"success", "error", "timeout"} {
+		assert.Contains(t, body, `queue_handler_calls_total{handler="audit",outcome="`+outcome+`",subscription="metrics-handler-sub"} 1`)
+	}
+	assert.Contains(t, body, `queue_handler_duration_seconds_count{handler="audit",subscription="metrics-handler-sub"} 3`)
+}
+
+type handlerFunc func(ctx context.Context, event *types.Event) error
+
+func (f handlerFunc) Supports(topic string) bool { return topic == "t" }
+
+func (f handlerFunc) Handle(ctx context.Context, event *types.Event) error { return f(ctx, event) }
+
+func TestMiddleware(t *testing.T) {
+	want := errors.New("falhou")
+	handler := metrics.Middleware("metrics-middleware-sub", "notification", handlerFunc(func(ctx context.Context, event *types.Event) error {
+		return want
+	}))
+
+	assert.True(t, handler.Supports("t"))
+	assert.False(t, handler.Supports("outro"))
+	assert.ErrorIs(t, handler.Handle(context.Background(), &types.Event{}), want)
+	assert.Contains(t, scrape(t), `queue_handler_calls_total{handler="notification",outcome="error",subscription="metrics-middleware-sub"} 1`)
+}
+
+func TestNotificationObserved(t *testing.T) {
+	before := scrape(t)
+	metrics.NotificationObserved(&functions.HttpException{Status: 418}, time.Millisecond)
+	metrics.NotificationObserved(errors.New("conexão recusada"), time.Millisecond)
+	metrics.NotificationObserved(&functions.ResponseDecodeError{Status: 202, Err: errors.New("json inválido")}, time.Millisecond)
+	metrics.NotificationRetried()
+
+	body := scrape(t)
+	assert.Contains(t, body, `queue_notification_requests_total{status_code="418"} 1`)
+	assert.Contains(t, body, `queue_notification_request_duration_seconds_count{status_code="418"} 1`)
+	assert.Contains(t, body, `queue_notification_requests_total{status_code="202"} 1`)
+	assert.Equal(t, counter(t, before, "queue_notification_requests_total{status_code=\"500\"}")+1,
+		counter(t, body, "queue_notification_requests_total{status_code=\"500\"}"))
+	assert.Equal(t, counter(t, before, "queue_notification_retries_total")+1, counter(t, body, "queue_notification_retries_total"))
+}
+
+func TestDeadLetterObserved(t *testing.T) {
+	metrics.DeadLetterObserved("metrics-dlq-sub", nil)
+	metrics.DeadLetterObserved("metrics-dlq-sub", errors.New("falhou"))
+
+	body := scrape(t)
+	assert.Contains(t, body, `queue_dead_letter_messages_total{result="published",subscription="metrics-dlq-sub"} 1`)
+	assert.Contains(t, body, `queue_dead_letter_messages_total{result="failed",subscription="metrics-dlq-sub"} 1`)
+}
+
+// counter lê o valor de uma série na exposição; séries ausentes valem zero.
+func counter(t *testing.T, body string, series string) int {
+	t.Helper()
+	for _, line := range strings.Split(body, "\n") {
+		if value, ok := strings.CutPrefix(line, series+" "); ok {
+			var n int
+			_, err := fmt.Sscan(value, &n)
+			assert.NoError(t, err)
+			return n
+		}
+	}
+	return 0
+}
@@ -28,8 +28,46 @@ func UseClassTransformerMiddleware(dtoType interface{}) gin.HandlerFunc {
 	}
 }
 
+// BatchItem guarda o resultado da transformação de cada item de um lote, preservando sua posição original.
+type BatchItem struct {
+	Index int
+	Dto   interface{}
+	Err   error
+}
+
+func UseClassTransformerBatchMiddleware(dtoType interface{}) gin.HandlerFunc {
+	typ := reflect.TypeOf(dtoType)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return func(c *gin.Context) {
+		body, err := captureRequestBody(c)
+		if err != nil {
+			handleError(c, "Erro ao ler corpo da requisição", err)
+			return
+		}
+		var rawItems []json.RawMessage
+		if err := decodeJSON(body, &rawItems); err != nil {
+			handleError(c, "O corpo da requisição deve ser uma lista", err)
+			return
+		}
+		items := make([]BatchItem, len(rawItems))
+		for i, raw := range rawItems {
+			dto := reflect.New(typ).Interface()
+			items[i] = BatchItem{Index: i, Dto: dto, Err: BindAndValidate(raw, dto)}
+		}
+		c.Set("dtos", items)
+		c.Next()
+	}
+}
+
 func ClassTransformerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// DTO já resolvido por um middleware anterior (ex.: CloudEvents)
+		if _, exists := c.Get("dto"); exists {
+			c.Next()
+			return
+		}
 		rawType, exists := c.Get("dtoType")
 		if !exists {
 			handleError(c, "Tipo de DTO não configurado", fmt.Errorf("Tipo de DTO não encontrado no contexto"))
@@ -69,7 +107,12 @@ func BindAndValidate(data []byte, dto interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = validate.Struct(dto)
+	return Validate(dto)
+}
+
+// Validate aplica as regras de validação do DTO e o pipe de trim, sem decodificar o corpo.
+func Validate(dto interface{}) error {
+	err := validate.Struct(dto)
 	if err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
@@ -43,3 +43,40 @@ func TestClassTransformerMiddleware_WithInvalidDTO(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code, "Status esperado: 400")
 }
+
+func TestUseClassTransformerBatchMiddleware(t *testing.T) {
+	type TestDTO struct {
+		Name string `json:"name" validate:"required"`
+	}
+	r := gin.Default()
+	var items []classtransformer.BatchItem
+	r.POST("/test", classtransformer.UseClassTransformerBatchMiddleware(&TestDTO{}), func(c *gin.Context) {
+		raw, exists := c.Get("dtos")
+		assert.True(t, exists)
+		items = raw.([]classtransformer.BatchItem)
+		c.JSON(http.StatusOK, gin.H{"status": "success"})
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBuffer([]byte(`[{"name":" John "},{"name":""}]`)))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, items, 2)
+	assert.NoError(t, items[0].Err)
+	assert.Equal(t, "John", items[0].Dto.(*TestDTO).Name)
+	assert.Equal(t, 1, items[1].Index)
+	assert.Error(t, items[1].Err)
+}
+
+func TestUseClassTransformerBatchMiddleware_CorpoNaoELista(t *testing.T) {
+	type TestDTO struct {
+		Name string `json:"name" validate:"required"`
+	}
+	r := gin.Default()
+	r.POST("/test", classtransformer.UseClassTransformerBatchMiddleware(&TestDTO{}), func(c *gin.Context) {
+		t.FailNow()
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBuffer([]byte(`{"name":"John"}`)))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,39 @@
+package classtransformer
+
+import (
+	"github.com/gin-gonic/gin"
+	"queue/core/domain/types"
+	"queue/core/infra/cloudevents"
+)
+
+// UseCloudEventsMiddleware converte requisições CloudEvents (modo estruturado ou binário) no DTO
+// do endpoint. Requisições em outros formatos seguem para o próximo middleware sem alteração.
+func UseCloudEventsMiddleware(convert func(event *types.Event) (interface{}, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cloudevents.IsHTTPRequest(c.Request.Header) {
+			c.Next()
+			return
+		}
+		body, err := captureRequestBody(c)
+		if err != nil {
+			handleError(c, "Erro ao ler corpo da requisição", err)
+			return
+		}
+		event, err := cloudevents.FromHTTPRequest(c.Request.Header, body)
+		if err != nil {
+			handleError(c, err.Error(), err)
+			return
+		}
+		dto, err := convert(event)
+		if err != nil {
+			handleError(c, err.Error(), err)
+			return
+		}
+		if err := Validate(dto); err != nil {
+			handleError(c, err.Error(), err)
+			return
+		}
+		c.Set("dto", dto)
+		c.Next()
+	}
+}
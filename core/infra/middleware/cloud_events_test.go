@@ -0,0 +1,74 @@
+package classtransformer_test
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/domain/types"
+	"queue/core/infra/middleware"
+	"testing"
+)
+
+type eventDTO struct {
+	Topic string `json:"topic" validate:"required"`
+}
+
+func setupCloudEventsRouter(handler gin.HandlerFunc) *gin.Engine {
+	r := gin.Default()
+	r.POST("/test",
+		classtransformer.UseCloudEventsMiddleware(func(event *types.Event) (interface{}, error) {
+			return &eventDTO{Topic: event.Extensions["topic"]}, nil
+		}),
+		classtransformer.UseClassTransformerMiddleware(&eventDTO{}),
+		handler,
+	)
+	return r
+}
+
+func TestUseCloudEventsMiddleware_ConverteCloudEvent(t *testing.T) {
+	var got interface{}
+	r := setupCloudEventsRouter(func(c *gin.Context) {
+		got, _ = c.Get("dto")
+		c.Status(http.StatusOK)
+	})
+	body := `{"specversion":"1.0","id":"1","source":"s","type":"t","topic":"notifications"}`
+	req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &eventDTO{Topic: "notifications"}, got)
+}
+
+func TestUseCloudEventsMiddleware_RequisicaoComum(t *testing.T) {
+	var got interface{}
+	r := setupCloudEventsRouter(func(c *gin.Context) {
+		got, _ = c.Get("dto")
+		c.Status(http.StatusOK)
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(`{"topic":"audit"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, &eventDTO{Topic: "audit"}, got)
+}
+
+func TestUseCloudEventsMiddleware_Invalido(t *testing.T) {
+	r := setupCloudEventsRouter(func(c *gin.Context) { t.FailNow() })
+	cases := map[string]string{
+		"atributos ausentes": `{"specversion":"1.0","topic":"notifications"}`,
+		"dto inválido":       `{"specversion":"1.0","id":"1","source":"s","type":"t"}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, "/test", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/cloudevents+json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
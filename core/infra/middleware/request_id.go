@@ -0,0 +1,37 @@
+package classtransformer
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"queue/core/infra/logging"
+)
+
+// maxRequestIDLength limita o id aceito do cliente, que é repetido em logs e atributos de mensagens.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware usa o X-Request-ID recebido ou gera um novo, devolve-o na resposta e o guarda no
+// contexto da requisição, de onde ele segue para os logs e para as mensagens publicadas.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(logging.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID aceita apenas caracteres ASCII visíveis, para que o id não quebre linhas de log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
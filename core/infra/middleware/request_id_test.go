@@ -0,0 +1,47 @@
+package classtransformer_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/infra/logging"
+	"queue/core/infra/middleware"
+	"strings"
+	"testing"
+)
+
+func requestIDRouter(got *string) *gin.Engine {
+	r := gin.New()
+	r.Use(classtransformer.RequestIDMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		*got = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestRequestIDMiddleware_UsaIdRecebido(t *testing.T) {
+	var got string
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	w := httptest.NewRecorder()
+	requestIDRouter(&got).ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", got)
+	assert.Equal(t, "req-123", w.Header().Get("X-Request-ID"))
+}
+
+func TestRequestIDMiddleware_GeraIdQuandoAusenteOuInvalido(t *testing.T) {
+	for _, header := range []string{"", "com espaço", strings.Repeat("a", 129)} {
+		var got string
+		req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("X-Request-ID", header)
+		w := httptest.NewRecorder()
+		requestIDRouter(&got).ServeHTTP(w, req)
+
+		assert.Len(t, got, 36, header)
+		assert.NotEqual(t, header, got)
+		assert.Equal(t, got, w.Header().Get("X-Request-ID"))
+	}
+}
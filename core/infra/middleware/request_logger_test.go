@@ -0,0 +1,39 @@
+package classtransformer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/domain/types"
+	"queue/core/infra/logging"
+	"queue/core/infra/middleware"
+	"testing"
+)
+
+func TestRequestLoggerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, types.LogConfig{Format: "json"}))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	r := gin.New()
+	r.Use(classtransformer.RequestIDMiddleware(), classtransformer.RequestLoggerMiddleware())
+	r.POST("/publish", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+	req, _ := http.NewRequest(http.MethodPost, "/publish", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), buf.String())
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "POST", line["method"])
+	assert.Equal(t, "/publish", line["path"])
+	assert.Equal(t, 400.0, line["status"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Contains(t, line, "duration_ms")
+}
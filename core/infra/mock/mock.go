@@ -1,11 +1,11 @@
 package mock
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
 	"errors"
 	"google.golang.org/api/iterator"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
 )
 
 // MockSubscription implementa interfaces.ISubscription
@@ -15,7 +15,7 @@ type MockSubscription struct {
 
 func (m *MockSubscription) ID() string { return m.IDValue }
 
-func (m *MockSubscription) Receive(ctx context.Context, fn func(context.Context, *pubsub.Message)) error {
+func (m *MockSubscription) Receive(ctx context.Context, fn func(context.Context, *types.Message)) error {
 	return nil
 }
 
@@ -58,7 +58,7 @@ func (m *MockTopic) ID() string { return m.IDValue }
 
 func (m *MockTopic) Stop() {}
 
-func (m *MockTopic) Publish(ctx context.Context, msg *pubsub.Message) interfaces.IPublishResult {
+func (m *MockTopic) Publish(ctx context.Context, msg *types.Message) interfaces.IPublishResult {
 	if m.IDValue == "fail" {
 		return &MockPublishResult{MsgID: "", Err: errors.New("erro simulado")}
 	}
@@ -101,7 +101,7 @@ type MockSubscribeHandler struct {
 	ShouldFail bool
 }
 
-func (m *MockSubscribeHandler) Handle(sub interfaces.ISubscription) error {
+func (m *MockSubscribeHandler) Handle(ctx context.Context, sub interfaces.ISubscription) error {
 	m.CalledWith = sub
 	if m.ShouldFail {
 		return errors.New("erro simulado")
@@ -1,11 +1,11 @@
 package mock_test
 
 import (
-	"cloud.google.com/go/pubsub"
 	"context"
 	"errors"
 	"google.golang.org/api/iterator"
 	"log"
+	"queue/core/domain/types"
 	"queue/core/infra/mock"
 	"testing"
 )
@@ -20,7 +20,7 @@ func TestMockSubscription_ID(t *testing.T) {
 func TestMockSubscription_Receive(t *testing.T) {
 	mockSub := &mock.MockSubscription{IDValue: "sub-1"}
 	called := false
-	err := mockSub.Receive(context.Background(), func(ctx context.Context, m *pubsub.Message) {
+	err := mockSub.Receive(context.Background(), func(ctx context.Context, m *types.Message) {
 		called = true
 	})
 	if err != nil {
@@ -66,7 +66,7 @@ func TestMockTopic_ID(t *testing.T) {
 
 func TestMockTopic_Publish(t *testing.T) {
 	topic := &mock.MockTopic{IDValue: "top-1"}
-	result := topic.Publish(context.Background(), &pubsub.Message{Data: []byte("x")})
+	result := topic.Publish(context.Background(), &types.Message{Data: []byte("x")})
 	if result == nil {
 		t.Errorf("Esperava um PublishResult válido")
 	}
@@ -0,0 +1,179 @@
+package queuectl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"queue/core/application/publish/dto"
+	"queue/core/domain/structs"
+	"queue/core/infra/middleware"
+	"sort"
+	"strings"
+)
+
+// maxLineSize limita o tamanho de cada linha dos arquivos JSONL.
+const maxLineSize = 10 * 1024 * 1024
+
+// line é uma linha não vazia de um arquivo JSONL, já convertida em envelope ou com o erro de leitura.
+type line struct {
+	number int
+	dto    *publishdto.InputDto
+	err    error
+}
+
+func (c *CLI) publish(ctx context.Context, args []string) error {
+	fs := c.flags("publish")
+	file := fs.String("file", "-", "arquivo com o envelope JSON; - lê da entrada padrão")
+	idempotencyKey := fs.String("idempotency-key", "", "chave de idempotência, quando não informada no envelope")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	reader, err := c.open(*file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("erro ao ler o envelope: %w", err)
+	}
+
+	var input publishdto.InputDto
+	if err := classtransformer.BindAndValidate(data, &input); err != nil {
+		return describe(err)
+	}
+	if input.Meta.IdempotencyKey == "" {
+		input.Meta.IdempotencyKey = *idempotencyKey
+	}
+	output, err := c.Publisher.Publish(ctx, input)
+	if err != nil {
+		return describe(err)
+	}
+	return c.encoder().Encode(output)
+}
+
+// publishBatch publica o arquivo em lotes de até MaxBatchSize linhas, com a mesma concorrência
+// do POST /publish/batch. O index de cada resultado é o número da linha no arquivo.
+func (c *CLI) publishBatch(ctx context.Context, args []string) error {
+	fs := c.flags("publish-batch")
+	file := fs.String("file", "-", "arquivo JSONL com um envelope por linha; - lê da entrada padrão")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return c.publishLines(*file, parseEnvelope, func(lines []line) []publishdto.BatchItemResultDto {
+		return c.publishChunk(ctx, lines)
+	})
+}
+
+func parseEnvelope(data []byte) (*publishdto.InputDto, error) {
+	var input publishdto.InputDto
+	if err := classtransformer.BindAndValidate(data, &input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
+
+func (c *CLI) publishChunk(ctx context.Context, lines []line) []publishdto.BatchItemResultDto {
+	results := make([]publishdto.BatchItemResultDto, len(lines))
+	valid := make([]publishdto.InputDto, 0, len(lines))
+	validIndexes := make([]int, 0, len(lines))
+	for i, l := range lines {
+		if l.err != nil {
+			results[i] = failedResult(l, l.err)
+			continue
+		}
+		valid = append(valid, *l.dto)
+		validIndexes = append(validIndexes, i)
+	}
+	for i, result := range c.Publisher.PublishBatch(ctx, valid) {
+		result.Index = lines[validIndexes[i]].number
+		results[validIndexes[i]] = result
+	}
+	return results
+}
+
+// publishLines lê o arquivo em lotes, publica cada lote com send e imprime um resultado por linha.
+// Ao final informa o total em Stderr e retorna ErrFailures se alguma linha falhou.
+func (c *CLI) publishLines(path string, parse func([]byte) (*publishdto.InputDto, error), send func([]line) []publishdto.BatchItemResultDto) error {
+	reader, err := c.open(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	encoder := c.encoder()
+	published, failed := 0, 0
+	flush := func(lines []line) error {
+		for _, result := range send(lines) {
+			if result.Error != "" {
+				failed++
+			} else {
+				published++
+			}
+			if err := encoder.Encode(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	chunk := make([]line, 0, publishdto.MaxBatchSize)
+	number := 0
+	for scanner.Scan() {
+		number++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		dto, err := parse(data)
+		chunk = append(chunk, line{number: number, dto: dto, err: err})
+		if len(chunk) == publishdto.MaxBatchSize {
+			if err := flush(chunk); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("erro ao ler a linha %d: %w", number+1, err)
+	}
+	if len(chunk) > 0 {
+		if err := flush(chunk); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(c.Stderr, "%d mensagem(ns) publicada(s), %d com falha\n", published, failed)
+	if failed > 0 {
+		return ErrFailures
+	}
+	return nil
+}
+
+func failedResult(l line, err error) publishdto.BatchItemResultDto {
+	result := publishdto.BatchItemResultDto{Index: l.number, Error: err.Error()}
+	if l.dto != nil {
+		result.Topic = l.dto.Meta.Topic
+	}
+	var ve *structs.ValidationMessagesError
+	if errors.As(err, &ve) {
+		result.Details = ve.Messages
+	}
+	return result
+}
+
+// describe reúne todas as mensagens de validação, que na API iriam nos detalhes da resposta.
+func describe(err error) error {
+	var ve *structs.ValidationMessagesError
+	if errors.As(err, &ve) && len(ve.Messages) > 1 {
+		messages := append([]string(nil), ve.Messages...)
+		sort.Strings(messages)
+		return errors.New(strings.Join(messages, " "))
+	}
+	return err
+}
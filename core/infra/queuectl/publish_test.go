@@ -0,0 +1,99 @@
+package queuectl_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"queue/core/application/publish/dto"
+	"queue/core/infra/queuectl"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLines[T any](t *testing.T, out string) []T {
+	t.Helper()
+	var items []T
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		var item T
+		require.NoError(t, json.Unmarshal([]byte(line), &item), line)
+		items = append(items, item)
+	}
+	return items
+}
+
+func TestPublish_EntradaPadrao(t *testing.T) {
+	h := newHarness(t)
+	err := h.run(`{"meta":{"topic":"topic","attributes":{"k":"v"}},"data":{"hello":"world"}}`, "publish")
+	require.NoError(t, err)
+
+	outputs := decodeLines[publishdto.OutputDto](t, h.stdout.String())
+	require.Len(t, outputs, 1)
+	assert.NotEmpty(t, outputs[0].MessageID)
+
+	received := h.receive(t, "sub", 200*time.Millisecond)
+	require.Len(t, received, 1)
+	assert.Equal(t, outputs[0].MessageID, received[0].ID)
+	assert.Equal(t, "v", received[0].Attributes["k"])
+	assert.JSONEq(t, `{"meta":{"topic":"topic","attributes":{"k":"v"}},"data":{"hello":"world"}}`, string(received[0].Data))
+}
+
+func TestPublish_Arquivo(t *testing.T) {
+	h := newHarness(t)
+	path := filepath.Join(t.TempDir(), "envelope.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"meta":{"topic":"topic"},"data":1}`), 0o644))
+
+	require.NoError(t, h.run("", "publish", "-file", path))
+	assert.Len(t, h.receive(t, "sub", 200*time.Millisecond), 1)
+
+	assert.ErrorContains(t, h.run("", "publish", "-file", filepath.Join(t.TempDir(), "nao-existe.json")), "erro ao abrir")
+}
+
+func TestPublish_EnvelopeInvalido(t *testing.T) {
+	h := newHarness(t)
+	assert.ErrorContains(t, h.run(`{"meta":{},"data":null}`, "publish"), "O tópico é obrigatório.")
+	assert.ErrorContains(t, h.run(`{"meta":`, "publish"), "Erro ao decodificar dados")
+	assert.Empty(t, h.stdout.String())
+}
+
+func TestPublishBatch(t *testing.T) {
+	h := newHarness(t)
+	input := strings.Join([]string{
+		`{"meta":{"topic":"topic"},"data":1}`,
+		``,
+		`{"meta":{"topic":"topic"}`,
+		`{"meta":{"topic":"nao-existe"},"data":3}`,
+		`{"meta":{"topic":"topic"},"data":4}`,
+	}, "\n")
+
+	err := h.run(input, "publish-batch")
+	assert.ErrorIs(t, err, queuectl.ErrFailures)
+	assert.Contains(t, h.stderr.String(), "2 mensagem(ns) publicada(s), 2 com falha")
+
+	results := decodeLines[publishdto.BatchItemResultDto](t, h.stdout.String())
+	require.Len(t, results, 4)
+	assert.Equal(t, []int{1, 3, 4, 5}, []int{results[0].Index, results[1].Index, results[2].Index, results[3].Index})
+	assert.NotEmpty(t, results[0].MessageID)
+	assert.Contains(t, results[1].Error, "Erro ao decodificar dados")
+	assert.Equal(t, "nao-existe", results[2].Topic)
+	assert.NotEmpty(t, results[2].Error)
+	assert.NotEmpty(t, results[3].MessageID)
+
+	assert.Len(t, h.receive(t, "sub", 200*time.Millisecond), 2)
+}
+
+func TestPublishBatch_LotesMaioresQueOLimite(t *testing.T) {
+	h := newHarness(t)
+	lines := make([]string, publishdto.MaxBatchSize+5)
+	for i := range lines {
+		lines[i] = `{"meta":{"topic":"topic"},"data":1}`
+	}
+
+	require.NoError(t, h.run(strings.Join(lines, "\n"), "publish-batch"))
+	results := decodeLines[publishdto.BatchItemResultDto](t, h.stdout.String())
+	require.Len(t, results, len(lines))
+	assert.Equal(t, len(lines), results[len(results)-1].Index)
+}
@@ -0,0 +1,95 @@
+package queuectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"queue/core/application/publish/service"
+	"queue/core/domain/interfaces"
+)
+
+const usage = `uso: queuectl <comando> [opções]
+
+comandos:
+  publish        publica um envelope {meta, data} lido de -file ou da entrada padrão
+  publish-batch  publica um envelope por linha de um arquivo JSONL
+  tail           imprime as mensagens de uma assinatura sem confirmá-las
+  replay         reenvia para um tópico as mensagens de um arquivo gerado pelo tail
+
+Use queuectl <comando> -h para ver as opções de cada comando.
+`
+
+// ErrFailures indica que parte das mensagens não foi publicada; o detalhe de cada uma já foi impresso.
+var ErrFailures = errors.New("há mensagens com falha")
+
+// CLI executa os comandos do queuectl sobre o mesmo PublishService e IPubSubClient usados pela API.
+// A saída padrão recebe apenas JSON, uma linha por resultado, e as mensagens de uso vão para Stderr.
+type CLI struct {
+	Client    interfaces.IPubSubClient
+	Publisher *publishservice.PublishService
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+}
+
+func NewCLI(client interfaces.IPubSubClient, publisher *publishservice.PublishService) *CLI {
+	return &CLI{
+		Client:    client,
+		Publisher: publisher,
+		Stdin:     os.Stdin,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+	}
+}
+
+// Run executa o comando indicado em args[0]. Retorna flag.ErrHelp quando a ajuda foi pedida.
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(c.Stderr, usage)
+		return errors.New("informe um comando")
+	}
+	switch args[0] {
+	case "publish":
+		return c.publish(ctx, args[1:])
+	case "publish-batch":
+		return c.publishBatch(ctx, args[1:])
+	case "tail":
+		return c.tail(ctx, args[1:])
+	case "replay":
+		return c.replay(ctx, args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(c.Stderr, usage)
+		return flag.ErrHelp
+	default:
+		fmt.Fprint(c.Stderr, usage)
+		return fmt.Errorf("comando %q desconhecido", args[0])
+	}
+}
+
+func (c *CLI) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("queuectl "+name, flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	return fs
+}
+
+// open lê o arquivo informado ou, quando vazio ou "-", a entrada padrão.
+func (c *CLI) open(path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(c.Stdin), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func (c *CLI) encoder() *json.Encoder {
+	encoder := json.NewEncoder(c.Stdout)
+	encoder.SetEscapeHTML(false)
+	return encoder
+}
@@ -0,0 +1,95 @@
+package queuectl_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"google.golang.org/api/iterator"
+	"queue/core/application/publish/service"
+	"queue/core/domain/types"
+	"queue/core/infra/memory_broker"
+	"queue/core/infra/queuectl"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type harness struct {
+	cli    *queuectl.CLI
+	broker *memorybroker.MemoryBroker
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	return newHarnessWithAckDeadline(t, time.Second)
+}
+
+func newHarnessWithAckDeadline(t *testing.T, ackDeadline time.Duration) *harness {
+	t.Helper()
+	broker := memorybroker.NewMemoryBroker(ackDeadline)
+	require.NoError(t, broker.CreateSubscription("sub", "topic"))
+	t.Cleanup(func() { _ = broker.Close() })
+	publisher, _ := publishservice.NewPublishService(broker)
+	h := &harness{broker: broker, stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	h.cli = queuectl.NewCLI(broker, publisher)
+	h.cli.Stdin = strings.NewReader("")
+	h.cli.Stdout = h.stdout
+	h.cli.Stderr = h.stderr
+	return h
+}
+
+func (h *harness) run(stdin string, args ...string) error {
+	h.cli.Stdin = strings.NewReader(stdin)
+	return h.cli.Run(context.Background(), args)
+}
+
+// receive confirma e devolve as mensagens entregues à assinatura até o prazo informado.
+func (h *harness) receive(t *testing.T, subID string, wait time.Duration) []*types.Message {
+	t.Helper()
+	it := h.broker.Subscriptions(context.Background())
+	for {
+		sub, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			t.Fatalf("assinatura %s não encontrada", subID)
+		}
+		if sub.ID() != subID {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		defer cancel()
+		var mu sync.Mutex
+		var received []*types.Message
+		assert.NoError(t, sub.Receive(ctx, func(ctx context.Context, msg *types.Message) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, msg)
+			msg.Ack()
+		}))
+		return received
+	}
+}
+
+func TestCLI_SemComando(t *testing.T) {
+	h := newHarness(t)
+	assert.Error(t, h.run(""))
+	assert.Contains(t, h.stderr.String(), "uso: queuectl")
+}
+
+func TestCLI_ComandoDesconhecido(t *testing.T) {
+	h := newHarness(t)
+	assert.ErrorContains(t, h.run("", "purge"), `"purge"`)
+}
+
+func TestCLI_Ajuda(t *testing.T) {
+	h := newHarness(t)
+	assert.ErrorIs(t, h.run("", "help"), flag.ErrHelp)
+	assert.ErrorIs(t, h.run("", "tail", "-h"), flag.ErrHelp)
+	assert.Contains(t, h.stderr.String(), "-subscription")
+}
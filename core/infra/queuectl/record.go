@@ -0,0 +1,62 @@
+package queuectl
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"queue/core/domain/types"
+	"time"
+)
+
+// Record é a linha JSONL impressa pelo tail e lida pelo replay. Os itens com corpo JSON listados
+// pela API de dead-letter usam os mesmos campos topic, attributes e data e também podem ser reenviados.
+type Record struct {
+	ID              string            `json:"id,omitempty"`
+	Subscription    string            `json:"subscription,omitempty"`
+	Topic           string            `json:"topic,omitempty"`
+	PublishTime     *time.Time        `json:"publishTime,omitempty"`
+	DeliveryAttempt *int              `json:"deliveryAttempt,omitempty"`
+	OrderingKey     string            `json:"orderingKey,omitempty"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+	// Data guarda o corpo quando ele é um JSON válido; os demais vão em DataBase64 para não perder bytes.
+	Data       json.RawMessage `json:"data,omitempty"`
+	DataBase64 string          `json:"dataBase64,omitempty"`
+}
+
+func NewRecord(subscriptionID string, msg *types.Message) Record {
+	record := Record{
+		ID:              msg.ID,
+		Subscription:    subscriptionID,
+		DeliveryAttempt: msg.DeliveryAttempt,
+		OrderingKey:     msg.OrderingKey,
+		Attributes:      msg.Attributes,
+	}
+	if !msg.PublishTime.IsZero() {
+		publishTime := msg.PublishTime
+		record.PublishTime = &publishTime
+	}
+	if len(msg.Data) > 0 && json.Valid(msg.Data) {
+		record.Data = json.RawMessage(msg.Data)
+	} else if len(msg.Data) > 0 {
+		record.DataBase64 = base64.StdEncoding.EncodeToString(msg.Data)
+	}
+	return record
+}
+
+// Payload devolve o corpo original da mensagem.
+func (r Record) Payload() ([]byte, error) {
+	if r.DataBase64 != "" {
+		if len(r.Data) > 0 {
+			return nil, errors.New("informe apenas data ou dataBase64")
+		}
+		data, err := base64.StdEncoding.DecodeString(r.DataBase64)
+		if err != nil {
+			return nil, errors.New("dataBase64 inválido")
+		}
+		return data, nil
+	}
+	if len(r.Data) == 0 {
+		return []byte{}, nil
+	}
+	return []byte(r.Data), nil
+}
@@ -0,0 +1,56 @@
+package queuectl_test
+
+import (
+	"encoding/json"
+	"queue/core/domain/types"
+	"queue/core/infra/queuectl"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecord_DataJSON(t *testing.T) {
+	attempt := 2
+	publishTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := queuectl.NewRecord("sub", &types.Message{
+		ID:              "1",
+		Data:            []byte(`{"a":1}`),
+		Attributes:      map[string]string{"k": "v"},
+		OrderingKey:     "user-1",
+		PublishTime:     publishTime,
+		DeliveryAttempt: &attempt,
+	})
+
+	raw, err := json.Marshal(record)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","subscription":"sub","publishTime":"2026-01-02T03:04:05Z","deliveryAttempt":2,
+		"orderingKey":"user-1","attributes":{"k":"v"},"data":{"a":1}}`, string(raw))
+
+	payload, err := record.Payload()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(payload))
+}
+
+func TestNewRecord_DataBinaria(t *testing.T) {
+	record := queuectl.NewRecord("sub", &types.Message{ID: "1", Data: []byte{0xff, 'x'}})
+	assert.Empty(t, record.Data)
+	assert.Equal(t, "/3g=", record.DataBase64)
+	assert.Nil(t, record.PublishTime)
+
+	payload, err := record.Payload()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 'x'}, payload)
+}
+
+func TestRecord_PayloadInvalido(t *testing.T) {
+	_, err := queuectl.Record{DataBase64: "%%"}.Payload()
+	assert.ErrorContains(t, err, "dataBase64")
+
+	_, err = queuectl.Record{Data: json.RawMessage(`1`), DataBase64: "eA=="}.Payload()
+	assert.Error(t, err)
+
+	payload, err := queuectl.Record{}.Payload()
+	assert.NoError(t, err)
+	assert.Empty(t, payload)
+}
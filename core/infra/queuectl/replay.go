@@ -0,0 +1,68 @@
+package queuectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"queue/core/application/publish/dto"
+)
+
+// replay reenvia as mensagens na ordem do arquivo, uma de cada vez, para manter a ordem das
+// chaves de ordenação. O corpo e os atributos são publicados como estavam, sem novo envelope.
+func (c *CLI) replay(ctx context.Context, args []string) error {
+	fs := c.flags("replay")
+	file := fs.String("file", "-", "arquivo JSONL gerado pelo tail; - lê da entrada padrão")
+	topic := fs.String("topic", "", "tópico de destino; quando vazio usa o campo topic de cada linha")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	parse := func(data []byte) (*publishdto.InputDto, error) {
+		return parseRecord(data, *topic)
+	}
+	return c.publishLines(*file, parse, func(lines []line) []publishdto.BatchItemResultDto {
+		return c.publishSequentially(ctx, lines)
+	})
+}
+
+func parseRecord(data []byte, topic string) (*publishdto.InputDto, error) {
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("Erro ao decodificar dados: %v", err)
+	}
+	if topic == "" {
+		topic = record.Topic
+	}
+	if topic == "" {
+		return nil, errors.New("tópico não informado; use -topic ou o campo topic")
+	}
+	payload, err := record.Payload()
+	if err != nil {
+		return nil, err
+	}
+	return &publishdto.InputDto{
+		Meta: publishdto.MetaDto{
+			Topic:       topic,
+			Attributes:  record.Attributes,
+			OrderingKey: record.OrderingKey,
+		},
+		RawData: payload,
+	}, nil
+}
+
+func (c *CLI) publishSequentially(ctx context.Context, lines []line) []publishdto.BatchItemResultDto {
+	results := make([]publishdto.BatchItemResultDto, len(lines))
+	for i, l := range lines {
+		if l.err != nil {
+			results[i] = failedResult(l, l.err)
+			continue
+		}
+		output, err := c.Publisher.Publish(ctx, *l.dto)
+		if err != nil {
+			results[i] = failedResult(l, err)
+			continue
+		}
+		results[i] = publishdto.BatchItemResultDto{Index: l.number, Topic: output.Topic, MessageID: output.MessageID}
+	}
+	return results
+}
@@ -0,0 +1,62 @@
+package queuectl_test
+
+import (
+	"queue/core/application/publish/dto"
+	"queue/core/domain/types"
+	"queue/core/infra/queuectl"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay_ArquivoDoTail(t *testing.T) {
+	h := newHarness(t)
+	require.NoError(t, h.broker.CreateSubscription("replay-sub", "replay"))
+	h.publish(t,
+		&types.Message{Data: []byte(`{"n":1}`), Attributes: map[string]string{"k": "v"}, OrderingKey: "user-1"},
+		&types.Message{Data: []byte{0xff, 'x'}, OrderingKey: "user-2"},
+	)
+	require.NoError(t, h.run("", "tail", "-subscription", "sub", "-n", "2", "-timeout", "2s"))
+	archive := h.stdout.String()
+	h.stdout.Reset()
+
+	require.NoError(t, h.run(archive, "replay", "-topic", "replay"))
+	results := decodeLines[publishdto.BatchItemResultDto](t, h.stdout.String())
+	require.Len(t, results, 2)
+	assert.Equal(t, "replay", results[0].Topic)
+	assert.NotEmpty(t, results[1].MessageID)
+
+	received := h.receive(t, "replay-sub", 300*time.Millisecond)
+	require.Len(t, received, 2)
+	sort.Slice(received, func(i, j int) bool { return received[i].OrderingKey < received[j].OrderingKey })
+	assert.Equal(t, `{"n":1}`, string(received[0].Data))
+	assert.Equal(t, "v", received[0].Attributes["k"])
+	assert.Equal(t, "user-1", received[0].OrderingKey)
+	assert.Equal(t, []byte{0xff, 'x'}, received[1].Data)
+}
+
+func TestReplay_TopicoDaLinha(t *testing.T) {
+	h := newHarness(t)
+	input := strings.Join([]string{
+		`{"topic":"topic","data":{"n":1}}`,
+		`{"data":{"n":2}}`,
+		`{"topic":"topic","dataBase64":"%%"}`,
+		`nao-e-json`,
+	}, "\n")
+
+	assert.ErrorIs(t, h.run(input, "replay"), queuectl.ErrFailures)
+	results := decodeLines[publishdto.BatchItemResultDto](t, h.stdout.String())
+	require.Len(t, results, 4)
+	assert.Empty(t, results[0].Error)
+	assert.Contains(t, results[1].Error, "tópico não informado")
+	assert.Contains(t, results[2].Error, "dataBase64")
+	assert.Contains(t, results[3].Error, "Erro ao decodificar dados")
+
+	received := h.receive(t, "sub", 200*time.Millisecond)
+	require.Len(t, received, 1)
+	assert.Equal(t, `{"n":1}`, string(received[0].Data))
+}
@@ -0,0 +1,115 @@
+package queuectl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"google.golang.org/api/iterator"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+	"sync"
+)
+
+var ErrSubscriptionNotFound = errors.New("assinatura não encontrada")
+
+// tail imprime cada mensagem recebida uma única vez e as mantém sem ack enquanto executa. Ao sair,
+// todas recebem Nack e voltam para a assinatura. Como as mensagens ficam retidas durante o tail,
+// prefira uma assinatura dedicada em vez da usada pelo subscriber. Com chaves de ordenação, só a
+// primeira mensagem de cada chave é entregue, já que as seguintes aguardam o ack da anterior.
+func (c *CLI) tail(ctx context.Context, args []string) error {
+	fs := c.flags("tail")
+	subscriptionID := fs.String("subscription", "", "assinatura a acompanhar (obrigatório)")
+	limit := fs.Int("n", 0, "encerra após imprimir n mensagens; 0 acompanha até Ctrl+C")
+	timeout := fs.Duration("timeout", 0, "encerra após o tempo informado, por exemplo 30s; 0 não limita")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *subscriptionID == "" {
+		fs.Usage()
+		return errors.New("informe -subscription")
+	}
+	sub, err := c.subscription(ctx, *subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if *timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	var mu sync.Mutex
+	var held []*types.Message
+	var writeErr error
+	released := false
+	seen := map[string]bool{}
+	encoder := json.NewEncoder(c.Stdout)
+	encoder.SetEscapeHTML(false)
+
+	// O Receive do Pub/Sub só retorna depois que as mensagens pendentes recebem ack ou nack,
+	// então as retidas são devolvidas assim que o tail encerra, e não após o Receive.
+	releasedAll := make(chan struct{})
+	go func() {
+		defer close(releasedAll)
+		<-ctx.Done()
+		mu.Lock()
+		defer mu.Unlock()
+		released = true
+		for _, msg := range held {
+			msg.Nack()
+		}
+		held = nil
+	}()
+
+	err = sub.Receive(ctx, func(_ context.Context, msg *types.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		if released {
+			msg.Nack()
+			return
+		}
+		held = append(held, msg)
+		// Reentregas após o prazo de ack não são impressas de novo
+		if seen[msg.ID] || (*limit > 0 && len(seen) >= *limit) || writeErr != nil {
+			return
+		}
+		seen[msg.ID] = true
+		if writeErr = encoder.Encode(NewRecord(sub.ID(), msg)); writeErr != nil {
+			cancel()
+			return
+		}
+		if *limit > 0 && len(seen) >= *limit {
+			cancel()
+		}
+	})
+	cancel()
+	<-releasedAll
+
+	fmt.Fprintf(c.Stderr, "%d mensagem(ns) lida(s) de %s\n", len(seen), sub.ID())
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return nil
+}
+
+func (c *CLI) subscription(ctx context.Context, id string) (interfaces.ISubscription, error) {
+	it := c.Client.Subscriptions(ctx)
+	for {
+		sub, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sub.ID() == id {
+			return sub, nil
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package queuectl_test
+
+import (
+	"context"
+	"queue/core/domain/types"
+	"queue/core/infra/queuectl"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (h *harness) publish(t *testing.T, msgs ...*types.Message) {
+	t.Helper()
+	for _, msg := range msgs {
+		_, err := h.broker.Topic("topic").Publish(context.Background(), msg).Get(context.Background())
+		require.NoError(t, err)
+	}
+}
+
+func TestTail_ImprimeSemConfirmar(t *testing.T) {
+	h := newHarness(t)
+	h.publish(t,
+		&types.Message{Data: []byte(`{"n":1}`), Attributes: map[string]string{"k": "v"}},
+		&types.Message{Data: []byte("texto")},
+	)
+
+	require.NoError(t, h.run("", "tail", "-subscription", "sub", "-n", "2", "-timeout", "2s"))
+	records := decodeLines[queuectl.Record](t, h.stdout.String())
+	require.Len(t, records, 2)
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	assert.Equal(t, "sub", records[0].Subscription)
+	assert.JSONEq(t, `{"n":1}`, string(records[0].Data))
+	assert.Equal(t, "v", records[0].Attributes["k"])
+	assert.Equal(t, "dGV4dG8=", records[1].DataBase64)
+	assert.Contains(t, h.stderr.String(), "2 mensagem(ns) lida(s) de sub")
+
+	// As mensagens continuam na assinatura para o subscriber
+	assert.Len(t, h.receive(t, "sub", 300*time.Millisecond), 2)
+}
+
+func TestTail_NaoRepeteReentregas(t *testing.T) {
+	h := newHarnessWithAckDeadline(t, 100*time.Millisecond)
+	h.publish(t, &types.Message{Data: []byte("1")})
+
+	require.NoError(t, h.run("", "tail", "-subscription", "sub", "-timeout", "500ms"))
+	assert.Len(t, decodeLines[queuectl.Record](t, h.stdout.String()), 1)
+}
+
+func TestTail_EncerraNoPrazo(t *testing.T) {
+	h := newHarness(t)
+	start := time.Now()
+	require.NoError(t, h.run("", "tail", "-subscription", "sub", "-timeout", "100ms"))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Empty(t, h.stdout.String())
+}
+
+func TestTail_CancelamentoDoContexto(t *testing.T) {
+	h := newHarness(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, h.cli.Run(ctx, []string{"tail", "-subscription", "sub"}))
+}
+
+func TestTail_Validacao(t *testing.T) {
+	h := newHarness(t)
+	assert.ErrorContains(t, h.run("", "tail"), "-subscription")
+	assert.ErrorIs(t, h.run("", "tail", "-subscription", "nao-existe"), queuectl.ErrSubscriptionNotFound)
+}
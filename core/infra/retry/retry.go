@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"queue/core/domain/types"
+	"time"
+)
+
+// Do executa fn até obter sucesso, esgotar policy.MaxAttempts ou receber um erro que retryable
+// não aceita, aguardando entre as tentativas um backoff exponencial com jitter. O último erro é retornado.
+func Do(ctx context.Context, policy types.RetryPolicy, retryable func(error) bool, fn func(attempt int) error) error {
+	attempts := max(policy.MaxAttempts, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(attempt); err == nil {
+			return nil
+		}
+		if attempt == attempts || !retryable(err) {
+			return err
+		}
+		timer := time.NewTimer(Backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
+
+// Backoff calcula a espera após a tentativa informada: o intervalo inicial dobra a cada tentativa,
+// limitado ao máximo, e metade dele é sorteada para evitar que os consumidores repitam juntos.
+func Backoff(policy types.RetryPolicy, attempt int) time.Duration {
+	delay := policy.InitialBackoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || delay < policy.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
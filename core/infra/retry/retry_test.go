@@ -0,0 +1,83 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"queue/core/domain/types"
+	"queue/core/infra/retry"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTemporario = errors.New("temporário")
+
+func always(error) bool { return true }
+
+func TestDo_RepeteAteSucesso(t *testing.T) {
+	policy := types.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	calls := 0
+	err := retry.Do(context.Background(), policy, always, func(attempt int) error {
+		calls++
+		if attempt < 3 {
+			return errTemporario
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDo_EsgotaTentativas(t *testing.T) {
+	policy := types.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	calls := 0
+	err := retry.Do(context.Background(), policy, always, func(int) error {
+		calls++
+		return errTemporario
+	})
+	assert.ErrorIs(t, err, errTemporario)
+	assert.Equal(t, 2, calls)
+}
+
+func TestDo_ErroNaoRepetivel(t *testing.T) {
+	policy := types.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}
+	calls := 0
+	err := retry.Do(context.Background(), policy, func(error) bool { return false }, func(int) error {
+		calls++
+		return errTemporario
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_ContextoCancelado(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	policy := types.RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}
+	calls := 0
+	err := retry.Do(ctx, policy, always, func(int) error {
+		calls++
+		return errTemporario
+	})
+	assert.ErrorIs(t, err, errTemporario)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff_ExponencialComLimite(t *testing.T) {
+	policy := types.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for i := 0; i < 50; i++ {
+		first := retry.Backoff(policy, 1)
+		assert.GreaterOrEqual(t, first, 50*time.Millisecond)
+		assert.LessOrEqual(t, first, 100*time.Millisecond)
+
+		second := retry.Backoff(policy, 2)
+		assert.GreaterOrEqual(t, second, 100*time.Millisecond)
+		assert.LessOrEqual(t, second, 200*time.Millisecond)
+
+		capped := retry.Backoff(policy, 10)
+		assert.GreaterOrEqual(t, capped, 150*time.Millisecond)
+		assert.LessOrEqual(t, capped, 300*time.Millisecond)
+	}
+	assert.Zero(t, retry.Backoff(types.RetryPolicy{}, 3))
+}
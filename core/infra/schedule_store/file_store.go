@@ -0,0 +1,153 @@
+package schedulestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"queue/core/domain/types"
+	"sync"
+	"time"
+)
+
+// FileStore persiste os agendamentos em um arquivo JSON, regravado por inteiro a cada alteração.
+// As reservas de Claim ficam só em memória: após um reinício, os agendamentos reservados voltam a ser
+// publicados, mesmo que a publicação anterior tenha chegado ao broker.
+type FileStore struct {
+	mu       sync.Mutex
+	path     string
+	messages map[string]types.ScheduledMessage
+	claimed  map[string]struct{}
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{path: path, messages: map[string]types.ScheduledMessage{}, claimed: map[string]struct{}{}}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler agendamentos: %w", err)
+	}
+	var list []types.ScheduledMessage
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, fmt.Errorf("arquivo de agendamentos inválido: %w", err)
+		}
+	}
+	for _, m := range list {
+		store.messages[m.ID] = m
+	}
+	return store, nil
+}
+
+func (s *FileStore) Save(msg types.ScheduledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, existed := s.messages[msg.ID]
+	s.messages[msg.ID] = msg
+	if err := s.flush(); err != nil {
+		if existed {
+			s.messages[msg.ID] = previous
+		} else {
+			delete(s.messages, msg.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) List() ([]types.ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortByDeliverAt(s.messages, pending(s.claimed)), nil
+}
+
+func (s *FileStore) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[id]; !ok {
+		return false, nil
+	}
+	if _, ok := s.claimed[id]; ok {
+		return false, types.ErrScheduleInFlight
+	}
+	return true, s.remove(id)
+}
+
+func (s *FileStore) Claim(now time.Time) ([]types.ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return claimDue(s.messages, s.claimed, now), nil
+}
+
+func (s *FileStore) Release(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, id)
+	return nil
+}
+
+// Complete mantém a reserva se a gravação falhar, para que o agendamento já publicado não seja
+// publicado de novo; ele só volta a ser tentado após um reinício.
+func (s *FileStore) Complete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[id]; !ok {
+		delete(s.claimed, id)
+		return nil
+	}
+	if err := s.remove(id); err != nil {
+		return err
+	}
+	delete(s.claimed, id)
+	return nil
+}
+
+func (s *FileStore) remove(id string) error {
+	previous := s.messages[id]
+	delete(s.messages, id)
+	if err := s.flush(); err != nil {
+		s.messages[id] = previous
+		return err
+	}
+	return nil
+}
+
+// flush grava em um arquivo temporário, sincroniza-o com o disco e o renomeia sobre o original, para
+// que uma queda no meio da gravação não deixe o arquivo de agendamentos corrompido.
+func (s *FileStore) flush() error {
+	list := sortByDeliverAt(s.messages, func(types.ScheduledMessage) bool { return true })
+	raw, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório de agendamentos: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := writeSynced(tmp, raw); err != nil {
+		return fmt.Errorf("erro ao gravar agendamentos: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("erro ao gravar agendamentos: %w", err)
+	}
+	return nil
+}
+
+func writeSynced(path string, raw []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(raw); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,103 @@
+package schedulestore_test
+
+import (
+	"os"
+	"path/filepath"
+	"queue/core/domain/types"
+	"queue/core/infra/schedule_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore_PersisteEntreInstancias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "scheduled.json")
+	deliverAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	store, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.ScheduledMessage{
+		ID:          "a",
+		Topic:       "t",
+		Data:        []byte(`{"data":1}`),
+		Attributes:  map[string]string{"k": "v"},
+		OrderingKey: "user-1",
+		DeliverAt:   deliverAt,
+	}))
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "b", Topic: "t", DeliverAt: deliverAt.Add(time.Hour)}))
+	deleted, err := store.Delete("b")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	reopened, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	list, err := reopened.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "a", list[0].ID)
+	assert.Equal(t, `{"data":1}`, string(list[0].Data))
+	assert.Equal(t, "v", list[0].Attributes["k"])
+	assert.Equal(t, "user-1", list[0].OrderingKey)
+	assert.True(t, deliverAt.Equal(list[0].DeliverAt))
+
+	due, err := reopened.Claim(deliverAt)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+}
+
+func TestFileStore_ReservaNaoSobreviveAoReinicio(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.json")
+	now := time.Now()
+	store, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "a", Topic: "t", DeliverAt: now}))
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "b", Topic: "t", DeliverAt: now}))
+
+	due, err := store.Claim(now)
+	assert.NoError(t, err)
+	assert.Len(t, due, 2)
+	_, err = store.Delete("a")
+	assert.ErrorIs(t, err, types.ErrScheduleInFlight)
+	assert.NoError(t, store.Complete("b"))
+
+	// O processo parou antes de concluir "a": ela volta a ser publicada
+	reopened, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	due, err = reopened.Claim(now)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+	assert.Equal(t, "a", due[0].ID)
+}
+
+func TestNewFileStore_ArquivoInvalido(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{not-json"), 0o600))
+	_, err := schedulestore.NewFileStore(path)
+	assert.Error(t, err)
+}
+
+func TestFileStore_GravacaoInterrompidaNaoCorrompeArquivo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.json")
+	store, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "a", Topic: "t", DeliverAt: time.Now().Add(time.Hour)}))
+
+	// Simula uma queda durante a gravação anterior: o temporário incompleto não afeta o arquivo
+	assert.NoError(t, os.WriteFile(path+".tmp", []byte(`[{"id":"b"`), 0o600))
+	reopened, err := schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	list, err := reopened.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	// A próxima gravação sobrescreve o temporário e o renomeia sobre o original
+	assert.NoError(t, reopened.Save(types.ScheduledMessage{ID: "b", Topic: "t", DeliverAt: time.Now().Add(time.Hour)}))
+	_, err = os.Stat(path + ".tmp")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	reopened, err = schedulestore.NewFileStore(path)
+	assert.NoError(t, err)
+	list, err = reopened.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+}
@@ -0,0 +1,102 @@
+package schedulestore
+
+import (
+	"queue/core/domain/types"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore mantém os agendamentos apenas em memória; eles são perdidos ao reiniciar o processo.
+type MemoryStore struct {
+	mu       sync.Mutex
+	messages map[string]types.ScheduledMessage
+	claimed  map[string]struct{}
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: map[string]types.ScheduledMessage{}, claimed: map[string]struct{}{}}
+}
+
+func (s *MemoryStore) Save(msg types.ScheduledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[msg.ID] = msg
+	return nil
+}
+
+func (s *MemoryStore) List() ([]types.ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortByDeliverAt(s.messages, pending(s.claimed)), nil
+}
+
+func (s *MemoryStore) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.messages[id]; !ok {
+		return false, nil
+	}
+	if _, ok := s.claimed[id]; ok {
+		return false, types.ErrScheduleInFlight
+	}
+	delete(s.messages, id)
+	return true, nil
+}
+
+func (s *MemoryStore) Claim(now time.Time) ([]types.ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return claimDue(s.messages, s.claimed, now), nil
+}
+
+func (s *MemoryStore) Release(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, id)
+	return nil
+}
+
+func (s *MemoryStore) Complete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.claimed, id)
+	delete(s.messages, id)
+	return nil
+}
+
+// pending filtra os agendamentos que ainda não foram reservados para publicação.
+func pending(claimed map[string]struct{}) func(types.ScheduledMessage) bool {
+	return func(m types.ScheduledMessage) bool {
+		_, ok := claimed[m.ID]
+		return !ok
+	}
+}
+
+// claimDue reserva os agendamentos pendentes cujo horário já chegou e os retorna em ordem de entrega.
+func claimDue(messages map[string]types.ScheduledMessage, claimed map[string]struct{}, now time.Time) []types.ScheduledMessage {
+	isPending := pending(claimed)
+	due := sortByDeliverAt(messages, func(m types.ScheduledMessage) bool {
+		return isPending(m) && !m.DeliverAt.After(now)
+	})
+	for _, m := range due {
+		claimed[m.ID] = struct{}{}
+	}
+	return due
+}
+
+func sortByDeliverAt(messages map[string]types.ScheduledMessage, filter func(types.ScheduledMessage) bool) []types.ScheduledMessage {
+	list := make([]types.ScheduledMessage, 0, len(messages))
+	for _, m := range messages {
+		if filter(m) {
+			list = append(list, m)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].DeliverAt.Equal(list[j].DeliverAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].DeliverAt.Before(list[j].DeliverAt)
+	})
+	return list
+}
@@ -0,0 +1,70 @@
+package schedulestore_test
+
+import (
+	"queue/core/domain/types"
+	"queue/core/infra/schedule_store"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_SaveListDelete(t *testing.T) {
+	store := schedulestore.NewMemoryStore()
+	now := time.Now()
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "b", Topic: "t", DeliverAt: now.Add(time.Hour)}))
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "a", Topic: "t", DeliverAt: now.Add(-time.Minute)}))
+
+	list, err := store.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids(list))
+
+	deleted, err := store.Delete("b")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = store.Delete("b")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	list, _ = store.List()
+	assert.Equal(t, []string{"a"}, ids(list))
+}
+
+func TestMemoryStore_ClaimReservaAteConcluir(t *testing.T) {
+	store := schedulestore.NewMemoryStore()
+	now := time.Now()
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "a", Topic: "t", DeliverAt: now.Add(-time.Minute)}))
+	assert.NoError(t, store.Save(types.ScheduledMessage{ID: "b", Topic: "t", DeliverAt: now.Add(time.Hour)}))
+
+	due, err := store.Claim(now)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, ids(due))
+
+	// Reservada, a mensagem não é entregue de novo nem pode ser cancelada
+	due, _ = store.Claim(now)
+	assert.Empty(t, due)
+	deleted, err := store.Delete("a")
+	assert.ErrorIs(t, err, types.ErrScheduleInFlight)
+	assert.False(t, deleted)
+	list, _ := store.List()
+	assert.Equal(t, []string{"b"}, ids(list))
+
+	assert.NoError(t, store.Release("a"))
+	due, _ = store.Claim(now)
+	assert.Equal(t, []string{"a"}, ids(due))
+
+	assert.NoError(t, store.Complete("a"))
+	assert.NoError(t, store.Release("a"))
+	due, _ = store.Claim(now)
+	assert.Empty(t, due)
+	list, _ = store.List()
+	assert.Equal(t, []string{"b"}, ids(list))
+}
+
+func ids(list []types.ScheduledMessage) []string {
+	out := make([]string, len(list))
+	for i, m := range list {
+		out[i] = m.ID
+	}
+	return out
+}
@@ -3,77 +3,284 @@ package servers
 import (
 	"cloud.google.com/go/pubsub"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"log"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
 	"queue/core/application/auth"
+	"queue/core/application/dead_letter"
 	"queue/core/application/health_check"
+	"queue/core/application/metrics"
 	"queue/core/application/publish"
+	"queue/core/application/schedule"
 	"queue/core/application/subscription"
+	"queue/core/domain/dedup"
+	"queue/core/domain/enum"
 	"queue/core/domain/interfaces"
+	"queue/core/domain/monitor"
 	"queue/core/domain/types"
 	"queue/core/infra/adapter"
 	"queue/core/infra/config"
+	"queue/core/infra/dedup_store"
 	"queue/core/infra/exceptions"
+	"queue/core/infra/idempotency_store"
+	"queue/core/infra/logging"
+	"queue/core/infra/memory_broker"
+	"queue/core/infra/metrics"
+	"queue/core/infra/middleware"
+	"queue/core/infra/schedule_store"
+	"queue/core/infra/topic_registry"
+	"queue/core/infra/tracing"
+	"slices"
+	"syscall"
+	"time"
 )
 
+const (
+	emulatorProjectID   = "local-project"
+	tracingFlushTimeout = 5 * time.Second
+)
+
+// Run sobe o serviço no modo definido em RUN_MODE.
 func Run() {
+	RunWithMode("")
+}
+
+// RunWithMode sobe o serviço no modo informado, que prevalece sobre RUN_MODE quando não vazio.
+func RunWithMode(mode enum.RunModeEnum) {
 	LoadEnv()
 	cfg := config.LoadConfig()
+	if mode != "" {
+		cfg.Mode = string(mode)
+	}
+	logging.Setup(cfg.Log)
+	if err := ValidateConfig(cfg); err != nil {
+		logging.Fatal("Configuração inválida", "mode", RunMode(cfg), "error", err)
+	}
+	slog.Info("Iniciando o serviço", "mode", RunMode(cfg))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		logging.Fatal("Erro ao configurar o tracing", "error", err)
+	}
 
-	pubsubClient := NewPubSubClient(cfg)
-	adapter := pubsubadapter.NewPubSubClientAdapter(pubsubClient)
+	brokerClient := NewBrokerClient(cfg)
+	workers := NewWorkers(context.Background())
 
-	router := SetupRouter(cfg, adapter)
-	if err := StartServer(router, cfg.Port); err != nil {
-		log.Fatalf("Erro ao iniciar o servidor: %v", err)
+	router := SetupRouter(cfg, brokerClient, workers)
+	server := NewHTTPServer(router, cfg.Port)
+	go func() {
+		slog.Info("Servidor HTTP iniciado", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("Erro ao iniciar o servidor", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Sinal de encerramento recebido, iniciando desligamento gracioso", "timeout", cfg.ShutdownTimeout)
+	err = Shutdown(server, workers, brokerClient, cfg.ShutdownTimeout)
+	// Os spans das últimas mensagens só são enviados depois que os receptores terminam
+	flushCtx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+	defer cancel()
+	if flushErr := shutdownTracing(flushCtx); flushErr != nil {
+		slog.Error("Erro ao enviar os spans pendentes", "error", flushErr)
 	}
+	if err != nil {
+		slog.Warn("Desligamento concluído com pendências", "error", err)
+		return
+	}
+	slog.Info("Desligamento concluído")
 }
 
 func LoadEnv() {
 	if err := godotenv.Load(); err != nil {
-		log.Printf("Nenhum arquivo .env encontrado. Usando variáveis de ambiente do sistema: %v", err)
+		slog.Info("Nenhum arquivo .env encontrado. Usando variáveis de ambiente do sistema", "error", err)
 	}
 }
 
-func SetupRouter(cfg *types.Config, pubsubClient interfaces.IPubSubClient) *gin.Engine {
-	r := gin.Default()
+func SetupRouter(cfg *types.Config, pubsubClient interfaces.IPubSubClient, workers *Workers) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery(), classtransformer.RequestIDMiddleware())
+	r.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithPropagators(tracing.Propagator), otelgin.WithFilter(tracedRequest)))
+	r.Use(classtransformer.RequestLoggerMiddleware())
 	r.Use(cors.New(cfg.CorsConfig), exceptions.AllExceptionFilter())
-	r.Use(auth.BasicAuthMiddleware(cfg.Auth.Username, cfg.Auth.Password))
-	RegisterModules(r, cfg, pubsubClient)
+	r.Use(auth.BasicAuthMiddleware(cfg.Auth.Username, cfg.Auth.Password, append(monitoringRoutes, deadlettermodule.RoutePrefix)...))
+	RegisterModules(r, cfg, pubsubClient, workers)
 	return r
 }
 
-func RegisterModules(r *gin.Engine, cfg *types.Config, pubsubClient interfaces.IPubSubClient) {
-	healthCheckModule := healthcheckmodule.NewHealthCheckModule()
+// monitoringRoutes são consultadas periodicamente pelo monitoramento: não exigem credenciais nem geram traces.
+var monitoringRoutes = []string{"/health", "/metrics"}
+
+// tracedRequest deixa de fora dos traces a rota "/" e as de monitoramento.
+func tracedRequest(r *http.Request) bool {
+	return r.URL.Path != "/" && !slices.Contains(monitoringRoutes, r.URL.Path)
+}
+
+// RegisterModules registra as rotas e inicia em workers as tarefas do modo configurado: o agendador
+// no publisher e os receptores das assinaturas no subscriber. O health check é registrado em todos os modos.
+func RegisterModules(r *gin.Engine, cfg *types.Config, pubsubClient interfaces.IPubSubClient, workers *Workers) {
+	var subscriptionMonitor interfaces.ISubscriptionMonitor = monitor.NewMonitor()
+	if runsSubscriber(cfg) {
+		subscriptionService := subscriptionservice.NewSubscriptionService(pubsubClient, cfg, NewHandlerMiddlewares(cfg)...)
+		subscriptionMonitor = subscriptionService.Monitor
+		workers.Go("assinaturas", subscriptionService.Listen)
+	}
+	healthCheckModule := healthcheckmodule.NewHealthCheckModule(subscriptionMonitor)
 	healthCheckModule.RegisterRoutes(r)
-	publishModule, err := publishmodule.NewPublishModule(pubsubClient)
+	if cfg.Metrics.Enabled {
+		metricsmodule.NewMetricsModule().RegisterRoutes(r)
+	}
+	if runsPublisher(cfg) {
+		registerPublisherModules(r, cfg, pubsubClient, workers)
+	}
+}
+
+func registerPublisherModules(r *gin.Engine, cfg *types.Config, pubsubClient interfaces.IPubSubClient, workers *Workers) {
+	scheduleModule := schedulemodule.NewScheduleModule(pubsubClient, NewScheduleStore(cfg), cfg.Scheduler.PollInterval)
+	scheduleModule.RegisterRoutes(r)
+	workers.Go("agendador", scheduleModule.Service.Run)
+	publishModule, err := publishmodule.NewPublishModule(pubsubClient, publishmodule.Options{
+		Scheduler:      scheduleModule.Service,
+		Idempotency:    NewIdempotencyStore(cfg),
+		IdempotencyTTL: cfg.Idempotency.TTL,
+		TopicRegistry:  NewTopicRegistry(cfg),
+	})
 	if err != nil {
-		log.Fatalf("Erro ao criar publish module: %v", err)
+		logging.Fatal("Erro ao criar publish module", "error", err)
 	}
 	publishModule.RegisterRoutes(r)
-	subscriptionService := subscriptionservice.NewSubscriptionService(pubsubClient, cfg)
-	go func() {
-		if err := subscriptionService.Listen(context.Background()); err != nil {
-			log.Fatalf("Erro no listener: %v", err)
+	// Sem credenciais próprias a API de dead-letter não é exposta; ValidateConfig já recusa essa configuração.
+	if admin := cfg.DeadLetter.AdminAuth; cfg.DeadLetter.Subscription != "" && admin.Username != "" && admin.Password != "" {
+		deadlettermodule.NewDeadLetterModule(pubsubClient, cfg).RegisterRoutes(r)
+	}
+}
+
+// NewScheduleStore usa o arquivo configurado para manter os agendamentos entre reinícios. LoadConfig
+// sempre preenche um caminho padrão; a memória fica para configurações montadas sem ele, como em testes.
+func NewScheduleStore(cfg *types.Config) interfaces.IScheduleStore {
+	if cfg.Scheduler.StoreFile == "" {
+		return schedulestore.NewMemoryStore()
+	}
+	store, err := schedulestore.NewFileStore(cfg.Scheduler.StoreFile)
+	if err != nil {
+		logging.Fatal("Erro ao abrir o store de agendamentos", "error", err)
+	}
+	return store
+}
+
+// NewIdempotencyStore segue a mesma regra do store de agendamentos: arquivo quando configurado, memória caso contrário.
+func NewIdempotencyStore(cfg *types.Config) interfaces.IIdempotencyStore {
+	if cfg.Idempotency.StoreFile == "" {
+		return idempotencystore.NewMemoryStore()
+	}
+	store, err := idempotencystore.NewFileStore(cfg.Idempotency.StoreFile)
+	if err != nil {
+		logging.Fatal("Erro ao abrir o store de idempotência", "error", err)
+	}
+	return store
+}
+
+// NewHandlerMiddlewares lista os middlewares aplicados aos handlers das assinaturas conforme a configuração.
+func NewHandlerMiddlewares(cfg *types.Config) []interfaces.EventHandlerMiddleware {
+	var middlewares []interfaces.EventHandlerMiddleware
+	if cfg.Dedup.Enabled {
+		middlewares = append(middlewares, dedup.Middleware(NewDedupStore(cfg), dedup.NewKeyFunc(cfg.Dedup.Key), cfg.Dedup.TTL))
+	}
+	// Por último, para medir e rastrear também os demais middlewares
+	return append(middlewares, metrics.Middleware, tracing.Middleware)
+}
+
+// NewDedupStore usa o arquivo quando configurado, para que as chaves sobrevivam a reinícios, e memória caso contrário.
+func NewDedupStore(cfg *types.Config) interfaces.IDedupStore {
+	if cfg.Dedup.StoreFile == "" {
+		return dedupstore.NewMemoryStore(cfg.Dedup.MaxEntries)
+	}
+	store, err := dedupstore.NewFileStore(cfg.Dedup.StoreFile)
+	if err != nil {
+		logging.Fatal("Erro ao abrir o store de deduplicação", "error", err)
+	}
+	return store
+}
+
+// NewTopicRegistry retorna nil quando nenhum registro é configurado, mantendo a publicação liberada para qualquer tópico.
+func NewTopicRegistry(cfg *types.Config) interfaces.ITopicRegistry {
+	if cfg.TopicRegistry.File == "" {
+		return nil
+	}
+	registry, err := topicregistry.LoadTopicRegistry(cfg.TopicRegistry.File)
+	if err != nil {
+		logging.Fatal("Erro ao carregar o registro de tópicos", "error", err)
+	}
+	return registry
+}
+
+func NewBrokerClient(cfg *types.Config) interfaces.IPubSubClient {
+	if cfg.Broker.Type == string(enum.MemoryBroker) {
+		broker, err := memorybroker.NewMemoryBrokerFromConfig(cfg.Broker)
+		if err != nil {
+			logging.Fatal("Erro ao criar o broker em memória", "error", err)
 		}
-	}()
+		slog.Info("Usando broker em memória", "subscriptions", len(cfg.Broker.Subscriptions))
+		return broker
+	}
+	adapter := pubsubadapter.NewPubSubClientAdapter(NewPubSubClient(cfg))
+	if cfg.Broker.AutoProvision {
+		if err := adapter.Provision(context.Background(), cfg.Broker); err != nil {
+			logging.Fatal("Erro ao provisionar tópicos e assinaturas", "error", err)
+		}
+	}
+	return adapter
 }
 
 func NewPubSubClient(cfg *types.Config) *pubsub.Client {
-	client, err := pubsub.NewClient(context.Background(), cfg.Google.ProjectID)
+	client, err := pubsub.NewClient(context.Background(), PubSubProjectID(cfg), PubSubClientOptions(cfg)...)
 	if err != nil {
-		log.Fatalf("Erro ao criar o pubsub: %v", err)
+		logging.Fatal("Erro ao criar o pubsub", "error", err)
 	}
 	return client
 }
 
+// PubSubProjectID usa um projeto local quando o emulador está configurado sem PROJECT_ID.
+func PubSubProjectID(cfg *types.Config) string {
+	if cfg.Google.ProjectID == "" && cfg.Google.EmulatorHost != "" {
+		return emulatorProjectID
+	}
+	return cfg.Google.ProjectID
+}
+
+func PubSubClientOptions(cfg *types.Config) []option.ClientOption {
+	if cfg.Google.EmulatorHost == "" {
+		return nil
+	}
+	slog.Info("Usando o emulador do Pub/Sub", "host", cfg.Google.EmulatorHost)
+	return []option.ClientOption{
+		option.WithEndpoint(cfg.Google.EmulatorHost),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	}
+}
+
 func StartServer(router *gin.Engine, port int) error {
+	server := NewHTTPServer(router, port)
+	slog.Info("Servidor HTTP iniciado", "addr", server.Addr)
+	return server.ListenAndServe()
+}
+
+func NewHTTPServer(router *gin.Engine, port int) *http.Server {
 	if port == 0 {
 		port = 3003
 	}
-	log.Printf("Server running on port %d", port)
-	return router.Run(fmt.Sprintf(":%d", port))
+	return &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: router}
 }
@@ -1,26 +1,42 @@
 package servers_test
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"queue/core/infra/config"
+	"queue/core/infra/dedup_store"
+	"queue/core/infra/idempotency_store"
+	"queue/core/infra/memory_broker"
 	"queue/core/infra/mock"
+	"queue/core/infra/schedule_store"
 	"queue/core/infra/servers"
+	"strings"
 	"testing"
+	"time"
 
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"queue/core/domain/enum"
 	"queue/core/domain/types"
 )
 
 func TestSetupRouter_HealthRouteAndAuth(t *testing.T) {
 	cfg := &types.Config{
+		CorsConfig: cors.Config{AllowAllOrigins: true},
 		Auth: types.BasicAuthConfig{
 			Username: "admin",
 			Password: "123",
 		},
 	}
 	pubsubMock := mock.NewMockPubSubClientAdapter()
-	router := servers.SetupRouter(cfg, pubsubMock)
+	router := servers.SetupRouter(cfg, pubsubMock, newWorkers(t))
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
 	req.SetBasicAuth("admin", "123")
@@ -29,6 +45,26 @@ func TestSetupRouter_HealthRouteAndAuth(t *testing.T) {
 	assert.Contains(t, w.Body.String(), "Queue on Air")
 }
 
+func TestSetupRouter_MonitoramentoSemAutenticacao(t *testing.T) {
+	cfg := &types.Config{
+		Mode:       "subscriber",
+		CorsConfig: cors.Config{AllowAllOrigins: true},
+		Auth:       types.BasicAuthConfig{Username: "admin", Password: "123"},
+		Metrics:    types.MetricsConfig{Enabled: true},
+	}
+	router := servers.SetupRouter(cfg, mock.NewMockPubSubClientAdapter(), newWorkers(t))
+	for _, path := range []string{"/health", "/metrics"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+
+	// As demais rotas continuam exigindo credenciais
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/publish", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
 func TestStartServer_DoesNotPanicOnPortZero(t *testing.T) {
 	router := gin.New()
 	go func() {
@@ -36,3 +72,341 @@ func TestStartServer_DoesNotPanicOnPortZero(t *testing.T) {
 	}()
 	assert.True(t, true)
 }
+
+func TestNewBrokerClient_Memory(t *testing.T) {
+	cfg := &types.Config{Broker: types.BrokerConfig{Type: "memory"}}
+	client := servers.NewBrokerClient(cfg)
+	assert.IsType(t, &memorybroker.MemoryBroker{}, client)
+}
+
+// newWorkers cria as tarefas em segundo plano e as encerra ao final do teste.
+func newWorkers(t *testing.T) *servers.Workers {
+	t.Helper()
+	workers := servers.NewWorkers(context.Background())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = workers.Stop(ctx)
+	})
+	return workers
+}
+
+// setupMemoryRouter sobe o router com broker em memória e uma API de notificação falsa que repassa os corpos recebidos.
+func setupMemoryRouter(t *testing.T, configure ...func(cfg *types.Config)) (*gin.Engine, <-chan []byte) {
+	t.Helper()
+	delivered := make(chan []byte, 2)
+	notificationAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		delivered <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(notificationAPI.Close)
+
+	cfg := config.LoadConfig()
+	cfg.Environment = "prod"
+	cfg.Auth = types.BasicAuthConfig{Username: "admin", Password: "123"}
+	cfg.URLs.Notification = notificationAPI.URL
+	cfg.Scheduler.StoreFile = ""
+	cfg.Broker = types.BrokerConfig{
+		Type:          "memory",
+		Subscriptions: []types.SubscriptionConfig{{ID: "notifications-sub", Topic: "notifications"}},
+	}
+	for _, fn := range configure {
+		fn(cfg)
+	}
+	client := servers.NewBrokerClient(cfg)
+	t.Cleanup(func() { client.Close() })
+	return servers.SetupRouter(cfg, client, newWorkers(t)), delivered
+}
+
+func assertDelivered(t *testing.T, delivered <-chan []byte, want string) {
+	t.Helper()
+	select {
+	case got := <-delivered:
+		assert.Contains(t, string(got), want)
+	case <-time.After(2 * time.Second):
+		t.Fatal("notificação não foi entregue ao handler")
+	}
+}
+
+func TestPublish_EntregaAoHandlerComBrokerEmMemoria(t *testing.T) {
+	router, delivered := setupMemoryRouter(t)
+
+	body := `{"meta":{"topic":"notifications"},"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(body))
+	req.SetBasicAuth("admin", "123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertDelivered(t, delivered, `"recipient":"ada@example.com"`)
+}
+
+func TestPublish_RequestIDChegaAAPIDeNotificacao(t *testing.T) {
+	requestIDs := make(chan string, 1)
+	notificationAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs <- r.Header.Get("X-Request-ID")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(notificationAPI.Close)
+	router, _ := setupMemoryRouter(t, func(cfg *types.Config) {
+		cfg.URLs.Notification = notificationAPI.URL
+	})
+
+	body := `{"meta":{"topic":"notifications"},"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(body))
+	req.SetBasicAuth("admin", "123")
+	req.Header.Set("X-Request-ID", "req-e2e")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "req-e2e", w.Header().Get("X-Request-ID"))
+	select {
+	case got := <-requestIDs:
+		assert.Equal(t, "req-e2e", got)
+	case <-time.After(2 * time.Second):
+		t.Fatal("notificação não foi entregue ao handler")
+	}
+}
+
+func TestPublish_CloudEventEntregaAoHandler(t *testing.T) {
+	router, delivered := setupMemoryRouter(t)
+
+	structured := `{"specversion":"1.0","id":"evt-1","source":"/crm","type":"notifications","datacontenttype":"application/json",
+		"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(structured))
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	req.SetBasicAuth("admin", "123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"topic":"notifications"`)
+	assertDelivered(t, delivered, `"recipient":"ada@example.com"`)
+
+	binary := `{"userId":2,"userName":"Grace","channel":"EMAIL","recipient":"grace@example.com","payload":{"html":"<p>oi</p>"}}`
+	req = httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(binary))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "evt-2")
+	req.Header.Set("Ce-Source", "/crm")
+	req.Header.Set("Ce-Type", "com.acme.user.created")
+	req.Header.Set("Ce-Topic", "notifications")
+	req.SetBasicAuth("admin", "123")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertDelivered(t, delivered, `"recipient":"grace@example.com"`)
+}
+
+func TestPublish_DedupIgnoraEventoRepetido(t *testing.T) {
+	router, delivered := setupMemoryRouter(t, func(cfg *types.Config) {
+		cfg.Dedup = types.DedupConfig{Enabled: true, TTL: time.Hour}
+	})
+
+	structured := `{"specversion":"1.0","id":"evt-1","source":"/crm","type":"notifications","datacontenttype":"application/json",
+		"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(structured))
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+		req.SetBasicAuth("admin", "123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assertDelivered(t, delivered, `"recipient":"ada@example.com"`)
+	select {
+	case <-delivered:
+		t.Fatal("evento repetido não deveria ser entregue novamente")
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestSetupRouter_HealthReportaAssinaturas(t *testing.T) {
+	router, _ := setupMemoryRouter(t)
+
+	assert.Eventually(t, func() bool {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.SetBasicAuth("admin", "123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code == http.StatusOK && strings.Contains(w.Body.String(), `"id":"notifications-sub","state":"running"`)
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func serve(router *gin.Engine, method string, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(`{}`))
+	req.SetBasicAuth("admin", "123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSetupRouter_ModoPublisher(t *testing.T) {
+	router, _ := setupMemoryRouter(t, func(cfg *types.Config) {
+		cfg.Mode = string(enum.PublisherMode)
+	})
+
+	assert.NotEqual(t, http.StatusNotFound, serve(router, http.MethodPost, "/publish").Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/publish/scheduled").Code)
+	// Sem receptores, o health não lista assinaturas
+	time.Sleep(50 * time.Millisecond)
+	assert.Contains(t, serve(router, http.MethodGet, "/health").Body.String(), `"subscriptions":[]`)
+}
+
+func TestSetupRouter_ModoSubscriber(t *testing.T) {
+	router, _ := setupMemoryRouter(t, func(cfg *types.Config) {
+		cfg.Mode = string(enum.SubscriberMode)
+	})
+
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodPost, "/publish").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/publish/scheduled").Code)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(serve(router, http.MethodGet, "/health").Body.String(), `"id":"notifications-sub","state":"running"`)
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestSetupRouter_Metrics(t *testing.T) {
+	router, delivered := setupMemoryRouter(t)
+
+	body := `{"meta":{"topic":"notifications"},"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(body))
+	req.SetBasicAuth("admin", "123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assertDelivered(t, delivered, `"recipient":"ada@example.com"`)
+
+	series := []string{
+		`queue_publish_requests_total{outcome="published",topic="notifications"}`,
+		`queue_messages_received_total{subscription="notifications-sub"}`,
+		`queue_messages_processed_total{outcome="acked",subscription="notifications-sub"}`,
+		`queue_handler_calls_total{handler="notification",outcome="success",subscription="notifications-sub"}`,
+		`queue_notification_requests_total{status_code="200"}`,
+	}
+	assert.Eventually(t, func() bool {
+		metrics := serve(router, http.MethodGet, "/metrics").Body.String()
+		for _, s := range series {
+			if !strings.Contains(metrics, s) {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestSetupRouter_MetricsDesabilitado(t *testing.T) {
+	router, _ := setupMemoryRouter(t, func(cfg *types.Config) {
+		cfg.Metrics.Enabled = false
+	})
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodGet, "/metrics").Code)
+}
+
+func TestNewScheduleStore(t *testing.T) {
+	assert.IsType(t, &schedulestore.MemoryStore{}, servers.NewScheduleStore(&types.Config{}))
+
+	cfg := &types.Config{Scheduler: types.SchedulerConfig{StoreFile: filepath.Join(t.TempDir(), "scheduled.json")}}
+	assert.IsType(t, &schedulestore.FileStore{}, servers.NewScheduleStore(cfg))
+}
+
+func TestNewIdempotencyStore(t *testing.T) {
+	assert.IsType(t, &idempotencystore.MemoryStore{}, servers.NewIdempotencyStore(&types.Config{}))
+
+	cfg := &types.Config{Idempotency: types.IdempotencyConfig{StoreFile: filepath.Join(t.TempDir(), "idempotency.json")}}
+	assert.IsType(t, &idempotencystore.FileStore{}, servers.NewIdempotencyStore(cfg))
+}
+
+func TestNewDedupStore(t *testing.T) {
+	assert.IsType(t, &dedupstore.MemoryStore{}, servers.NewDedupStore(&types.Config{}))
+
+	cfg := &types.Config{Dedup: types.DedupConfig{StoreFile: filepath.Join(t.TempDir(), "dedup.jsonl")}}
+	assert.IsType(t, &dedupstore.FileStore{}, servers.NewDedupStore(cfg))
+}
+
+func TestNewHandlerMiddlewares(t *testing.T) {
+	assert.Len(t, servers.NewHandlerMiddlewares(&types.Config{}), 2)
+	assert.Len(t, servers.NewHandlerMiddlewares(&types.Config{Dedup: types.DedupConfig{Enabled: true}}), 3)
+}
+
+func TestNewTopicRegistry(t *testing.T) {
+	assert.Nil(t, servers.NewTopicRegistry(&types.Config{}))
+
+	path := filepath.Join(t.TempDir(), "topics.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"topics": [{"name": "notifications"}]}`), 0o600))
+	registry := servers.NewTopicRegistry(&types.Config{TopicRegistry: types.TopicRegistryConfig{File: path}})
+	assert.NoError(t, registry.Validate("notifications", nil))
+	assert.Error(t, registry.Validate("outro", nil))
+}
+
+func TestPubSubClientOptions(t *testing.T) {
+	cfg := &types.Config{}
+	assert.Empty(t, servers.PubSubClientOptions(cfg))
+
+	cfg.Google.EmulatorHost = "localhost:8085"
+	assert.NotEmpty(t, servers.PubSubClientOptions(cfg))
+}
+
+func TestPubSubProjectID(t *testing.T) {
+	assert.Equal(t, "projeto", servers.PubSubProjectID(&types.Config{Google: types.GoogleConfig{ProjectID: "projeto"}}))
+	assert.Equal(t, "local-project", servers.PubSubProjectID(&types.Config{Google: types.GoogleConfig{EmulatorHost: "localhost:8085"}}))
+}
+
+func TestNewBrokerClient_EmuladorComProvisionamento(t *testing.T) {
+	srv := pstest.NewServer()
+	defer srv.Close()
+	cfg := &types.Config{
+		Google: types.GoogleConfig{EmulatorHost: srv.Addr},
+		Broker: types.BrokerConfig{
+			Type:          "pubsub",
+			AutoProvision: true,
+			Subscriptions: []types.SubscriptionConfig{{ID: "notifications-sub", Topic: "notifications"}},
+		},
+	}
+	client := servers.NewBrokerClient(cfg)
+	defer client.Close()
+
+	sub, err := client.Subscriptions(context.Background()).Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "notifications-sub", sub.ID())
+}
+
+func TestSetupRouter_DeadLetterSomenteQuandoConfigurado(t *testing.T) {
+	cfg := &types.Config{
+		CorsConfig: cors.Config{AllowAllOrigins: true},
+		Auth:       types.BasicAuthConfig{Username: "admin", Password: "123"},
+	}
+	request := func(router *gin.Engine) int {
+		req := httptest.NewRequest(http.MethodPost, "/admin/dead-letter/purge", bytes.NewBufferString(`{}`))
+		req.SetBasicAuth("admin", "123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusNotFound, request(servers.SetupRouter(cfg, mock.NewMockPubSubClientAdapter(), newWorkers(t))))
+
+	// Sem credenciais de administração as rotas continuam fora do ar
+	cfg.DeadLetter = types.DeadLetterConfig{Topic: "dlq", Subscription: "dlq-sub"}
+	assert.Equal(t, http.StatusNotFound, request(servers.SetupRouter(cfg, mock.NewMockPubSubClientAdapter(), newWorkers(t))))
+
+	cfg.DeadLetter.AdminAuth = types.BasicAuthConfig{Username: "ops", Password: "456"}
+	assert.Equal(t, http.StatusUnauthorized, request(servers.SetupRouter(cfg, mock.NewMockPubSubClientAdapter(), newWorkers(t))))
+}
+
+func TestSetupRouter_DeadLetterUsaCredenciaisDeAdministracao(t *testing.T) {
+	cfg := &types.Config{
+		CorsConfig: cors.Config{AllowAllOrigins: true},
+		Auth:       types.BasicAuthConfig{Username: "admin", Password: "123"},
+		DeadLetter: types.DeadLetterConfig{
+			Topic:        "dlq",
+			Subscription: "dlq-sub",
+			AdminAuth:    types.BasicAuthConfig{Username: "ops", Password: "456"},
+		},
+	}
+	router := servers.SetupRouter(cfg, mock.NewMockPubSubClientAdapter(), newWorkers(t))
+	request := func(path, username, password string) int {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(`{}`))
+		req.SetBasicAuth(username, password)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusBadRequest, request("/admin/dead-letter/purge", "ops", "456"))
+	assert.Equal(t, http.StatusUnauthorized, request("/admin/dead-letter/purge", "admin", "123"))
+	assert.Equal(t, http.StatusUnauthorized, request("/publish", "ops", "456"))
+}
@@ -0,0 +1,50 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"queue/core/domain/interfaces"
+	"time"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Shutdown encerra o processo na ordem segura: para de aceitar requisições HTTP e aguarda as
+// em andamento, cancela os receptores e espera as mensagens em processamento, e por fim envia as
+// publicações pendentes fechando o broker. Todas as etapas compartilham o mesmo prazo.
+func Shutdown(server *http.Server, workers *Workers, client interfaces.IPubSubClient, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Warn("Requisições HTTP não finalizadas dentro do prazo", "error", err)
+			errs = append(errs, err)
+		} else {
+			slog.Info("Servidor HTTP encerrado e requisições em andamento finalizadas")
+		}
+	}
+	if workers != nil {
+		if err := workers.Stop(ctx); err != nil {
+			slog.Warn("Mensagens em processamento não finalizadas dentro do prazo", "error", err)
+			errs = append(errs, err)
+		} else {
+			slog.Info("Receptores e agendador encerrados sem mensagens pendentes")
+		}
+	}
+	if client != nil {
+		if err := client.Close(); err != nil {
+			slog.Error("Erro ao fechar o broker", "error", err)
+			errs = append(errs, err)
+		} else {
+			slog.Info("Conexão com o broker encerrada")
+		}
+	}
+	return errors.Join(errs...)
+}
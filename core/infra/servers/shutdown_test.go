@@ -0,0 +1,69 @@
+package servers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"queue/core/domain/types"
+	"queue/core/infra/config"
+	"queue/core/infra/servers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown_AguardaMensagemEmProcessamento(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	completed := make(chan struct{}, 1)
+	notificationAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		completed <- struct{}{}
+	}))
+	defer notificationAPI.Close()
+
+	cfg := config.LoadConfig()
+	cfg.Environment = "prod"
+	cfg.Auth = types.BasicAuthConfig{Username: "admin", Password: "123"}
+	cfg.URLs.Notification = notificationAPI.URL
+	cfg.Scheduler.StoreFile = ""
+	cfg.Broker = types.BrokerConfig{
+		Type:          "memory",
+		Subscriptions: []types.SubscriptionConfig{{ID: "notifications-sub", Topic: "notifications"}},
+	}
+	client := servers.NewBrokerClient(cfg)
+	workers := servers.NewWorkers(t.Context())
+	router := servers.SetupRouter(cfg, client, workers)
+
+	body := `{"meta":{"topic":"notifications"},"data":{"userId":1,"userName":"Ada","channel":"EMAIL","recipient":"ada@example.com","payload":{"html":"<p>oi</p>"}}}`
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewBufferString(body))
+	req.SetBasicAuth("admin", "123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notificação não chegou à API")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- servers.Shutdown(servers.NewHTTPServer(router, 0), workers, client, 2*time.Second) }()
+	select {
+	case <-done:
+		t.Fatal("Shutdown não deveria terminar com mensagem em processamento")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-done)
+	select {
+	case <-completed:
+	default:
+		t.Fatal("a notificação em andamento deveria ter sido concluída")
+	}
+}
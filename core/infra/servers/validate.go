@@ -0,0 +1,94 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"queue/core/domain/enum"
+	"queue/core/domain/strategy"
+	"queue/core/domain/strategy/handlers"
+	"queue/core/domain/types"
+)
+
+// RunMode normaliza o modo configurado, tratando o vazio como all.
+func RunMode(cfg *types.Config) enum.RunModeEnum {
+	if cfg.Mode == "" {
+		return enum.AllMode
+	}
+	return enum.RunModeEnum(cfg.Mode)
+}
+
+func runsPublisher(cfg *types.Config) bool {
+	mode := RunMode(cfg)
+	return mode == enum.AllMode || mode == enum.PublisherMode
+}
+
+func runsSubscriber(cfg *types.Config) bool {
+	mode := RunMode(cfg)
+	return mode == enum.AllMode || mode == enum.SubscriberMode
+}
+
+// ValidateConfig confere apenas a configuração exigida pelo modo de execução, para que um
+// publisher não dependa da API de notificações nem um subscriber das credenciais da API.
+func ValidateConfig(cfg *types.Config) error {
+	var errs []error
+	switch RunMode(cfg) {
+	case enum.AllMode, enum.PublisherMode, enum.SubscriberMode:
+	default:
+		return fmt.Errorf("modo de execução %q inválido, use publisher, subscriber ou all", cfg.Mode)
+	}
+	switch enum.TracingExporterEnum(cfg.Tracing.Exporter) {
+	case "", enum.NoTracingExporter, enum.StdoutTracingExporter, enum.OTLPTracingExporter:
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER %q inválido, use none, stdout ou otlp", cfg.Tracing.Exporter))
+	}
+	switch enum.LogFormatEnum(cfg.Log.Format) {
+	case "", enum.JSONLogFormat, enum.TextLogFormat:
+	default:
+		errs = append(errs, fmt.Errorf("LOG_FORMAT %q inválido, use json ou text", cfg.Log.Format))
+	}
+	if cfg.Broker.LoadErr != nil {
+		errs = append(errs, cfg.Broker.LoadErr)
+	}
+	if cfg.Handlers.LoadErr != nil {
+		errs = append(errs, cfg.Handlers.LoadErr)
+	}
+	if cfg.Broker.Type != string(enum.MemoryBroker) && PubSubProjectID(cfg) == "" {
+		errs = append(errs, errors.New("PROJECT_ID é obrigatório para o broker pubsub"))
+	}
+	if runsPublisher(cfg) && (cfg.Auth.Username == "" || cfg.Auth.Password == "") {
+		errs = append(errs, errors.New("BASIC_AUTH_USERNAME e BASIC_AUTH_PASSWORD são obrigatórios para a API de publicação"))
+	}
+	if runsPublisher(cfg) && cfg.DeadLetter.Subscription != "" && (cfg.DeadLetter.AdminAuth.Username == "" || cfg.DeadLetter.AdminAuth.Password == "") {
+		errs = append(errs, errors.New("DEAD_LETTER_ADMIN_USERNAME e DEAD_LETTER_ADMIN_PASSWORD são obrigatórios para a API de dead-letter"))
+	}
+	if runsSubscriber(cfg) && usesHandler(cfg, handlers.NotificationHandlerName) {
+		if cfg.URLs.Notification == "" {
+			errs = append(errs, errors.New("NOTIFICATION_URL é obrigatório para o handler de notificações"))
+		}
+		// O handler se autentica na API de notificações com as mesmas credenciais da API de publicação
+		if !runsPublisher(cfg) && (cfg.Auth.Username == "" || cfg.Auth.Password == "") {
+			errs = append(errs, errors.New("BASIC_AUTH_USERNAME e BASIC_AUTH_PASSWORD são obrigatórios para o handler de notificações"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// usesHandler considera o mapeamento padrão quando nenhum arquivo de handlers é configurado e
+// ignora as assinaturas que não são consumidas no ambiente atual.
+func usesHandler(cfg *types.Config, name string) bool {
+	handlersCfg := cfg.Handlers
+	if len(handlersCfg.Subscriptions) == 0 {
+		handlersCfg = strategy.DefaultHandlersConfig
+	}
+	for _, sub := range handlersCfg.Subscriptions {
+		if _, ok := strategy.ResolveSubscriptionID(sub.ID, cfg.Environment, handlersCfg.EnvironmentPrefixes); !ok {
+			continue
+		}
+		for _, binding := range sub.Handlers {
+			if binding.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package servers_test
+
+import (
+	"errors"
+	"queue/core/domain/enum"
+	"queue/core/domain/types"
+	"queue/core/infra/servers"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig(mode string) *types.Config {
+	return &types.Config{
+		Mode:        mode,
+		Environment: "prod",
+		Auth:        types.BasicAuthConfig{Username: "admin", Password: "123"},
+		Google:      types.GoogleConfig{ProjectID: "projeto"},
+		Broker:      types.BrokerConfig{Type: string(enum.PubSubBroker)},
+		URLs:        types.URLsConfig{Notification: "http://notification"},
+	}
+}
+
+func TestRunMode(t *testing.T) {
+	assert.Equal(t, enum.AllMode, servers.RunMode(&types.Config{}))
+	assert.Equal(t, enum.PublisherMode, servers.RunMode(&types.Config{Mode: "publisher"}))
+}
+
+func TestValidateConfig_Valida(t *testing.T) {
+	for _, mode := range []string{"", "all", "publisher", "subscriber"} {
+		assert.NoError(t, servers.ValidateConfig(validConfig(mode)), mode)
+	}
+}
+
+func TestValidateConfig_ModoInvalido(t *testing.T) {
+	assert.ErrorContains(t, servers.ValidateConfig(validConfig("worker")), `"worker"`)
+}
+
+func TestValidateConfig_PublisherNaoExigeNotificacao(t *testing.T) {
+	cfg := validConfig("publisher")
+	cfg.URLs.Notification = ""
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	cfg.Auth = types.BasicAuthConfig{}
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "BASIC_AUTH_USERNAME")
+}
+
+func TestValidateConfig_SubscriberExigeCredenciaisSoComNotificacao(t *testing.T) {
+	cfg := validConfig("subscriber")
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	cfg.Auth = types.BasicAuthConfig{}
+	cfg.URLs.Notification = ""
+	err := servers.ValidateConfig(cfg)
+	assert.ErrorContains(t, err, "NOTIFICATION_URL")
+	assert.ErrorContains(t, err, "BASIC_AUTH_USERNAME e BASIC_AUTH_PASSWORD são obrigatórios para o handler de notificações")
+
+	// Sem o handler de notificações nas assinaturas nem a URL nem as credenciais são necessárias
+	cfg.Handlers = types.HandlersConfig{Subscriptions: []types.SubscriptionHandlers{
+		{ID: "audit-sub", Handlers: []types.HandlerBinding{{Name: "audit"}}},
+	}}
+	assert.NoError(t, servers.ValidateConfig(cfg))
+}
+
+func TestValidateConfig_Tracing(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.Tracing.Exporter = "otlp"
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	cfg.Tracing.Exporter = "jaeger"
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "TRACING_EXPORTER")
+}
+
+func TestValidateConfig_ArquivoDeTopologia(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.Broker.LoadErr = errors.New("erro ao carregar BROKER_TOPOLOGY_FILE topology.json")
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "BROKER_TOPOLOGY_FILE")
+}
+
+func TestValidateConfig_ArquivoDeHandlers(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.Handlers.LoadErr = errors.New("erro ao carregar SUBSCRIPTION_HANDLERS_FILE handlers.json")
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "SUBSCRIPTION_HANDLERS_FILE")
+}
+
+func TestValidateConfig_NotificacaoSoNoAmbienteConsumido(t *testing.T) {
+	cfg := validConfig("subscriber")
+	cfg.URLs.Notification = ""
+	cfg.Environment = ""
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	cfg.Environment = "hml"
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "NOTIFICATION_URL")
+}
+
+func TestValidateConfig_Log(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.Log.Format = "json"
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	cfg.Log.Format = "xml"
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "LOG_FORMAT")
+}
+
+func TestValidateConfig_Broker(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.Google = types.GoogleConfig{}
+	cfg.Auth = types.BasicAuthConfig{}
+	err := servers.ValidateConfig(cfg)
+	assert.ErrorContains(t, err, "PROJECT_ID")
+	assert.ErrorContains(t, err, "BASIC_AUTH_USERNAME")
+
+	cfg.Google.EmulatorHost = "localhost:8085"
+	assert.NotContains(t, servers.ValidateConfig(cfg).Error(), "PROJECT_ID")
+
+	cfg = validConfig("all")
+	cfg.Google = types.GoogleConfig{}
+	cfg.Broker.Type = string(enum.MemoryBroker)
+	assert.NoError(t, servers.ValidateConfig(cfg))
+}
+
+func TestValidateConfig_CredenciaisDoDeadLetter(t *testing.T) {
+	cfg := validConfig("all")
+	cfg.DeadLetter = types.DeadLetterConfig{Topic: "dlq", Subscription: "dlq-sub"}
+	assert.ErrorContains(t, servers.ValidateConfig(cfg), "DEAD_LETTER_ADMIN_USERNAME")
+
+	cfg.DeadLetter.AdminAuth = types.BasicAuthConfig{Username: "ops", Password: "456"}
+	assert.NoError(t, servers.ValidateConfig(cfg))
+
+	// Sem a API de publicação as rotas de dead-letter não são registradas
+	cfg = validConfig("subscriber")
+	cfg.DeadLetter = types.DeadLetterConfig{Topic: "dlq", Subscription: "dlq-sub"}
+	assert.NoError(t, servers.ValidateConfig(cfg))
+}
@@ -0,0 +1,76 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Workers acompanha as tarefas em segundo plano (agendador e assinaturas) para que o desligamento
+// cancele o contexto delas e aguarde o término antes de fechar o broker.
+type Workers struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	running map[string]int
+}
+
+func NewWorkers(parent context.Context) *Workers {
+	ctx, cancel := context.WithCancel(parent)
+	return &Workers{ctx: ctx, cancel: cancel, running: map[string]int{}}
+}
+
+// Go executa fn em uma goroutine com o contexto das tarefas, identificada por name nos logs.
+func (w *Workers) Go(name string, fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	w.mu.Lock()
+	w.running[name]++
+	w.mu.Unlock()
+	go func() {
+		defer w.wg.Done()
+		defer w.finish(name)
+		fn(w.ctx)
+	}()
+}
+
+func (w *Workers) finish(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running[name]--
+	if w.running[name] == 0 {
+		delete(w.running, name)
+	}
+	slog.Info("Tarefa em segundo plano finalizada", "worker", name)
+}
+
+// Stop cancela as tarefas e aguarda o término delas até o prazo do contexto.
+// Retorna erro com as tarefas que ainda estavam em execução quando o prazo expirou.
+func (w *Workers) Stop(ctx context.Context) error {
+	w.cancel()
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tarefas ainda em execução: %s", strings.Join(w.pending(), ", "))
+	}
+}
+
+func (w *Workers) pending() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	names := make([]string, 0, len(w.running))
+	for name := range w.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
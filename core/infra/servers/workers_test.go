@@ -0,0 +1,41 @@
+package servers_test
+
+import (
+	"context"
+	"queue/core/infra/servers"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers_StopCancelaEAguarda(t *testing.T) {
+	workers := servers.NewWorkers(context.Background())
+	finished := make(chan struct{})
+	workers.Go("tarefa", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		close(finished)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, workers.Stop(ctx))
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Stop deveria aguardar o término da tarefa")
+	}
+}
+
+func TestWorkers_StopRespeitaPrazo(t *testing.T) {
+	workers := servers.NewWorkers(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	workers.Go("presa", func(ctx context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := workers.Stop(ctx)
+	assert.ErrorContains(t, err, "presa")
+}
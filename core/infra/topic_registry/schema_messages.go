@@ -0,0 +1,79 @@
+package topicregistry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+)
+
+// SchemaMessages converte os erros do JSON Schema no mesmo formato de validation.ValidationMessages:
+// caminho do campo em notação de ponto (prefixado por "data") para a mensagem.
+func SchemaMessages(ve *jsonschema.ValidationError) map[string]string {
+	msgs := map[string]string{}
+	collectSchemaMessages(ve, msgs)
+	return msgs
+}
+
+func collectSchemaMessages(ve *jsonschema.ValidationError, msgs map[string]string) {
+	if len(ve.Causes) > 0 {
+		for _, cause := range ve.Causes {
+			collectSchemaMessages(cause, msgs)
+		}
+		return
+	}
+	field := fieldPath(ve.InstanceLocation)
+	switch k := ve.ErrorKind.(type) {
+	case *kind.Required:
+		for _, name := range k.Missing {
+			child := field + "." + name
+			msgs[child] = fmt.Sprintf("O campo %s é obrigatório.", child)
+		}
+	case *kind.AdditionalProperties:
+		for _, name := range k.Properties {
+			child := field + "." + name
+			msgs[child] = fmt.Sprintf("O campo %s não é permitido.", child)
+		}
+	case *kind.Type:
+		msgs[field] = fmt.Sprintf("O campo %s deve ser do tipo %s.", field, strings.Join(k.Want, " ou "))
+	case *kind.Enum, *kind.Const:
+		msgs[field] = fmt.Sprintf("O campo %s possui um valor não permitido.", field)
+	case *kind.MinLength:
+		msgs[field] = fmt.Sprintf("O campo %s deve ter no mínimo %d caracteres.", field, k.Want)
+	case *kind.MaxLength:
+		msgs[field] = fmt.Sprintf("O campo %s deve ter no máximo %d caracteres.", field, k.Want)
+	case *kind.Minimum:
+		msgs[field] = fmt.Sprintf("O campo %s deve ser maior ou igual a %s.", field, k.Want.RatString())
+	case *kind.Maximum:
+		msgs[field] = fmt.Sprintf("O campo %s deve ser menor ou igual a %s.", field, k.Want.RatString())
+	case *kind.Pattern:
+		msgs[field] = fmt.Sprintf("O campo %s não está no formato esperado.", field)
+	case *kind.Format:
+		msgs[field] = fmt.Sprintf("O campo %s deve estar no formato %s.", field, k.Want)
+	case *kind.MinItems:
+		msgs[field] = fmt.Sprintf("O campo %s deve ter no mínimo %d itens.", field, k.Want)
+	case *kind.MaxItems:
+		msgs[field] = fmt.Sprintf("O campo %s deve ter no máximo %d itens.", field, k.Want)
+	default:
+		msgs[field] = fmt.Sprintf("O campo %s é inválido.", field)
+	}
+}
+
+func fieldPath(location []string) string {
+	return strings.Join(append([]string{"data"}, location...), ".")
+}
+
+func sortedMessages(msgs map[string]string) []string {
+	fields := make([]string, 0, len(msgs))
+	for field := range msgs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	out := make([]string, len(fields))
+	for i, field := range fields {
+		out[i] = msgs[field]
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package topicregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"queue/core/domain/structs"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// TopicDefinition descreve um tópico publicável e, opcionalmente, o JSON Schema do seu campo data.
+// O schema pode vir inline ou de um arquivo relativo ao arquivo do registro.
+type TopicDefinition struct {
+	Name       string          `json:"name"`
+	Schema     json.RawMessage `json:"schema,omitempty"`
+	SchemaFile string          `json:"schemaFile,omitempty"`
+}
+
+type registryFile struct {
+	Topics []TopicDefinition `json:"topics"`
+}
+
+// TopicRegistry implementa interfaces.ITopicRegistry. Tópicos sem schema aceitam qualquer data.
+type TopicRegistry struct {
+	schemas map[string]*jsonschema.Schema
+}
+
+func NewTopicRegistry(definitions []TopicDefinition) (*TopicRegistry, error) {
+	return newTopicRegistry(definitions, "")
+}
+
+// LoadTopicRegistry lê o registro de tópicos de um arquivo JSON no formato {"topics": [...]}.
+func LoadTopicRegistry(path string) (*TopicRegistry, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler registro de tópicos: %w", err)
+	}
+	var file registryFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("registro de tópicos inválido: %w", err)
+	}
+	return newTopicRegistry(file.Topics, filepath.Dir(path))
+}
+
+func newTopicRegistry(definitions []TopicDefinition, baseDir string) (*TopicRegistry, error) {
+	registry := &TopicRegistry{schemas: map[string]*jsonschema.Schema{}}
+	compiler := jsonschema.NewCompiler()
+	for _, def := range definitions {
+		if def.Name == "" {
+			return nil, fmt.Errorf("registro de tópicos inválido: tópico sem nome")
+		}
+		if _, ok := registry.schemas[def.Name]; ok {
+			return nil, fmt.Errorf("tópico %s declarado mais de uma vez", def.Name)
+		}
+		raw := def.Schema
+		if def.SchemaFile != "" {
+			path := def.SchemaFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(baseDir, path)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("erro ao ler schema do tópico %s: %w", def.Name, err)
+			}
+			raw = content
+		}
+		if len(raw) == 0 {
+			registry.schemas[def.Name] = nil
+			continue
+		}
+		schema, err := compileSchema(compiler, def.Name, raw)
+		if err != nil {
+			return nil, err
+		}
+		registry.schemas[def.Name] = schema
+	}
+	return registry, nil
+}
+
+func compileSchema(compiler *jsonschema.Compiler, topic string, raw []byte) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("schema do tópico %s inválido: %w", topic, err)
+	}
+	url := "topics/" + topic + ".json"
+	if err := compiler.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("schema do tópico %s inválido: %w", topic, err)
+	}
+	schema, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("schema do tópico %s inválido: %w", topic, err)
+	}
+	return schema, nil
+}
+
+// Has informa se o tópico está registrado, independentemente do data.
+func (r *TopicRegistry) Has(topic string) bool {
+	_, ok := r.schemas[topic]
+	return ok
+}
+
+// Validate retorna *structs.ValidationMessagesError quando o tópico não está registrado ou o data não atende ao schema.
+func (r *TopicRegistry) Validate(topic string, data interface{}) error {
+	schema, ok := r.schemas[topic]
+	if !ok {
+		return &structs.ValidationMessagesError{
+			Messages: []string{fmt.Sprintf("O tópico %s não está habilitado para publicação.", topic)},
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	err = schema.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	return &structs.ValidationMessagesError{Messages: sortedMessages(SchemaMessages(ve))}
+}
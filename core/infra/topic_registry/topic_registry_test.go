@@ -0,0 +1,110 @@
+package topicregistry_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"queue/core/domain/structs"
+	"queue/core/infra/topic_registry"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const notificationSchema = `{
+	"type": "object",
+	"required": ["email", "template"],
+	"properties": {
+		"email": {"type": "string", "minLength": 3},
+		"template": {"enum": ["welcome", "reset"]},
+		"retries": {"type": "integer", "minimum": 0}
+	},
+	"additionalProperties": false
+}`
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("erro ao escrever arquivo: %v", err)
+	}
+	return path
+}
+
+func validationMessages(t *testing.T, err error) []string {
+	t.Helper()
+	var ve *structs.ValidationMessagesError
+	if !errors.As(err, &ve) {
+		t.Fatalf("esperava ValidationMessagesError, recebeu %v", err)
+	}
+	return ve.Messages
+}
+
+func TestTopicRegistry_TopicoNaoRegistrado(t *testing.T) {
+	registry, err := topicregistry.NewTopicRegistry([]topicregistry.TopicDefinition{{Name: "audit"}})
+	assert.NoError(t, err)
+
+	assert.NoError(t, registry.Validate("audit", map[string]any{"qualquer": "coisa"}))
+	assert.Equal(t, []string{"O tópico outro não está habilitado para publicação."}, validationMessages(t, registry.Validate("outro", nil)))
+	assert.True(t, registry.Has("audit"))
+	assert.False(t, registry.Has("outro"))
+}
+
+func TestTopicRegistry_SchemaInline(t *testing.T) {
+	registry, err := topicregistry.NewTopicRegistry([]topicregistry.TopicDefinition{
+		{Name: "notifications", Schema: json.RawMessage(notificationSchema)},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, registry.Validate("notifications", map[string]any{"email": "a@b.c", "template": "welcome"}))
+
+	err = registry.Validate("notifications", map[string]any{"template": "outro", "retries": -1.0, "extra": true})
+	assert.Equal(t, []string{
+		"O campo data.email é obrigatório.",
+		"O campo data.extra não é permitido.",
+		"O campo data.retries deve ser maior ou igual a 0.",
+		"O campo data.template possui um valor não permitido.",
+	}, validationMessages(t, err))
+
+	err = registry.Validate("notifications", "texto")
+	assert.Equal(t, []string{"O campo data deve ser do tipo object."}, validationMessages(t, err))
+}
+
+func TestLoadTopicRegistry_SchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "schemas"), 0o755))
+	writeFile(t, filepath.Join(dir, "schemas"), "notifications.json", notificationSchema)
+	path := writeFile(t, dir, "topics.json", `{
+		"topics": [
+			{"name": "notifications", "schemaFile": "schemas/notifications.json"},
+			{"name": "audit"}
+		]
+	}`)
+
+	registry, err := topicregistry.LoadTopicRegistry(path)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Validate("audit", nil))
+	err = registry.Validate("notifications", map[string]any{"email": "ab", "template": "reset"})
+	assert.Equal(t, []string{"O campo data.email deve ter no mínimo 3 caracteres."}, validationMessages(t, err))
+}
+
+func TestLoadTopicRegistry_Invalido(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"json":       `{`,
+		"sem nome":   `{"topics": [{"schema": {}}]}`,
+		"duplicado":  `{"topics": [{"name": "a"}, {"name": "a"}]}`,
+		"schema":     `{"topics": [{"name": "a", "schema": {"type": 1}}]}`,
+		"sem schema": `{"topics": [{"name": "a", "schemaFile": "nao-existe.json"}]}`,
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := topicregistry.LoadTopicRegistry(writeFile(t, dir, "topics.json", content))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := topicregistry.LoadTopicRegistry(filepath.Join(dir, "nao-existe.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"queue/core/domain/enum"
+	"queue/core/domain/interfaces"
+	"queue/core/domain/types"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "queue"
+
+// Propagator lê e grava o contexto no formato W3C (traceparent, tracestate e baggage),
+// tanto nos cabeçalhos HTTP quanto nos atributos das mensagens.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Setup instala o TracerProvider global com o exportador configurado e devolve a função que envia os
+// spans pendentes no desligamento. Com o exportador none os spans não são gravados, mas o traceparent
+// recebido continua sendo repassado às mensagens e às chamadas HTTP.
+func Setup(ctx context.Context, cfg types.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(Propagator)
+	exporter, err := newExporter(ctx, cfg.Exporter)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, exporter string) (sdktrace.SpanExporter, error) {
+	switch enum.TracingExporterEnum(exporter) {
+	case "", enum.NoTracingExporter:
+		return nil, nil
+	case enum.StdoutTracingExporter:
+		return stdouttrace.New()
+	case enum.OTLPTracingExporter:
+		return otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("exportador de tracing %q inválido, use none, stdout ou otlp", exporter)
+	}
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject grava o contexto de trace de ctx nos atributos, que não podem ser nil.
+func Inject(ctx context.Context, attributes map[string]string) {
+	Propagator.Inject(ctx, propagation.MapCarrier(attributes))
+}
+
+// Extract devolve ctx com o contexto de trace gravado nos atributos da mensagem, quando houver.
+func Extract(ctx context.Context, attributes map[string]string) context.Context {
+	return Propagator.Extract(ctx, propagation.MapCarrier(attributes))
+}
+
+// RecordError marca o span como falho; erros nil são ignorados.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Middleware abre um span por execução de handler das assinaturas, filho do span da mensagem.
+func Middleware(subscriptionID string, name string, handler interfaces.IEventHandler) interfaces.IEventHandler {
+	return &tracedHandler{subscriptionID: subscriptionID, name: name, inner: handler}
+}
+
+type tracedHandler struct {
+	subscriptionID string
+	name           string
+	inner          interfaces.IEventHandler
+}
+
+func (h *tracedHandler) Supports(topic string) bool {
+	return h.inner.Supports(topic)
+}
+
+func (h *tracedHandler) Handle(ctx context.Context, event *types.Event) error {
+	ctx, span := Tracer().Start(ctx, "handler "+h.name, trace.WithAttributes(
+		semconv.MessagingDestinationSubscriptionName(h.subscriptionID),
+		attribute.String("queue.handler", h.name),
+		attribute.String("queue.event.id", event.ID),
+	))
+	defer span.End()
+	err := h.inner.Handle(ctx, event)
+	RecordError(span, err)
+	return err
+}
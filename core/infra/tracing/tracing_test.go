@@ -0,0 +1,90 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"queue/core/domain/types"
+	"queue/core/infra/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeHandler struct {
+	err  error
+	span trace.SpanContext
+}
+
+func (h *fakeHandler) Supports(topic string) bool { return topic == "t" }
+
+func (h *fakeHandler) Handle(ctx context.Context, event *types.Event) error {
+	h.span = trace.SpanContextFromContext(ctx)
+	return h.err
+}
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestInjectExtract(t *testing.T) {
+	newRecorder(t)
+	ctx, span := tracing.Tracer().Start(context.Background(), "publish")
+	defer span.End()
+
+	attributes := map[string]string{}
+	tracing.Inject(ctx, attributes)
+	assert.Contains(t, attributes, "traceparent")
+
+	extracted := trace.SpanContextFromContext(tracing.Extract(context.Background(), attributes))
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.True(t, extracted.IsRemote())
+}
+
+func TestExtract_SemContexto(t *testing.T) {
+	ctx := tracing.Extract(context.Background(), nil)
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	for _, exporter := range []string{"", "none", "stdout"} {
+		shutdown, err := tracing.Setup(context.Background(), types.TracingConfig{Exporter: exporter, ServiceName: "queue", SampleRatio: 1})
+		require.NoError(t, err, exporter)
+		assert.NoError(t, shutdown(context.Background()), exporter)
+	}
+
+	_, err := tracing.Setup(context.Background(), types.TracingConfig{Exporter: "jaeger"})
+	assert.ErrorContains(t, err, `"jaeger"`)
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := newRecorder(t)
+	inner := &fakeHandler{err: errors.New("falhou")}
+	handler := tracing.Middleware("sub", "audit", inner)
+	assert.True(t, handler.Supports("t"))
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "process sub")
+	err := handler.Handle(ctx, &types.Event{ID: "1", Topic: "t"})
+	parent.End()
+	assert.EqualError(t, err, "falhou")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "handler audit", span.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, span.SpanContext(), inner.span)
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
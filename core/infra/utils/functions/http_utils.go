@@ -9,6 +9,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"queue/core/infra/logging"
+	"queue/core/infra/tracing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type IHttpClient interface {
@@ -34,17 +41,53 @@ func (e *HttpException) Error() string {
 	return fmt.Sprintf("status %d: %s", e.Status, e.Message)
 }
 
+// ResponseDecodeError indica que o servidor respondeu com sucesso, mas o corpo não pôde ser lido ou
+// decodificado. A requisição já foi aceita, então repeti-la pode duplicar o seu efeito.
+type ResponseDecodeError struct {
+	Status int
+	Err    error
+}
+
+func (e *ResponseDecodeError) Error() string {
+	return fmt.Sprintf("status %d: erro ao ler resposta: %v", e.Status, e.Err)
+}
+
+func (e *ResponseDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Send executa a requisição em um span de cliente e repassa o contexto de trace de cfg.Ctx
+// no cabeçalho traceparent, para que o destino continue o mesmo trace.
 func Send[T any](client IHttpClient, cfg RequestConfig) (*T, error) {
+	ctx := cfg.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := tracing.Tracer().Start(ctx, cfg.Method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(cfg.Method), semconv.URLFull(cfg.Url)))
+	defer span.End()
+	cfg.Ctx = ctx
+
 	req, err := buildRequest(cfg)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return returnError[T](cfg.ShowError, "Erro ao criar requisição", err, http.StatusInternalServerError)
 	}
+	tracing.Propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if id := logging.RequestID(ctx); id != "" && req.Header.Get(logging.RequestIDHeader) == "" {
+		req.Header.Set(logging.RequestIDHeader, id)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return returnError[T](cfg.ShowError, "Erro ao executar requisição", err, http.StatusInternalServerError)
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
 
 	return handleResponse[T](resp, cfg.ShowError)
 }
@@ -108,6 +151,9 @@ func allowsBody(method string) bool {
 
 func handleResponse[T any](resp *http.Response, showError bool) (*T, error) {
 	body, err := io.ReadAll(resp.Body)
+	if err != nil && resp.StatusCode < 400 {
+		return returnDecodeError[T](showError, resp.StatusCode, err)
+	}
 	if err != nil {
 		return returnError[T](showError, "Erro ao ler resposta", err, http.StatusInternalServerError)
 	}
@@ -120,7 +166,7 @@ func handleResponse[T any](resp *http.Response, showError bool) (*T, error) {
 	var result T
 	if len(body) > 0 {
 		if err := json.Unmarshal(body, &result); err != nil {
-			return returnError[T](showError, "Erro ao decodificar JSON", err, http.StatusInternalServerError)
+			return returnDecodeError[T](showError, resp.StatusCode, fmt.Errorf("Erro ao decodificar JSON: %w", err))
 		}
 	}
 	return &result, nil
@@ -151,6 +197,13 @@ func returnError[T any](showError bool, msg string, err error, status int) (*T,
 	return nil, &HttpException{Message: msg, Status: status}
 }
 
+func returnDecodeError[T any](showError bool, status int, err error) (*T, error) {
+	if !showError {
+		return nil, nil
+	}
+	return nil, &ResponseDecodeError{Status: status, Err: err}
+}
+
 func CreateBasicAuthHeader(username, password string) map[string]string {
 	credentials := fmt.Sprintf("%s:%s", username, password)
 	encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
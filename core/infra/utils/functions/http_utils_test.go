@@ -2,12 +2,17 @@ package functions_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"queue/core/infra/logging"
 	"queue/core/infra/utils/functions"
 	"reflect"
+	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type mockClient struct {
@@ -85,6 +90,61 @@ func TestSend_Success_NoBody(t *testing.T) {
 	}
 }
 
+func TestSend_PropagaTraceparent(t *testing.T) {
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	var header string
+	client := &mockClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			header = req.Header.Get("traceparent")
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			}, nil
+		},
+	}
+	type empty struct{}
+	cfg := functions.RequestConfig{
+		Ctx:    trace.ContextWithSpanContext(context.Background(), parent),
+		Method: "POST",
+		Url:    "http://example.com",
+	}
+	if _, err := functions.Send[empty](client, cfg); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(header, parent.TraceID().String()) {
+		t.Errorf("traceparent should carry trace id, got %q", header)
+	}
+}
+
+func TestSend_PropagaRequestID(t *testing.T) {
+	var header string
+	client := &mockClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			header = req.Header.Get("X-Request-ID")
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewBuffer(nil)),
+			}, nil
+		},
+	}
+	type empty struct{}
+	cfg := functions.RequestConfig{
+		Ctx:    logging.WithRequestID(context.Background(), "req-1"),
+		Method: "POST",
+		Url:    "http://example.com",
+	}
+	if _, err := functions.Send[empty](client, cfg); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if header != "req-1" {
+		t.Errorf("X-Request-ID should be forwarded, got %q", header)
+	}
+}
+
 func TestSend_buildRequestError(t *testing.T) {
 	cfg := functions.RequestConfig{
 		Method:    "POST",
@@ -194,9 +254,13 @@ func TestSend_ReadBodyError(t *testing.T) {
 	if res != nil {
 		t.Error("res should be nil")
 	}
+	var decodeErr *functions.ResponseDecodeError
+	if err == nil || !errors.As(err, &decodeErr) || decodeErr.Status != 200 {
+		t.Errorf("expected ResponseDecodeError with status 200, got %v", err)
+	}
 	var httpErr *functions.HttpException
-	if err == nil || !errors.As(err, &httpErr) {
-		t.Error("expected HttpException")
+	if errors.As(err, &httpErr) {
+		t.Error("a 2xx response must not be reported as HttpException")
 	}
 }
 
@@ -219,9 +283,9 @@ func TestSend_JSONDecodeError(t *testing.T) {
 	if res != nil {
 		t.Error("res should be nil")
 	}
-	var httpErr *functions.HttpException
-	if err == nil || !errors.As(err, &httpErr) {
-		t.Error("expected HttpException")
+	var decodeErr *functions.ResponseDecodeError
+	if err == nil || !errors.As(err, &decodeErr) || decodeErr.Status != 200 {
+		t.Errorf("expected ResponseDecodeError with status 200, got %v", err)
 	}
 }
 